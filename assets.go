@@ -0,0 +1,240 @@
+package main
+
+import (
+	"bytes"
+	"cmp"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/andybalholm/brotli"
+)
+
+// ── embedded assets ───────────────────────────────────────────────────────────
+//
+// Every embedded file is served from an asset that is compressed once at
+// startup. Responses carry a strong ETag derived from assetHash, so
+// revalidations (the page itself is no-cache) come back as 304 without a
+// body, and the WAV samples support Range requests.
+
+// asset is one embedded file with its precompressed variants. A variant is
+// nil when it would not be smaller than the original.
+type asset struct {
+	contentType  string
+	cacheControl string
+	hash         string
+	identity     []byte
+	gzip         []byte
+	br           []byte
+}
+
+// compression holds the gzip and brotli levels of an asset; gzip.NoCompression
+// or a negative brotli level skips that variant. On PCM audio and the
+// favicon's bitmaps, brotli saves no more than gzip and the highest levels
+// only cost startup time, so they get plain gzip. Fonts are WOFF2 and the app
+// icons PNG, which are compressed already.
+type compression struct{ gzip, brotli int }
+
+var (
+	textCompression   = compression{gzip.BestCompression, brotli.BestCompression}
+	binaryCompression = compression{gzip.DefaultCompression, -1}
+	audioCompression  = binaryCompression
+	storedCompression = compression{gzip.NoCompression, -1}
+)
+
+func newAsset(data []byte, contentType, cacheControl string, level compression) *asset {
+	a := &asset{
+		contentType:  contentType,
+		cacheControl: cacheControl,
+		hash:         assetHash(data),
+		identity:     data,
+	}
+
+	if level.gzip != gzip.NoCompression {
+		var gz bytes.Buffer
+		zw, _ := gzip.NewWriterLevel(&gz, level.gzip)
+		zw.Write(data)
+		zw.Close()
+		if gz.Len() < len(data) {
+			a.gzip = gz.Bytes()
+		}
+	}
+
+	if level.brotli < 0 {
+		return a
+	}
+	var br bytes.Buffer
+	bw := brotli.NewWriterLevel(&br, level.brotli)
+	bw.Write(data)
+	bw.Close()
+	if br.Len() < len(data) {
+		a.br = br.Bytes()
+	}
+	return a
+}
+
+// loadSampleAssets builds the assets of all .wav files in the directory dir
+// of fsys, keyed by file name. The files are compressed in parallel to keep
+// startup short.
+func loadSampleAssets(fsys fs.FS, dir string) (map[string]*asset, error) {
+	names, err := fs.Glob(fsys, path.Join(dir, "*.wav"))
+	if err != nil {
+		return nil, err
+	}
+	assets := make(map[string]*asset, len(names))
+	var (
+		mu       sync.Mutex
+		wg       sync.WaitGroup
+		firstErr error
+	)
+	sem := make(chan struct{}, 8)
+	for _, name := range names {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer func() { <-sem; wg.Done() }()
+			data, err := fs.ReadFile(fsys, name)
+			if err != nil {
+				mu.Lock()
+				firstErr = cmp.Or(firstErr, err)
+				mu.Unlock()
+				return
+			}
+			a := newAsset(data, "audio/wav", "public, max-age=604800", audioCompression)
+			mu.Lock()
+			assets[path.Base(name)] = a
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+	return assets, firstErr
+}
+
+// siteBuild is the production page from tools/precompile-jsx: the page with
+// its JSX precompiled, and content-hashed scripts, stylesheets and vendored
+// fonts below static/.
+type siteBuild struct {
+	page     []byte
+	manifest map[string]string // name used by the page → hashed path
+	files    map[string]*asset // by path below the build directory
+}
+
+// buildTypes are the file types served from a build's static/ directory,
+// with their content types and compression.
+var buildTypes = map[string]struct {
+	contentType string
+	level       compression
+}{
+	".js":    {"application/javascript; charset=utf-8", textCompression},
+	".css":   {"text/css; charset=utf-8", textCompression},
+	".woff2": {"font/woff2", storedCompression},
+}
+
+// loadBuild loads the build in the directory dir of fsys, or returns nil if
+// dir holds no build manifest. Hashed names never change content, so their
+// files may be cached for good.
+func loadBuild(fsys fs.FS, dir string) (*siteBuild, error) {
+	data, err := fs.ReadFile(fsys, path.Join(dir, "manifest.json"))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	b := &siteBuild{files: map[string]*asset{}}
+	if err := json.Unmarshal(data, &b.manifest); err != nil {
+		return nil, fmt.Errorf("build manifest: %w", err)
+	}
+	if b.page, err = fs.ReadFile(fsys, path.Join(dir, "Fremorizer.html")); err != nil {
+		return nil, err
+	}
+	err = fs.WalkDir(fsys, path.Join(dir, "static"), func(name string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		t, ok := buildTypes[path.Ext(name)]
+		if !ok {
+			return nil
+		}
+		data, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return err
+		}
+		rel := strings.TrimPrefix(name, dir+"/")
+		b.files[rel] = newAsset(data, t.contentType, "public, max-age=31536000, immutable", t.level)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	for name, hashed := range b.manifest {
+		if b.files[hashed] == nil {
+			return nil, fmt.Errorf("build manifest: %s maps to missing file %s", name, hashed)
+		}
+	}
+	return b, nil
+}
+
+// serve writes the asset, honouring Accept-Encoding, If-None-Match and Range.
+// Range requests always get the uncompressed file, which is what audio
+// elements and download resumption expect.
+func (a *asset) serve(w http.ResponseWriter, r *http.Request) {
+	h := w.Header()
+	h.Set("Content-Type", a.contentType)
+	h.Set("Cache-Control", a.cacheControl)
+	if a.gzip != nil || a.br != nil {
+		h.Add("Vary", "Accept-Encoding")
+	}
+
+	body, etag := a.identity, a.hash
+	if r.Header.Get("Range") == "" {
+		accept := r.Header.Get("Accept-Encoding")
+		switch {
+		case a.br != nil && acceptsEncoding(accept, "br"):
+			body, etag = a.br, a.hash+"-br"
+			h.Set("Content-Encoding", "br")
+		case a.gzip != nil && acceptsEncoding(accept, "gzip"):
+			body, etag = a.gzip, a.hash+"-gz"
+			h.Set("Content-Encoding", "gzip")
+		}
+	}
+	// Each encoding is a different representation and needs its own
+	// strong ETag.
+	h.Set("ETag", `"`+etag+`"`)
+	http.ServeContent(w, r, "", time.Time{}, bytes.NewReader(body))
+}
+
+// acceptsEncoding reports whether an Accept-Encoding header value allows
+// coding: it is listed without q=0, or not listed and "*" is.
+func acceptsEncoding(header, coding string) bool {
+	explicit, star := -1.0, -1.0
+	for _, part := range strings.Split(header, ",") {
+		name, params, _ := strings.Cut(part, ";")
+		name = strings.TrimSpace(name)
+		q := 1.0
+		for _, p := range strings.Split(params, ";") {
+			k, v, ok := strings.Cut(strings.TrimSpace(p), "=")
+			if ok && strings.EqualFold(k, "q") {
+				q, _ = strconv.ParseFloat(strings.TrimSpace(v), 64)
+			}
+		}
+		switch {
+		case strings.EqualFold(name, coding):
+			explicit = q
+		case name == "*":
+			star = q
+		}
+	}
+	if explicit >= 0 {
+		return explicit > 0
+	}
+	return star > 0
+}
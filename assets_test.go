@@ -0,0 +1,175 @@
+package main
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestAcceptsEncoding(t *testing.T) {
+	tests := []struct {
+		header, coding string
+		want           bool
+	}{
+		{"gzip, deflate, br", "br", true},
+		{"gzip, deflate, br", "gzip", true},
+		{"gzip", "br", false},
+		{"", "gzip", false},
+		{"br;q=0, gzip", "br", false},
+		{"br; q=0.5", "br", true},
+		{"BR", "br", true},
+		{"*", "br", true},
+		{"*;q=0", "gzip", false},
+		{"*, gzip;q=0", "gzip", false},
+		{"br;q=0, *", "br", false},
+		{"identity", "gzip", false},
+	}
+	for _, tt := range tests {
+		if got := acceptsEncoding(tt.header, tt.coding); got != tt.want {
+			t.Errorf("acceptsEncoding(%q, %q) = %v, want %v", tt.header, tt.coding, got, tt.want)
+		}
+	}
+}
+
+func TestAssetServe(t *testing.T) {
+	data := []byte(strings.Repeat("fremorizer fretboard ", 200))
+	a := newAsset(data, "text/plain; charset=utf-8", "no-cache", textCompression)
+	if a.br == nil || a.gzip == nil {
+		t.Fatal("text asset has no compressed variants")
+	}
+
+	tests := []struct {
+		name     string
+		headers  map[string]string
+		status   int
+		encoding string
+		body     []byte
+	}{
+		{"brotli preferred", map[string]string{"Accept-Encoding": "gzip, br"}, http.StatusOK, "br", a.br},
+		{"gzip", map[string]string{"Accept-Encoding": "gzip"}, http.StatusOK, "gzip", a.gzip},
+		{"brotli refused", map[string]string{"Accept-Encoding": "br;q=0, gzip"}, http.StatusOK, "gzip", a.gzip},
+		{"identity", nil, http.StatusOK, "", data},
+		{"everything refused", map[string]string{"Accept-Encoding": "br;q=0, gzip;q=0"}, http.StatusOK, "", data},
+		{"range", map[string]string{"Accept-Encoding": "gzip, br", "Range": "bytes=0-9"}, http.StatusPartialContent, "", data[:10]},
+		{"revalidation", map[string]string{"If-None-Match": `"` + a.hash + `"`}, http.StatusNotModified, "", nil},
+		{"revalidation, brotli", map[string]string{"Accept-Encoding": "br", "If-None-Match": `"` + a.hash + `-br"`}, http.StatusNotModified, "", nil},
+		{"stale etag", map[string]string{"If-None-Match": `"0000"`}, http.StatusOK, "", data},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest("GET", "/a.txt", nil)
+		for k, v := range tt.headers {
+			req.Header.Set(k, v)
+		}
+		rec := httptest.NewRecorder()
+		a.serve(rec, req)
+		if rec.Code != tt.status {
+			t.Errorf("%s: status %d, want %d", tt.name, rec.Code, tt.status)
+		}
+		if got := rec.Header().Get("Content-Encoding"); got != tt.encoding {
+			t.Errorf("%s: Content-Encoding %q, want %q", tt.name, got, tt.encoding)
+		}
+		if !bytes.Equal(rec.Body.Bytes(), tt.body) {
+			t.Errorf("%s: body of %d bytes, want %d", tt.name, rec.Body.Len(), len(tt.body))
+		}
+		if rec.Header().Get("Vary") != "Accept-Encoding" {
+			t.Errorf("%s: Vary = %q", tt.name, rec.Header().Get("Vary"))
+		}
+	}
+}
+
+func TestBinaryAssetsSkipBrotli(t *testing.T) {
+	favicon := newAsset(faviconBytes, "image/x-icon", "", binaryCompression)
+	if favicon.br != nil {
+		t.Error("favicon has a brotli variant")
+	}
+	icon, err := appIcon(faviconBytes, 192)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a := newAsset(icon, "image/png", "", storedCompression); a.br != nil || a.gzip != nil {
+		t.Error("PNG icon has compressed variants")
+	}
+}
+
+func TestAssetHash(t *testing.T) {
+	// tools/precompile-jsx pins the same value for writeHashed, which
+	// keeps the build's file names and the server's hashes in step.
+	if got := assetHash([]byte("lib.js")); got != "4d4d0cea" {
+		t.Errorf("assetHash = %q, want 4d4d0cea", got)
+	}
+}
+
+func TestLoadBuild(t *testing.T) {
+	page := &fstest.MapFile{Data: []byte(`<script src="app.js"></script>`)}
+	files := fstest.MapFS{
+		"dist/manifest.json":               {Data: []byte(`{"app.js":"static/app.0123abcd.js","styles.css":"static/styles.4567cdef.css"}`)},
+		"dist/Fremorizer.html":             page,
+		"dist/static/app.0123abcd.js":      {Data: []byte("app()")},
+		"dist/static/styles.4567cdef.css":  {Data: []byte("body{}")},
+		"dist/static/vendor/fonts/a.woff2": {Data: []byte("wOF2")},
+		"dist/static/notes.txt":            {Data: []byte("not served")},
+	}
+
+	b, err := loadBuild(files, "dist")
+	if err != nil {
+		t.Fatalf("loadBuild: %v", err)
+	}
+	if string(b.page) != string(page.Data) || b.manifest["app.js"] != "static/app.0123abcd.js" {
+		t.Errorf("page %q, manifest %v", b.page, b.manifest)
+	}
+	want := map[string]string{
+		"static/app.0123abcd.js":      "application/javascript; charset=utf-8",
+		"static/styles.4567cdef.css":  "text/css; charset=utf-8",
+		"static/vendor/fonts/a.woff2": "font/woff2",
+	}
+	if len(b.files) != len(want) {
+		t.Errorf("files = %v, want %d", sortedKeys(b.files), len(want))
+	}
+	for name, ct := range want {
+		if a := b.files[name]; a == nil || a.contentType != ct || !strings.Contains(a.cacheControl, "immutable") {
+			t.Errorf("%s: %+v, want %s and immutable", name, a, ct)
+		}
+	}
+
+	if b, err := loadBuild(fstest.MapFS{"dist/README.md": {}}, "dist"); b != nil || err != nil {
+		t.Errorf("without a manifest: %v, %v; want no build", b, err)
+	}
+
+	broken := func(edit func(fstest.MapFS)) fstest.MapFS {
+		fsys := fstest.MapFS{}
+		for name, f := range files {
+			fsys[name] = f
+		}
+		edit(fsys)
+		return fsys
+	}
+	errTests := []struct {
+		name string
+		fsys fstest.MapFS
+		want string
+	}{
+		{"missing file", broken(func(f fstest.MapFS) { delete(f, "dist/static/app.0123abcd.js") }), "app.js maps to missing file static/app.0123abcd.js"},
+		{"invalid manifest", broken(func(f fstest.MapFS) { f["dist/manifest.json"] = &fstest.MapFile{Data: []byte("{")} }), "build manifest"},
+		{"missing page", broken(func(f fstest.MapFS) { delete(f, "dist/Fremorizer.html") }), "Fremorizer.html"},
+	}
+	for _, tt := range errTests {
+		if _, err := loadBuild(tt.fsys, "dist"); err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: err = %v, want %q", tt.name, err, tt.want)
+		}
+	}
+}
+
+func TestVersionedPage(t *testing.T) {
+	page := []byte(`<link href="styles.css"><script src="lib.js"></script><script src="mylib.js"></script>"lib.js"`)
+	got := versionedPage(page, map[string]string{
+		"lib.js":     "static/lib.4d4d0cea.js",
+		"styles.css": "styles.css?v=12345678",
+	})
+	want := `<link href="styles.css?v=12345678"><script src="static/lib.4d4d0cea.js"></script><script src="mylib.js"></script>"static/lib.4d4d0cea.js"`
+	if string(got) != want {
+		t.Errorf("versionedPage =\n%s\nwant\n%s", got, want)
+	}
+}
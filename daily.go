@@ -0,0 +1,74 @@
+package main
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/funkymcb/fremorizer/game"
+	"github.com/funkymcb/fremorizer/instrument"
+)
+
+// ── daily challenge ───────────────────────────────────────────────────────────
+//
+// The daily challenge is a "guess a random note" game on a fixed setup,
+// seeded with the date, so everyone plays the same sequence on the same day
+// and can compare results by sharing the summary.
+
+const (
+	dailyInstrument = "guitar"
+	dailyFrets      = 12
+)
+
+func dailyTuning() []string { return instrument.DefaultGuitarTuning(6) }
+
+// startDaily starts today's challenge. The fixed setup stands in for the
+// player's own options until the game ends, like a race's does.
+func (m model) startDaily() (tea.Model, tea.Cmd) {
+	m.useInstrument(instrumentConfig{
+		instrType:         dailyInstrument,
+		numStrings:        len(dailyTuning()),
+		tuning:            dailyTuning(),
+		frets:             dailyFrets,
+		fretSetSequential: m.fretSetSequential,
+	})
+
+	// The date is kept for the summary, which may come after midnight.
+	date := time.Now().UTC()
+	next, cmd := m.startGameWithRand("single", rand.New(rand.NewSource(game.DailySeed(date))))
+	nm := next.(model)
+	nm.daily = nm.state == statePlaying
+	nm.dailyDate = date
+	if nm.daily {
+		metrics.gameStarted("daily")
+	}
+	nm.dailySlips = map[[2]int]bool{}
+	return nm, cmd
+}
+
+// dailySummary is the shareable result of the daily challenge of date: a
+// header line plus one row per string (highest first) with a square per fret.
+// Green notes were named on the first try, yellow ones took several attempts
+// and red ones had to be revealed. slips holds the positions ({string, fret})
+// that were answered wrong at least once.
+func dailySummary(date time.Time, inst *instrument.Instrument, slips map[[2]int]bool, elapsed time.Duration, accuracy float64) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Fremorizer daily %s — %s, %.0f%% accuracy\n",
+		date.Format("2006-01-02"), formatDuration(elapsed), accuracy*100)
+	for si, s := range inst.Strings {
+		for fi := 1; fi < len(s.Notes); fi++ { // skip open string
+			switch {
+			case s.Notes[fi].WasMissed:
+				sb.WriteString("🟥")
+			case slips[[2]int{si, fi}]:
+				sb.WriteString("🟨")
+			default:
+				sb.WriteString("🟩")
+			}
+		}
+		sb.WriteString("\n")
+	}
+	return strings.TrimSuffix(sb.String(), "\n")
+}
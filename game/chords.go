@@ -7,6 +7,7 @@ import (
 	"strings"
 
 	"github.com/funkymcb/fremorizer/instrument"
+	"github.com/funkymcb/fremorizer/theory"
 )
 
 // Chord game phases.
@@ -35,88 +36,20 @@ type cagedShape struct {
 
 // allCAGEDShapes returns the 10 CAGED shapes for 6-string standard-tuning guitar.
 // String indices: 0=high E, 1=B, 2=G, 3=D, 4=A, 5=low E.
+// The shapes the web page's chord quiz shares come from the theory package;
+// the minor G- and C-shapes are only played here.
 func allCAGEDShapes() []cagedShape {
 	return []cagedShape{
 		// ── Major shapes ────────────────────────────────────────────────────────
-		{
-			name: "E", isMajor: true, rootString: 5,
-			notes: [6]noteSpec{
-				{0, "1"}, // high E
-				{0, "5"}, // B
-				{1, "3"}, // G
-				{2, "1"}, // D
-				{2, "5"}, // A
-				{0, "1"}, // low E  ← root
-			},
-		},
-		{
-			name: "A", isMajor: true, rootString: 4,
-			notes: [6]noteSpec{
-				{0, "5"}, // high E
-				{2, "3"}, // B
-				{2, "1"}, // G
-				{2, "5"}, // D
-				{0, "1"}, // A  ← root
-				{0, "x"}, // low E  muted
-			},
-		},
-		{
-			name: "G", isMajor: true, rootString: 5,
-			notes: [6]noteSpec{
-				{0, "1"},  // high E
-				{-3, "3"}, // B
-				{-3, "1"}, // G
-				{-3, "5"}, // D
-				{-1, "3"}, // A
-				{0, "1"},  // low E  ← root
-			},
-		},
-		{
-			name: "C", isMajor: true, rootString: 4,
-			notes: [6]noteSpec{
-				{-3, "3"}, // high E
-				{-2, "1"}, // B
-				{-3, "5"}, // G
-				{-1, "3"}, // D
-				{0, "1"},  // A  ← root
-				{0, "x"},  // low E  muted
-			},
-		},
-		{
-			name: "D", isMajor: true, rootString: 3,
-			notes: [6]noteSpec{
-				{2, "3"}, // high E
-				{3, "1"}, // B
-				{2, "5"}, // G
-				{0, "1"}, // D  ← root
-				{0, "x"}, // A  muted
-				{0, "x"}, // low E  muted
-			},
-		},
+		theoryShape(" major", "E"),
+		theoryShape(" major", "A"),
+		theoryShape(" major", "G"),
+		theoryShape(" major", "C"),
+		theoryShape(" major", "D"),
 
 		// ── Minor shapes ────────────────────────────────────────────────────────
-		{
-			name: "E", isMajor: false, rootString: 5,
-			notes: [6]noteSpec{
-				{0, "1"},  // high E
-				{0, "5"},  // B
-				{0, "b3"}, // G  (one fret lower than major's +1)
-				{2, "1"},  // D
-				{2, "5"},  // A
-				{0, "1"},  // low E  ← root
-			},
-		},
-		{
-			name: "A", isMajor: false, rootString: 4,
-			notes: [6]noteSpec{
-				{0, "5"},  // high E
-				{1, "b3"}, // B  (one fret lower than major's +2)
-				{2, "1"},  // G
-				{2, "5"},  // D
-				{0, "1"},  // A  ← root
-				{0, "x"},  // low E  muted
-			},
-		},
+		theoryShape(" minor", "E"),
+		theoryShape(" minor", "A"),
 		{
 			name: "G", isMajor: false, rootString: 5,
 			notes: [6]noteSpec{
@@ -139,18 +72,27 @@ func allCAGEDShapes() []cagedShape {
 				{0, "x"},   // low E  muted
 			},
 		},
-		{
-			name: "D", isMajor: false, rootString: 3,
-			notes: [6]noteSpec{
-				{1, "b3"}, // high E  (one fret lower than major's +2)
-				{3, "1"},  // B
-				{2, "5"},  // G
-				{0, "1"},  // D  ← root
-				{0, "x"},  // A  muted
-				{0, "x"},  // low E  muted
-			},
-		},
+		theoryShape(" minor", "D"),
+	}
+}
+
+// theoryShape converts the theory package's voicing with the given suffix and
+// CAGED shape. Strings the voicing leaves out are muted.
+func theoryShape(suffix, shape string) cagedShape {
+	for _, v := range theory.Voicings {
+		if v.Suffix != suffix || v.Shape != shape {
+			continue
+		}
+		c := cagedShape{name: shape, isMajor: suffix == " major", rootString: v.RootString}
+		for i := range c.notes {
+			c.notes[i] = noteSpec{0, "x"}
+		}
+		for _, t := range v.Tones {
+			c.notes[t.String] = noteSpec{t.Offset, t.Degree}
+		}
+		return c
 	}
+	panic(fmt.Sprintf("theory has no%s %s-shape voicing", suffix, shape))
 }
 
 // chordInterval tracks one interval prompt within the chord identification game.
@@ -175,17 +117,19 @@ type ChordsGame struct {
 	marking         bool   // medium: cursor-marking sub-phase within ChordPhaseIntervals
 	cursorString    int
 	cursorFret      int
+	rng             *rand.Rand
 }
 
 // NewChordsGame creates a chord game for the given instrument (must be 6-string guitar).
-func NewChordsGame(inst *instrument.Instrument, chordsRequired int, difficulty string) *ChordsGame {
+// A nil rng gives a random chord sequence.
+func NewChordsGame(inst *instrument.Instrument, chordsRequired int, difficulty string, rng *rand.Rand) *ChordsGame {
 	if chordsRequired < 1 {
 		chordsRequired = 20
 	}
 	if difficulty == "" {
 		difficulty = "easy"
 	}
-	g := &ChordsGame{inst: inst, chordsRequired: chordsRequired, difficulty: difficulty}
+	g := &ChordsGame{inst: inst, chordsRequired: chordsRequired, difficulty: difficulty, rng: newRand(rng)}
 	g.pickNewChord()
 	return g
 }
@@ -394,7 +338,7 @@ func (g *ChordsGame) pickNewChord() {
 	g.clearChord()
 
 	shapes := allCAGEDShapes()
-	rand.Shuffle(len(shapes), func(i, j int) { shapes[i], shapes[j] = shapes[j], shapes[i] })
+	g.rng.Shuffle(len(shapes), func(i, j int) { shapes[i], shapes[j] = shapes[j], shapes[i] })
 
 	if slices.ContainsFunc(shapes, g.tryApplyShape) {
 		return
@@ -426,7 +370,7 @@ func (g *ChordsGame) tryApplyShape(shape cagedShape) bool {
 
 	rootFret := minRoot
 	if maxRoot > minRoot {
-		rootFret = minRoot + rand.Intn(maxRoot-minRoot+1)
+		rootFret = minRoot + g.rng.Intn(maxRoot-minRoot+1)
 	}
 
 	// Apply shape: set Interval on the appropriate Note, Muted on Notes[0] for "x" strings.
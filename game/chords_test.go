@@ -1,6 +1,8 @@
 package game
 
 import (
+	"fmt"
+	"math/rand"
 	"strings"
 	"testing"
 
@@ -73,7 +75,7 @@ func TestParseChordInput(t *testing.T) {
 
 func TestNewChordsGameDefaults(t *testing.T) {
 	inst := newTestGuitar()
-	g := NewChordsGame(inst, 0, "") // 0 chordsRequired → default 20, "" difficulty → "easy"
+	g := NewChordsGame(inst, 0, "", nil) // 0 chordsRequired → default 20, "" difficulty → "easy"
 	if g.chordsRequired != 20 {
 		t.Errorf("chordsRequired = %d, want 20", g.chordsRequired)
 	}
@@ -83,7 +85,7 @@ func TestNewChordsGameDefaults(t *testing.T) {
 }
 
 func TestChordsGameProgress(t *testing.T) {
-	g := NewChordsGame(newTestGuitar(), 5, "easy")
+	g := NewChordsGame(newTestGuitar(), 5, "easy", nil)
 	done, total := g.Progress()
 	if done != 0 || total != 5 {
 		t.Errorf("Progress() = (%d, %d), want (0, 5)", done, total)
@@ -91,7 +93,7 @@ func TestChordsGameProgress(t *testing.T) {
 }
 
 func TestChordsGameIsGameOverAtStart(t *testing.T) {
-	g := NewChordsGame(newTestGuitar(), 3, "easy")
+	g := NewChordsGame(newTestGuitar(), 3, "easy", nil)
 	if g.IsGameOver() {
 		t.Error("game should not be over at start")
 	}
@@ -100,7 +102,7 @@ func TestChordsGameIsGameOverAtStart(t *testing.T) {
 // ── ChordDisplayName ──────────────────────────────────────────────────────────
 
 func TestChordDisplayNameMajorNoTrailingM(t *testing.T) {
-	g := NewChordsGame(newTestGuitar(), 1, "easy")
+	g := NewChordsGame(newTestGuitar(), 1, "easy", nil)
 	for range 20 { // run several random chords to increase coverage
 		name := g.ChordDisplayName()
 		if name == "" {
@@ -120,7 +122,7 @@ func TestChordDisplayNameMajorNoTrailingM(t *testing.T) {
 // ── easy mode phase flow ──────────────────────────────────────────────────────
 
 func TestEasyModeFullPhaseFlow(t *testing.T) {
-	g := NewChordsGame(newTestGuitar(), 1, "easy")
+	g := NewChordsGame(newTestGuitar(), 1, "easy", nil)
 
 	if g.Phase() != ChordPhaseNaming {
 		t.Fatalf("expected ChordPhaseNaming at start, got %d", g.Phase())
@@ -232,3 +234,31 @@ func TestMarkingHintInfo(t *testing.T) {
 		t.Errorf("hint after marks: got (%d, %d), want (1, 1)", c, w)
 	}
 }
+
+// ── seeding ───────────────────────────────────────────────────────────────────
+
+// chordSignature describes the current chord: its name and shape position.
+func chordSignature(g *ChordsGame) string {
+	var sb strings.Builder
+	sb.WriteString(g.ChordDisplayName())
+	for si, s := range g.inst.Strings {
+		for fi, n := range s.Notes {
+			if n.Interval != "" {
+				fmt.Fprintf(&sb, " %d:%d", si, fi)
+			}
+		}
+	}
+	return sb.String()
+}
+
+func TestChordsSeededSequence(t *testing.T) {
+	a := NewChordsGame(newTestGuitar(), 10, "easy", rand.New(rand.NewSource(7)))
+	b := NewChordsGame(newTestGuitar(), 10, "easy", rand.New(rand.NewSource(7)))
+	for i := 0; i < 10; i++ {
+		if sa, sb := chordSignature(a), chordSignature(b); sa != sb {
+			t.Fatalf("chord %d: %q vs %q with the same seed", i, sa, sb)
+		}
+		a.pickNewChord()
+		b.pickNewChord()
+	}
+}
@@ -0,0 +1,10 @@
+package game
+
+import "time"
+
+// DailySeed returns the random seed of the daily challenge on t's calendar
+// day in UTC, so every player gets the same questions on the same date.
+func DailySeed(t time.Time) int64 {
+	y, m, d := t.UTC().Date()
+	return int64(y*10000 + int(m)*100 + d)
+}
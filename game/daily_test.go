@@ -0,0 +1,56 @@
+package game
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestDailySeed(t *testing.T) {
+	morning := time.Date(2026, 3, 14, 0, 5, 0, 0, time.UTC)
+	evening := time.Date(2026, 3, 14, 23, 55, 0, 0, time.UTC)
+	if DailySeed(morning) != DailySeed(evening) {
+		t.Error("same day should give the same seed")
+	}
+	if DailySeed(morning) == DailySeed(morning.AddDate(0, 0, 1)) {
+		t.Error("different days should give different seeds")
+	}
+	// The day is the UTC day, wherever the player is.
+	tokyo := time.FixedZone("JST", 9*60*60)
+	if DailySeed(time.Date(2026, 3, 15, 8, 0, 0, 0, tokyo)) != DailySeed(morning) {
+		t.Error("seed should follow the UTC date")
+	}
+}
+
+// dailySequence answers every question of a daily single-note game correctly
+// and returns the positions asked.
+func dailySequence(day time.Time) []notePos {
+	g := NewSingleNoteGame(newTestGuitar(), rand.New(rand.NewSource(DailySeed(day))))
+	var seq []notePos
+	for !g.IsGameOver() {
+		seq = append(seq, g.cur)
+		g.RevealNote(true)
+		_ = g.Next()
+	}
+	return seq
+}
+
+func TestDailyChallengeSameSequenceForEveryone(t *testing.T) {
+	day := time.Date(2026, 3, 14, 12, 0, 0, 0, time.UTC)
+	a, b := dailySequence(day), dailySequence(day)
+	if !slices.Equal(a, b) {
+		t.Error("two players on the same day got different sequences")
+	}
+	if slices.Equal(a, dailySequence(day.AddDate(0, 0, 1))) {
+		t.Error("consecutive days got the same sequence")
+	}
+}
+
+func TestNoteListSeededOrder(t *testing.T) {
+	a := NewNoteListGame("sharps", rand.New(rand.NewSource(7)))
+	b := NewNoteListGame("sharps", rand.New(rand.NewSource(7)))
+	if !slices.Equal(a.Notes(), b.Notes()) {
+		t.Errorf("seeded note lists differ: %v vs %v", a.Notes(), b.Notes())
+	}
+}
@@ -5,11 +5,7 @@ import (
 	"strings"
 
 	"github.com/funkymcb/fremorizer/instrument"
-)
-
-var (
-	majorScaleIntervals = []int{0, 2, 4, 5, 7, 9, 11}
-	minorScaleIntervals = []int{0, 2, 3, 5, 7, 8, 10}
+	"github.com/funkymcb/fremorizer/theory"
 )
 
 // FreeLearningGame implements a free-exploration mode (mode 4).
@@ -115,18 +111,15 @@ func (g *FreeLearningGame) RevealFret() {
 // 6-fret window around the cursor.
 func (g *FreeLearningGame) RevealScale(minor bool) {
 	rootName := g.inst.Strings[g.cursorString].Notes[g.cursorFret].Name
-	rootSemitone := instrument.NoteToSemitone(rootName)
 
-	intervals := majorScaleIntervals
 	scaleName := "major"
 	if minor {
-		intervals = minorScaleIntervals
 		scaleName = "minor"
 	}
 
 	semitones := map[int]bool{}
-	for _, iv := range intervals {
-		semitones[(rootSemitone+iv)%12] = true
+	for _, n := range instrument.ScaleNotes(rootName, theory.ScaleIntervals(scaleName)) {
+		semitones[instrument.NoteToSemitone(n)] = true
 	}
 
 	// Fret window: one fret back, four frets forward.
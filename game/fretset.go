@@ -24,10 +24,13 @@ type FretSetGameImpl struct {
 	cursorFret        int
 	sequential        bool
 	fretSetsCompleted int
+	rng               *rand.Rand
 }
 
-func NewFretSetGame(inst *instrument.Instrument, sequential bool) *FretSetGameImpl {
-	g := &FretSetGameImpl{inst: inst, sequential: sequential}
+// NewFretSetGame creates a mode-2 game. Games created with generators seeded
+// alike pick the same fret sets and target notes; rng may be nil.
+func NewFretSetGame(inst *instrument.Instrument, sequential bool, rng *rand.Rand) *FretSetGameImpl {
+	g := &FretSetGameImpl{inst: inst, sequential: sequential, rng: newRand(rng)}
 	g.initFretSet()
 	g.pickNextNote()
 	return g
@@ -268,7 +271,7 @@ func (g *FretSetGameImpl) pickNextNote() {
 		}
 	}
 	if len(candidates) > 0 {
-		g.targetNote = candidates[rand.Intn(len(candidates))]
+		g.targetNote = candidates[g.rng.Intn(len(candidates))]
 	}
 	g.cursorString = 0
 	g.cursorFret = g.fretStart
@@ -279,5 +282,5 @@ func (g *FretSetGameImpl) randomStart() int {
 	if max < 1 {
 		max = 1
 	}
-	return rand.Intn(max) + 1
+	return g.rng.Intn(max) + 1
 }
@@ -1,13 +1,14 @@
 package game
 
 import (
+	"math/rand"
 	"testing"
 )
 
 // ── MoveCursor ────────────────────────────────────────────────────────────────
 
 func TestFretSetMoveCursorStringWrapping(t *testing.T) {
-	g := NewFretSetGame(newTestGuitar(), true)
+	g := NewFretSetGame(newTestGuitar(), true, nil)
 	numStrings := len(g.inst.Strings)
 
 	// Move up past string 0 → wraps to last string.
@@ -26,7 +27,7 @@ func TestFretSetMoveCursorStringWrapping(t *testing.T) {
 }
 
 func TestFretSetMoveCursorFretWrapping(t *testing.T) {
-	g := NewFretSetGame(newTestGuitar(), true)
+	g := NewFretSetGame(newTestGuitar(), true, nil)
 	// Sequential mode: fretStart=1, fretEnd=3, window width=3.
 
 	// At fretStart, move left → wraps to fretEnd.
@@ -47,14 +48,14 @@ func TestFretSetMoveCursorFretWrapping(t *testing.T) {
 // ── IsComplete ────────────────────────────────────────────────────────────────
 
 func TestFretSetIsCompleteNotMarked(t *testing.T) {
-	g := NewFretSetGame(newTestGuitar(), true)
+	g := NewFretSetGame(newTestGuitar(), true, nil)
 	if g.IsComplete() {
 		t.Error("IsComplete() should be false before any marks")
 	}
 }
 
 func TestFretSetIsCompleteCorrectMarks(t *testing.T) {
-	g := NewFretSetGame(newTestGuitar(), true)
+	g := NewFretSetGame(newTestGuitar(), true, nil)
 
 	// Mark all positions of the target note within the fret set.
 	for si := range g.inst.Strings {
@@ -70,7 +71,7 @@ func TestFretSetIsCompleteCorrectMarks(t *testing.T) {
 }
 
 func TestFretSetIsCompleteWrongMark(t *testing.T) {
-	g := NewFretSetGame(newTestGuitar(), true)
+	g := NewFretSetGame(newTestGuitar(), true, nil)
 
 	// Mark all correct positions.
 	for si := range g.inst.Strings {
@@ -107,7 +108,7 @@ func TestFretSetIsCompleteWrongMark(t *testing.T) {
 // ── HintInfo ──────────────────────────────────────────────────────────────────
 
 func TestFretSetHintInfoEmpty(t *testing.T) {
-	g := NewFretSetGame(newTestGuitar(), true)
+	g := NewFretSetGame(newTestGuitar(), true, nil)
 	c, w := g.HintInfo()
 	if c != 0 || w != 0 {
 		t.Errorf("HintInfo() before any marks = (%d, %d), want (0, 0)", c, w)
@@ -115,7 +116,7 @@ func TestFretSetHintInfoEmpty(t *testing.T) {
 }
 
 func TestFretSetHintInfoCounting(t *testing.T) {
-	g := NewFretSetGame(newTestGuitar(), true)
+	g := NewFretSetGame(newTestGuitar(), true, nil)
 
 	var correctSI, correctF, wrongSI, wrongF int
 	foundCorrect, foundWrong := false, false
@@ -147,7 +148,7 @@ func TestFretSetHintInfoCounting(t *testing.T) {
 // ── ToggleMark bounds ─────────────────────────────────────────────────────────
 
 func TestToggleMarkOutOfBounds(t *testing.T) {
-	g := NewFretSetGame(newTestGuitar(), true)
+	g := NewFretSetGame(newTestGuitar(), true, nil)
 
 	// Fret outside fret set — should be a no-op (no panic).
 	g.ToggleMark(0, 0)                             // fret 0 (open string, below fretStart=1)
@@ -159,7 +160,7 @@ func TestToggleMarkOutOfBounds(t *testing.T) {
 // ── Progress ──────────────────────────────────────────────────────────────────
 
 func TestFretSetProgress(t *testing.T) {
-	g := NewFretSetGame(newTestGuitar(), true)
+	g := NewFretSetGame(newTestGuitar(), true, nil)
 	setC, setT, boardC, boardT := g.Progress()
 
 	if setT == 0 {
@@ -175,3 +176,20 @@ func TestFretSetProgress(t *testing.T) {
 		t.Errorf("setTotal (%d) should not exceed boardTotal (%d)", setT, boardT)
 	}
 }
+
+// ── seeding ───────────────────────────────────────────────────────────────────
+
+func TestFretSetSeededSequence(t *testing.T) {
+	a := NewFretSetGame(newTestGuitar(), false, rand.New(rand.NewSource(7)))
+	b := NewFretSetGame(newTestGuitar(), false, rand.New(rand.NewSource(7)))
+	for i := 0; i < 15; i++ {
+		aStart, _ := a.GetFretSetBounds()
+		bStart, _ := b.GetFretSetBounds()
+		if aStart != bStart || a.GetTargetNote() != b.GetTargetNote() {
+			t.Fatalf("step %d: set %d/%s vs %d/%s with the same seed",
+				i, aStart, a.GetTargetNote(), bStart, b.GetTargetNote())
+		}
+		_ = a.Next()
+		_ = b.Next()
+	}
+}
@@ -2,6 +2,8 @@ package game
 
 import (
 	"fmt"
+	"math/rand"
+	"time"
 
 	"github.com/funkymcb/fremorizer/instrument"
 )
@@ -29,13 +31,15 @@ type FretSetGame interface {
 
 // New creates a Game for the given mode and instrument.
 // opts is an optional map of mode-specific settings (e.g. "sequential": true).
+// A *rand.Rand under "rand" makes the question sequence reproducible.
 func New(mode string, inst *instrument.Instrument, opts map[string]any) (Game, error) {
+	rng, _ := opts["rand"].(*rand.Rand)
 	switch mode {
 	case "single":
-		return NewSingleNoteGame(inst), nil
+		return NewSingleNoteGame(inst, rng), nil
 	case "fretset":
 		sequential, _ := opts["sequential"].(bool)
-		return NewFretSetGame(inst, sequential), nil
+		return NewFretSetGame(inst, sequential, rng), nil
 	case "chords":
 		difficulty, _ := opts["difficulty"].(string)
 		if difficulty != "easy" && difficulty != "medium" {
@@ -48,13 +52,21 @@ func New(mode string, inst *instrument.Instrument, opts map[string]any) (Game, e
 			return nil, fmt.Errorf("chord mode requires at least 6 strings")
 		}
 		chordCount, _ := opts["chordCount"].(int)
-		return NewChordsGame(inst, chordCount, difficulty), nil
+		return NewChordsGame(inst, chordCount, difficulty, rng), nil
 	case "freelearning":
 		return NewFreeLearningGame(inst), nil
 	case "notelist":
 		accidentals, _ := opts["accidentals"].(string)
-		return NewNoteListGame(accidentals), nil
+		return NewNoteListGame(accidentals, rng), nil
 	default:
 		return nil, fmt.Errorf("unknown game mode: %s", mode)
 	}
 }
+
+// newRand returns rng, or a time-seeded generator if rng is nil.
+func newRand(rng *rand.Rand) *rand.Rand {
+	if rng != nil {
+		return rng
+	}
+	return rand.New(rand.NewSource(time.Now().UnixNano()))
+}
@@ -0,0 +1,325 @@
+package game
+
+import (
+	"os"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/dop251/goja"
+
+	"github.com/funkymcb/fremorizer/instrument"
+	"github.com/funkymcb/fremorizer/theory"
+)
+
+// Parity tests: html/lib.js runs in an embedded JS interpreter and must agree
+// with the Go packages on everything both front ends check.
+
+// libJS is the web page's window.Fremorizer API.
+type libJS struct {
+	t   *testing.T
+	vm  *goja.Runtime
+	api *goja.Object
+}
+
+func loadLibJS(t *testing.T) *libJS {
+	t.Helper()
+	src, err := os.ReadFile("../html/lib.js")
+	if err != nil {
+		t.Fatal(err)
+	}
+	vm := goja.New()
+	if _, err := vm.RunScript("lib.js", string(src)); err != nil {
+		t.Fatalf("lib.js: %v", err)
+	}
+	api := vm.Get("Fremorizer")
+	if api == nil || goja.IsUndefined(api) {
+		t.Fatal("lib.js did not define window.Fremorizer")
+	}
+	return &libJS{t, vm, api.ToObject(vm)}
+}
+
+// get returns an API value exported to Go.
+func (js *libJS) get(name string) any {
+	return js.api.Get(name).Export()
+}
+
+// call calls an API function and returns its result exported to Go.
+func (js *libJS) call(name string, args ...any) any {
+	js.t.Helper()
+	fn, ok := goja.AssertFunction(js.api.Get(name))
+	if !ok {
+		js.t.Fatalf("lib.js: %s is not a function", name)
+	}
+	vals := make([]goja.Value, len(args))
+	for i, a := range args {
+		vals[i] = js.vm.ToValue(a)
+	}
+	res, err := fn(goja.Undefined(), vals...)
+	if err != nil {
+		js.t.Fatalf("lib.js: %s%q: %v", name, args, err)
+	}
+	return res.Export()
+}
+
+func exportedStrings(v any) []string {
+	list, _ := v.([]any)
+	out := make([]string, len(list))
+	for i, s := range list {
+		out[i], _ = s.(string)
+	}
+	return out
+}
+
+// sharpName returns the sharp spelling of a canonical Go note name ("C#/Db"
+// → "C#"), which is how lib.js names notes.
+func sharpName(name string) string {
+	sharp, _, _ := strings.Cut(name, "/")
+	return sharp
+}
+
+// libJSInstruments builds the Go instrument for each lib.js tuning.
+var libJSInstruments = map[string]func(strings int) (*instrument.Instrument, error){
+	"guitar": func(n int) (*instrument.Instrument, error) {
+		return instrument.NewGuitar(instrument.DefaultGuitarTuning(n), 24)
+	},
+	"bass": func(n int) (*instrument.Instrument, error) {
+		return instrument.NewBass(instrument.DefaultBassTuning(n), 24)
+	},
+	"ukulele": func(int) (*instrument.Instrument, error) {
+		return instrument.NewUkulele(instrument.DefaultUkuleleTuning(), 24)
+	},
+}
+
+// goDefaultStrings are the string counts of every Go default tuning.
+var goDefaultStrings = map[string][]int{
+	"guitar":  {6, 7, 8},
+	"bass":    {4, 5, 6},
+	"ukulele": {4},
+}
+
+// weblessInstruments are the Go instruments the page does not have, so lib.js
+// has no tuning to check them against.
+var weblessInstruments = []string{"ukulele"}
+
+func TestLibJSNoteAtParity(t *testing.T) {
+	js := loadLibJS(t)
+	tunings, _ := js.get("TUNINGS").(map[string]any)
+	if len(tunings) == 0 {
+		t.Fatal("lib.js: no TUNINGS")
+	}
+	for name, tuning := range tunings {
+		strs := exportedStrings(tuning)
+		build, ok := libJSInstruments[name]
+		if !ok {
+			t.Errorf("lib.js tuning %q has no Go instrument", name)
+			continue
+		}
+		inst, err := build(len(strs))
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if len(inst.Strings) != len(strs) {
+			t.Fatalf("%s: Go has %d strings, lib.js %d", name, len(inst.Strings), len(strs))
+		}
+		for si := range inst.Strings {
+			for fret, note := range inst.Strings[si].Notes {
+				got, _ := js.call("noteAt", si, fret, name).(string)
+				if want := sharpName(note.Name); got != want {
+					t.Errorf("%s string %d fret %d: lib.js noteAt = %q, Go = %q", name, si, fret, got, note.Name)
+				}
+			}
+		}
+	}
+}
+
+// TestLibJSDefaultTuningsParity checks every Go default tuning against
+// lib.js. The page only has the 6-string guitar and the 4-string bass; the
+// extended ranges contain those strings, which must agree fret by fret.
+func TestLibJSDefaultTuningsParity(t *testing.T) {
+	js := loadLibJS(t)
+	tunings, _ := js.get("TUNINGS").(map[string]any)
+	for name, counts := range goDefaultStrings {
+		tuning, ok := tunings[name]
+		if !ok {
+			if !slices.Contains(weblessInstruments, name) {
+				t.Errorf("lib.js has no %s tuning", name)
+			}
+			continue
+		}
+		if slices.Contains(weblessInstruments, name) {
+			t.Errorf("lib.js has a %s tuning; remove it from weblessInstruments", name)
+		}
+		strs := exportedStrings(tuning)
+		for _, n := range counts {
+			inst, err := libJSInstruments[name](n)
+			if err != nil {
+				t.Fatalf("%s with %d strings: %v", name, n, err)
+			}
+			offset := slices.IndexFunc(inst.Strings, func(s instrument.InstrumentString) bool {
+				return sharpName(s.Notes[0].Name) == strs[0]
+			})
+			if offset < 0 || offset+len(strs) > len(inst.Strings) {
+				t.Errorf("%s with %d strings does not contain the lib.js strings %v", name, n, strs)
+				continue
+			}
+			for li := range strs {
+				for fret, note := range inst.Strings[offset+li].Notes {
+					got, _ := js.call("noteAt", li, fret, name).(string)
+					if want := sharpName(note.Name); got != want {
+						t.Errorf("%s with %d strings, string %d fret %d: lib.js noteAt = %q, Go = %q",
+							name, n, offset+li+1, fret, got, note.Name)
+					}
+				}
+			}
+		}
+	}
+}
+
+// noteAnswers are the answers both games must judge alike: every spelling of
+// every note in upper, lower and mixed case, with stray whitespace, with
+// Unicode accidentals, and some that are no note at all.
+func noteAnswers() []string {
+	spellings := []string{"Cb", "Fb", "E#", "B#", "H", "X", "", " ", "C#/Db", "Db/C#", "C##", "Dbb"}
+	for _, n := range theory.Chromatic {
+		spellings = append(spellings, n)
+		if flat, ok := theory.Flats[n]; ok {
+			spellings = append(spellings, flat)
+		}
+	}
+	var answers []string
+	for _, s := range spellings {
+		unicode := strings.NewReplacer("#", "♯", "b", "♭").Replace(s)
+		answers = append(answers, s, strings.ToLower(s), strings.ToUpper(s), " "+s+" ", unicode)
+		if len(s) == 2 {
+			answers = append(answers, strings.ToLower(s[:1])+s[1:], s[:1]+" "+s[1:])
+		}
+	}
+	return answers
+}
+
+func TestLibJSMatchNoteParity(t *testing.T) {
+	js := loadLibJS(t)
+	names := instrument.NoteNames()
+	for _, answer := range noteAnswers() {
+		for pc, name := range names {
+			goOK := instrument.NoteMatches(name, answer)
+			jsOK, _ := js.call("matchNote", answer, theory.Chromatic[pc]).(bool)
+			if goOK != jsOK {
+				t.Errorf("answer %q for %s: Go accepts = %v, lib.js matchNote = %v", answer, name, goOK, jsOK)
+			}
+		}
+	}
+}
+
+func TestLibJSScaleNotesParity(t *testing.T) {
+	js := loadLibJS(t)
+	for _, scale := range theory.Scales {
+		// lib.js names its scales MAJOR_SCALE, …, PENTATONIC_MAJOR.
+		constant := strings.ToUpper(strings.ReplaceAll(scale.Name, " ", "_"))
+		if !strings.HasPrefix(constant, "PENTATONIC") {
+			constant += "_SCALE"
+		}
+		intervals := js.api.Get(constant)
+		if intervals == nil || goja.IsUndefined(intervals) {
+			t.Errorf("lib.js has no %s", constant)
+			continue
+		}
+		for _, root := range instrument.NoteNames() {
+			got := exportedStrings(js.call("scaleNotes", sharpName(root), intervals))
+			var want []string
+			for _, n := range instrument.ScaleNotes(root, scale.Intervals) {
+				want = append(want, sharpName(n))
+			}
+			if !slices.Equal(got, want) {
+				t.Errorf("%s %s: lib.js scaleNotes = %v, Go = %v", root, scale.Name, got, want)
+			}
+		}
+	}
+}
+
+// chordAnswers are answers for the chord-naming prompt: every root spelling
+// with every way of writing major and minor.
+func chordAnswers() []string {
+	suffixes := []string{"", "m", "M", "maj", "Maj", "major", " major", "MAJOR", "min", "Min", "minor", " minor", " m", "dim", "7"}
+	var roots []string
+	for _, n := range theory.Chromatic {
+		roots = append(roots, n, strings.ToLower(n))
+		if flat, ok := theory.Flats[n]; ok {
+			roots = append(roots, flat, strings.ToLower(flat))
+		}
+	}
+	var answers []string
+	for _, r := range roots {
+		for _, s := range suffixes {
+			answers = append(answers, r+s)
+		}
+	}
+	return append(answers, "", "H", "Hm", "C#/Db")
+}
+
+func TestLibJSChordNamingParity(t *testing.T) {
+	js := loadLibJS(t)
+	// The page's naming check (ChordGame.handleNaming in Fremorizer.html).
+	check, err := js.vm.RunString(`(input, name) => Fremorizer.chordNameCorrect(
+		Fremorizer.normalizeChordAnswer(input), name.toLowerCase())`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	correct, _ := goja.AssertFunction(check)
+
+	for pc, root := range instrument.NoteNames() {
+		for _, major := range []bool{true, false} {
+			g := &ChordsGame{rootNote: root, isMajor: major, phase: ChordPhaseNaming}
+			// lib.js spells chords as in CHORD_SHAPES.
+			name := theory.RootNames[pc] + " minor"
+			if major {
+				name = theory.RootNames[pc] + " major"
+			}
+			answers := append(chordAnswers(), g.ChordDisplayName())
+			for _, answer := range answers {
+				res, err := correct(goja.Undefined(), js.vm.ToValue(answer), js.vm.ToValue(name))
+				if err != nil {
+					t.Fatal(err)
+				}
+				if goOK, jsOK := g.CheckAnswer(answer), res.ToBoolean(); goOK != jsOK {
+					t.Errorf("answer %q for %s: Go accepts = %v, lib.js = %v", answer, name, goOK, jsOK)
+				}
+			}
+		}
+	}
+}
+
+func TestLibJSChordShapesMatchGame(t *testing.T) {
+	js := loadLibJS(t)
+	// Every major and minor chord of the web quiz, placed on the fretboard of
+	// the terminal game, must spell the same notes.
+	inst, err := instrument.NewGuitar(instrument.DefaultGuitarTuning(6), 24)
+	if err != nil {
+		t.Fatal(err)
+	}
+	shapes, _ := js.get("CHORD_SHAPES").([]any)
+	checked := 0
+	for _, s := range shapes {
+		chord, _ := s.(map[string]any)
+		name, _ := chord["name"].(string)
+		if !strings.HasSuffix(name, " major") && !strings.HasSuffix(name, " minor") {
+			continue
+		}
+		root, _, _ := strings.Cut(name, " ")
+		rootPC, _ := theory.PitchClass(root)
+		for _, p := range chord["positions"].([]any) {
+			pos := p.(map[string]any)
+			si, fret, iv := int(pos["s"].(int64)), int(pos["f"].(int64)), pos["iv"].(string)
+			semis, _ := theory.DegreeSemitones(iv)
+			got := inst.Strings[si].Notes[fret].Name
+			if want := instrument.NoteNames()[(rootPC+semis)%12]; got != want {
+				t.Errorf("%s: string %d fret %d is %s in Go, lib.js says %s (%s)", name, si, fret, got, want, iv)
+			}
+		}
+		checked++
+	}
+	if checked == 0 {
+		t.Fatal("lib.js has no major or minor chord shapes")
+	}
+}
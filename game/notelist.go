@@ -19,10 +19,12 @@ var (
 type NoteListGame struct {
 	accidentals string // "both", "sharps", "flats"
 	notes       []string
+	rng         *rand.Rand
 }
 
-func NewNoteListGame(accidentals string) *NoteListGame {
-	g := &NoteListGame{accidentals: accidentals}
+// NewNoteListGame creates a note list; a nil rng gives a random order.
+func NewNoteListGame(accidentals string, rng *rand.Rand) *NoteListGame {
+	g := &NoteListGame{accidentals: accidentals, rng: newRand(rng)}
 	g.Shuffle()
 	return g
 }
@@ -38,7 +40,7 @@ func (g *NoteListGame) Shuffle() {
 	default:
 		src = append([]string{}, notesBoth...)
 	}
-	rand.Shuffle(len(src), func(i, j int) { src[i], src[j] = src[j], src[i] })
+	g.rng.Shuffle(len(src), func(i, j int) { src[i], src[j] = src[j], src[i] })
 	g.notes = src
 }
 
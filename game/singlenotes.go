@@ -17,10 +17,13 @@ type SingleNoteGame struct {
 	cur        notePos   // position currently being asked
 	queue      []notePos // positions yet to be asked this round
 	retryQueue []notePos // missed positions to retry
+	rng        *rand.Rand
 }
 
-func NewSingleNoteGame(inst *instrument.Instrument) *SingleNoteGame {
-	g := &SingleNoteGame{inst: inst}
+// NewSingleNoteGame creates a mode-1 game. Games created with generators
+// seeded alike ask the same question sequence; rng may be nil.
+func NewSingleNoteGame(inst *instrument.Instrument, rng *rand.Rand) *SingleNoteGame {
+	g := &SingleNoteGame{inst: inst, rng: newRand(rng)}
 	g.queue = g.buildQueue()
 	g.advance()
 	return g
@@ -87,6 +90,12 @@ func (g *SingleNoteGame) CurrentNoteName() string {
 	return g.inst.Strings[g.cur.s].Notes[g.cur.n].Name
 }
 
+// CurrentPosition returns the string index (0 = highest string) and fret of
+// the note currently being asked.
+func (g *SingleNoteGame) CurrentPosition() (stringIdx, fret int) {
+	return g.cur.s, g.cur.n
+}
+
 // advance picks the next position from the queue. If the queue is empty and
 // there are retries, it refills from retryQueue and continues.
 func (g *SingleNoteGame) advance() {
@@ -94,7 +103,7 @@ func (g *SingleNoteGame) advance() {
 		if len(g.retryQueue) == 0 {
 			return // game over
 		}
-		g.queue = shuffle(g.retryQueue)
+		g.queue = shuffle(g.rng, g.retryQueue)
 		g.retryQueue = nil
 	}
 	g.cur = g.queue[0]
@@ -112,12 +121,12 @@ func (g *SingleNoteGame) buildQueue() []notePos {
 			positions = append(positions, notePos{si, ni})
 		}
 	}
-	return shuffle(positions)
+	return shuffle(g.rng, positions)
 }
 
-func shuffle(positions []notePos) []notePos {
+func shuffle(rng *rand.Rand, positions []notePos) []notePos {
 	out := make([]notePos, len(positions))
 	copy(out, positions)
-	rand.Shuffle(len(out), func(i, j int) { out[i], out[j] = out[j], out[i] })
+	rng.Shuffle(len(out), func(i, j int) { out[i], out[j] = out[j], out[i] })
 	return out
 }
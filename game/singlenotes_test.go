@@ -1,11 +1,12 @@
 package game
 
 import (
+	"math/rand"
 	"testing"
 )
 
 func TestSingleNoteGameCheckAnswer(t *testing.T) {
-	g := NewSingleNoteGame(newTestGuitar())
+	g := NewSingleNoteGame(newTestGuitar(), nil)
 	name := g.CurrentNoteName()
 
 	// Correct answer (first part of canonical name).
@@ -27,14 +28,14 @@ func TestSingleNoteGameCheckAnswer(t *testing.T) {
 }
 
 func TestSingleNoteGameIsGameOverAtStart(t *testing.T) {
-	g := NewSingleNoteGame(newTestGuitar())
+	g := NewSingleNoteGame(newTestGuitar(), nil)
 	if g.IsGameOver() {
 		t.Error("game should not be over at start")
 	}
 }
 
 func TestSingleNoteGameProgress(t *testing.T) {
-	g := NewSingleNoteGame(newTestGuitar())
+	g := NewSingleNoteGame(newTestGuitar(), nil)
 	correct, total := g.Progress()
 	if correct != 0 {
 		t.Errorf("Progress().correct = %d at start, want 0", correct)
@@ -45,7 +46,7 @@ func TestSingleNoteGameProgress(t *testing.T) {
 }
 
 func TestSingleNoteGameRevealAndNext(t *testing.T) {
-	g := NewSingleNoteGame(newTestGuitar())
+	g := NewSingleNoteGame(newTestGuitar(), nil)
 	si, fi := g.cur.s, g.cur.n
 
 	g.RevealNote(true)
@@ -62,7 +63,7 @@ func TestSingleNoteGameRevealAndNext(t *testing.T) {
 }
 
 func TestSingleNoteGameRevealIncorrect(t *testing.T) {
-	g := NewSingleNoteGame(newTestGuitar())
+	g := NewSingleNoteGame(newTestGuitar(), nil)
 	si, fi := g.cur.s, g.cur.n
 
 	g.RevealNote(false)
@@ -84,3 +85,30 @@ func splitSlash(name string) []string {
 	}
 	return []string{name}
 }
+
+func TestSingleNoteGameCurrentPosition(t *testing.T) {
+	g := NewSingleNoteGame(newTestGuitar(), nil)
+	si, fi := g.CurrentPosition()
+	if fi < 1 {
+		t.Errorf("CurrentPosition fret = %d, open strings are never asked", fi)
+	}
+	if got := g.inst.Strings[si].Notes[fi].Name; got != g.CurrentNoteName() {
+		t.Errorf("note at CurrentPosition = %q, CurrentNoteName = %q", got, g.CurrentNoteName())
+	}
+}
+
+func TestSingleNoteGameSeededSequence(t *testing.T) {
+	a := NewSingleNoteGame(newTestGuitar(), rand.New(rand.NewSource(42)))
+	b := NewSingleNoteGame(newTestGuitar(), rand.New(rand.NewSource(42)))
+	for i := 0; i < 20; i++ {
+		as, af := a.CurrentPosition()
+		bs, bf := b.CurrentPosition()
+		if as != bs || af != bf {
+			t.Fatalf("question %d: (%d,%d) vs (%d,%d) with the same seed", i, as, af, bs, bf)
+		}
+		a.RevealNote(true)
+		b.RevealNote(true)
+		_ = a.Next()
+		_ = b.Next()
+	}
+}
@@ -2,11 +2,94 @@ package main
 
 import (
 	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
 	"time"
+	"unicode"
 
 	"github.com/funkymcb/fremorizer/instrument"
+	"github.com/funkymcb/fremorizer/stats"
 )
 
+// localPlayer is the stats player ID used by the local TUI.
+const localPlayer = "local"
+
+// openLocalStats opens the stats store of the local TUI in the user's config
+// directory. It returns nil if no config directory is available, in which
+// case results are simply not recorded.
+func openLocalStats() *stats.Store {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return nil
+	}
+	s, err := stats.Open(filepath.Join(dir, "fremorizer", "stats"))
+	if err != nil {
+		return nil
+	}
+	return s
+}
+
+// localPlayerName is the leaderboard name of the local TUI's player.
+func localPlayerName() string {
+	if u := os.Getenv("USER"); u != "" {
+		return u
+	}
+	return localPlayer
+}
+
+// maxNameLen is the most characters of a player or team name shown to
+// other players.
+const maxNameLen = 24
+
+// displayName makes a name picked by a remote user safe to show on other
+// players' terminals: control and formatting characters, which could smuggle
+// in escape sequences, are dropped, and it is cut to maxNameLen characters.
+func displayName(name string) string {
+	name = strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) || unicode.Is(unicode.Cf, r) {
+			return -1
+		}
+		return r
+	}, name)
+	name = strings.TrimSpace(name)
+	if r := []rune(name); len(r) > maxNameLen {
+		name = string(r[:maxNameLen])
+	}
+	return name
+}
+
+// openServerStats opens the stats store shared by the SSH and HTTP servers.
+// It returns nil (stats disabled) if the directory cannot be created.
+func openServerStats() *stats.Store {
+	dir := "/opt/fremorizer/stats"
+	// Fall back to a local path when running outside of the server environment.
+	if _, err := os.Stat(filepath.Dir(dir)); os.IsNotExist(err) {
+		dir = ".stats"
+	}
+	s, err := stats.Open(dir)
+	if err != nil {
+		log.Printf("stats disabled: %v", err)
+		return nil
+	}
+	return s
+}
+
+// buildInstrument creates an instrument of the given type.
+func buildInstrument(instrType string, tuning []string, frets int) (*instrument.Instrument, error) {
+	switch instrType {
+	case "guitar":
+		return instrument.NewGuitar(tuning, frets)
+	case "bass":
+		return instrument.NewBass(tuning, frets)
+	case "ukulele":
+		return instrument.NewUkulele(tuning, frets)
+	default:
+		return nil, fmt.Errorf("unknown instrument: %s", instrType)
+	}
+}
+
 func defaultStringCount(instrType string) int {
 	switch instrType {
 	case "bass", "ukulele":
@@ -88,6 +171,56 @@ func prevAccidentals(cur string) string {
 	}
 }
 
+func fretboardLayoutLabel(l string) string {
+	switch l {
+	case "horizontal":
+		return "horizontal"
+	case "vertical":
+		return "vertical (chord chart)"
+	default:
+		return "auto (vertical if too wide)"
+	}
+}
+
+func handednessLabel(leftHanded bool) string {
+	if leftHanded {
+		return "left-handed (nut on the right)"
+	}
+	return "right-handed"
+}
+
+func stringOrderLabel(lowOnTop bool) string {
+	if lowOnTop {
+		return "player's view (lowest string on top)"
+	}
+	return "tab view (highest string on top)"
+}
+
+func nextFretboardLayout(cur string) string {
+	switch cur {
+	case "auto":
+		return "horizontal"
+	case "horizontal":
+		return "vertical"
+	default:
+		return "auto"
+	}
+}
+
+func prevFretboardLayout(cur string) string {
+	switch cur {
+	case "auto":
+		return "vertical"
+	case "vertical":
+		return "horizontal"
+	default:
+		return "auto"
+	}
+}
+
+// chordDifficulties are the chord mode settings, easiest first.
+var chordDifficulties = []string{"easy", "medium", "hard"}
+
 func nextChordDifficulty(cur string) string {
 	switch cur {
 	case "easy":
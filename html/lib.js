@@ -2,46 +2,31 @@
 //
 // Loaded in the browser via <script src="/lib.js"> before the React inline
 // script, and required directly by html/lib.test.js for unit tests under Node.
+// game/libjs_test.go also runs it in Go's test suite and checks that it
+// judges notes, scales and chord names exactly like the terminal game.
 //
 // Browser: attaches the API to window.Fremorizer (no other globals).
 // Node:    module.exports = the same API object.
+//
+// The music-theory data at the top (notes, tunings, scales, chord shapes and
+// formulas) is generated from the Go theory package: edit it there and run
+// `go generate ./theory`.
 
 (function (global) {
 
 /* ═══════════════════════════════════════
    MUSIC THEORY
 ═══════════════════════════════════════ */
+// BEGIN GENERATED music-theory data — edit theory/*.go, then run `go generate ./theory`.
 const CHROMATIC = ['C','C#','D','D#','E','F','F#','G','G#','A','A#','B'];
-const DISPLAY_BOTH   = { 'C#':'C#/Db','D#':'D#/Eb','F#':'F#/Gb','G#':'G#/Ab','A#':'A#/Bb' };
-const DISPLAY_FLAT   = { 'C#':'Db','D#':'Eb','F#':'Gb','G#':'Ab','A#':'Bb' };
-const showNote = (n, style='both') => {
-  if (style==='flat')  return DISPLAY_FLAT[n]  || n;
-  if (style==='sharp') return n;
-  return DISPLAY_BOTH[n] || n;
-};
+const DISPLAY_BOTH = { 'C#':'C#/Db','D#':'D#/Eb','F#':'F#/Gb','G#':'G#/Ab','A#':'A#/Bb' };
+const DISPLAY_FLAT = { 'C#':'Db','D#':'Eb','F#':'Gb','G#':'Ab','A#':'Bb' };
 
 // Top-to-bottom tunings (high → low)
 const TUNINGS = {
   guitar: ['E','B','G','D','A','E'],
   bass:   ['G','D','A','E'],
 };
-const stringsFor = inst => TUNINGS[inst] || TUNINGS.guitar;
-
-// Open-string MIDI numbers, high → low (matches TUNINGS layout). Used for
-// audio playback and for finding the lowest sounding note of a voicing.
-const OPEN_MIDI = { guitar: [64,59,55,50,45,40], bass: [43,38,33,28] };
-
-function noteAt(si, fret, inst='guitar') {
-  const strings = stringsFor(inst);
-  const idx = CHROMATIC.indexOf(strings[si]);
-  return CHROMATIC[(idx + fret) % 12];
-}
-
-function matchNote(input, expected) {
-  const FLAT_MAP = {'DB':'C#','EB':'D#','GB':'F#','AB':'G#','BB':'A#'};
-  const n = input.trim().toUpperCase().replace('♭','B').replace('/','').replace(' ','');
-  return (FLAT_MAP[n] || n) === expected;
-}
 
 const MAJOR_SCALE = [0,2,4,5,7,9,11];
 const MINOR_SCALE = [0,2,3,5,7,8,10];
@@ -51,17 +36,9 @@ const HARMONIC_MINOR_SCALE = [0,2,3,5,7,8,11];
 const PENTATONIC_MAJOR = [0,2,4,7,9];
 const PENTATONIC_MINOR = [0,3,5,7,10];
 
-function scaleNotes(root, intervals) {
-  const ri = CHROMATIC.indexOf(root);
-  return intervals.map(i => CHROMATIC[(ri+i)%12]);
-}
-
-/* ═══════════════════════════════════════
-   CHORD DATA  (s = display string 0-5)
-═══════════════════════════════════════ */
+// Every voicing at every root that fits frets 0-12 (s = display string 0-5).
 const CHORD_SHAPES = [
-  // ── MAJOR ───────────────────────────────────────────────────────
-  // E-shape
+  // major, E-shape, root on the low E string
   { name:'C major', positions:[{s:5,f:8,iv:'1'},{s:4,f:10,iv:'5'},{s:3,f:10,iv:'1'},{s:2,f:9,iv:'3'},{s:1,f:8,iv:'5'},{s:0,f:8,iv:'1'}] },
   { name:'C# major', positions:[{s:5,f:9,iv:'1'},{s:4,f:11,iv:'5'},{s:3,f:11,iv:'1'},{s:2,f:10,iv:'3'},{s:1,f:9,iv:'5'},{s:0,f:9,iv:'1'}] },
   { name:'D major', positions:[{s:5,f:10,iv:'1'},{s:4,f:12,iv:'5'},{s:3,f:12,iv:'1'},{s:2,f:11,iv:'3'},{s:1,f:10,iv:'5'},{s:0,f:10,iv:'1'}] },
@@ -73,7 +50,7 @@ const CHORD_SHAPES = [
   { name:'A major', positions:[{s:5,f:5,iv:'1'},{s:4,f:7,iv:'5'},{s:3,f:7,iv:'1'},{s:2,f:6,iv:'3'},{s:1,f:5,iv:'5'},{s:0,f:5,iv:'1'}] },
   { name:'Bb major', positions:[{s:5,f:6,iv:'1'},{s:4,f:8,iv:'5'},{s:3,f:8,iv:'1'},{s:2,f:7,iv:'3'},{s:1,f:6,iv:'5'},{s:0,f:6,iv:'1'}] },
   { name:'B major', positions:[{s:5,f:7,iv:'1'},{s:4,f:9,iv:'5'},{s:3,f:9,iv:'1'},{s:2,f:8,iv:'3'},{s:1,f:7,iv:'5'},{s:0,f:7,iv:'1'}] },
-  // A-shape
+  // major, A-shape, root on the A string
   { name:'C major', positions:[{s:4,f:3,iv:'1'},{s:3,f:5,iv:'5'},{s:2,f:5,iv:'1'},{s:1,f:5,iv:'3'},{s:0,f:3,iv:'5'}] },
   { name:'C# major', positions:[{s:4,f:4,iv:'1'},{s:3,f:6,iv:'5'},{s:2,f:6,iv:'1'},{s:1,f:6,iv:'3'},{s:0,f:4,iv:'5'}] },
   { name:'D major', positions:[{s:4,f:5,iv:'1'},{s:3,f:7,iv:'5'},{s:2,f:7,iv:'1'},{s:1,f:7,iv:'3'},{s:0,f:5,iv:'5'}] },
@@ -85,7 +62,7 @@ const CHORD_SHAPES = [
   { name:'A major', positions:[{s:4,f:0,iv:'1'},{s:3,f:2,iv:'5'},{s:2,f:2,iv:'1'},{s:1,f:2,iv:'3'},{s:0,f:0,iv:'5'}] },
   { name:'Bb major', positions:[{s:4,f:1,iv:'1'},{s:3,f:3,iv:'5'},{s:2,f:3,iv:'1'},{s:1,f:3,iv:'3'},{s:0,f:1,iv:'5'}] },
   { name:'B major', positions:[{s:4,f:2,iv:'1'},{s:3,f:4,iv:'5'},{s:2,f:4,iv:'1'},{s:1,f:4,iv:'3'},{s:0,f:2,iv:'5'}] },
-  // D-shape
+  // major, D-shape, root on the D string
   { name:'D major', positions:[{s:3,f:0,iv:'1'},{s:2,f:2,iv:'5'},{s:1,f:3,iv:'1'},{s:0,f:2,iv:'3'}] },
   { name:'Eb major', positions:[{s:3,f:1,iv:'1'},{s:2,f:3,iv:'5'},{s:1,f:4,iv:'1'},{s:0,f:3,iv:'3'}] },
   { name:'E major', positions:[{s:3,f:2,iv:'1'},{s:2,f:4,iv:'5'},{s:1,f:5,iv:'1'},{s:0,f:4,iv:'3'}] },
@@ -96,7 +73,7 @@ const CHORD_SHAPES = [
   { name:'A major', positions:[{s:3,f:7,iv:'1'},{s:2,f:9,iv:'5'},{s:1,f:10,iv:'1'},{s:0,f:9,iv:'3'}] },
   { name:'Bb major', positions:[{s:3,f:8,iv:'1'},{s:2,f:10,iv:'5'},{s:1,f:11,iv:'1'},{s:0,f:10,iv:'3'}] },
   { name:'B major', positions:[{s:3,f:9,iv:'1'},{s:2,f:11,iv:'5'},{s:1,f:12,iv:'1'},{s:0,f:11,iv:'3'}] },
-  // C-shape
+  // major, C-shape, root on the A string
   { name:'C major', positions:[{s:4,f:3,iv:'1'},{s:3,f:2,iv:'3'},{s:2,f:0,iv:'5'},{s:1,f:1,iv:'1'},{s:0,f:0,iv:'3'}] },
   { name:'C# major', positions:[{s:4,f:4,iv:'1'},{s:3,f:3,iv:'3'},{s:2,f:1,iv:'5'},{s:1,f:2,iv:'1'},{s:0,f:1,iv:'3'}] },
   { name:'D major', positions:[{s:4,f:5,iv:'1'},{s:3,f:4,iv:'3'},{s:2,f:2,iv:'5'},{s:1,f:3,iv:'1'},{s:0,f:2,iv:'3'}] },
@@ -106,7 +83,7 @@ const CHORD_SHAPES = [
   { name:'F# major', positions:[{s:4,f:9,iv:'1'},{s:3,f:8,iv:'3'},{s:2,f:6,iv:'5'},{s:1,f:7,iv:'1'},{s:0,f:6,iv:'3'}] },
   { name:'G major', positions:[{s:4,f:10,iv:'1'},{s:3,f:9,iv:'3'},{s:2,f:7,iv:'5'},{s:1,f:8,iv:'1'},{s:0,f:7,iv:'3'}] },
   { name:'Ab major', positions:[{s:4,f:11,iv:'1'},{s:3,f:10,iv:'3'},{s:2,f:8,iv:'5'},{s:1,f:9,iv:'1'},{s:0,f:8,iv:'3'}] },
-  // G-shape
+  // major, G-shape, root on the low E string
   { name:'C major', positions:[{s:5,f:8,iv:'1'},{s:4,f:7,iv:'3'},{s:3,f:5,iv:'5'},{s:2,f:5,iv:'1'},{s:1,f:5,iv:'3'},{s:0,f:8,iv:'1'}] },
   { name:'C# major', positions:[{s:5,f:9,iv:'1'},{s:4,f:8,iv:'3'},{s:3,f:6,iv:'5'},{s:2,f:6,iv:'1'},{s:1,f:6,iv:'3'},{s:0,f:9,iv:'1'}] },
   { name:'D major', positions:[{s:5,f:10,iv:'1'},{s:4,f:9,iv:'3'},{s:3,f:7,iv:'5'},{s:2,f:7,iv:'1'},{s:1,f:7,iv:'3'},{s:0,f:10,iv:'1'}] },
@@ -116,8 +93,7 @@ const CHORD_SHAPES = [
   { name:'A major', positions:[{s:5,f:5,iv:'1'},{s:4,f:4,iv:'3'},{s:3,f:2,iv:'5'},{s:2,f:2,iv:'1'},{s:1,f:2,iv:'3'},{s:0,f:5,iv:'1'}] },
   { name:'Bb major', positions:[{s:5,f:6,iv:'1'},{s:4,f:5,iv:'3'},{s:3,f:3,iv:'5'},{s:2,f:3,iv:'1'},{s:1,f:3,iv:'3'},{s:0,f:6,iv:'1'}] },
   { name:'B major', positions:[{s:5,f:7,iv:'1'},{s:4,f:6,iv:'3'},{s:3,f:4,iv:'5'},{s:2,f:4,iv:'1'},{s:1,f:4,iv:'3'},{s:0,f:7,iv:'1'}] },
-  // ── MINOR ───────────────────────────────────────────────────────
-  // E-shape
+  // minor, E-shape, root on the low E string
   { name:'C minor', positions:[{s:5,f:8,iv:'1'},{s:4,f:10,iv:'5'},{s:3,f:10,iv:'1'},{s:2,f:8,iv:'b3'},{s:1,f:8,iv:'5'},{s:0,f:8,iv:'1'}] },
   { name:'C# minor', positions:[{s:5,f:9,iv:'1'},{s:4,f:11,iv:'5'},{s:3,f:11,iv:'1'},{s:2,f:9,iv:'b3'},{s:1,f:9,iv:'5'},{s:0,f:9,iv:'1'}] },
   { name:'D minor', positions:[{s:5,f:10,iv:'1'},{s:4,f:12,iv:'5'},{s:3,f:12,iv:'1'},{s:2,f:10,iv:'b3'},{s:1,f:10,iv:'5'},{s:0,f:10,iv:'1'}] },
@@ -129,7 +105,7 @@ const CHORD_SHAPES = [
   { name:'A minor', positions:[{s:5,f:5,iv:'1'},{s:4,f:7,iv:'5'},{s:3,f:7,iv:'1'},{s:2,f:5,iv:'b3'},{s:1,f:5,iv:'5'},{s:0,f:5,iv:'1'}] },
   { name:'Bb minor', positions:[{s:5,f:6,iv:'1'},{s:4,f:8,iv:'5'},{s:3,f:8,iv:'1'},{s:2,f:6,iv:'b3'},{s:1,f:6,iv:'5'},{s:0,f:6,iv:'1'}] },
   { name:'B minor', positions:[{s:5,f:7,iv:'1'},{s:4,f:9,iv:'5'},{s:3,f:9,iv:'1'},{s:2,f:7,iv:'b3'},{s:1,f:7,iv:'5'},{s:0,f:7,iv:'1'}] },
-  // A-shape
+  // minor, A-shape, root on the A string
   { name:'C minor', positions:[{s:4,f:3,iv:'1'},{s:3,f:5,iv:'5'},{s:2,f:5,iv:'1'},{s:1,f:4,iv:'b3'},{s:0,f:3,iv:'5'}] },
   { name:'C# minor', positions:[{s:4,f:4,iv:'1'},{s:3,f:6,iv:'5'},{s:2,f:6,iv:'1'},{s:1,f:5,iv:'b3'},{s:0,f:4,iv:'5'}] },
   { name:'D minor', positions:[{s:4,f:5,iv:'1'},{s:3,f:7,iv:'5'},{s:2,f:7,iv:'1'},{s:1,f:6,iv:'b3'},{s:0,f:5,iv:'5'}] },
@@ -141,7 +117,7 @@ const CHORD_SHAPES = [
   { name:'A minor', positions:[{s:4,f:0,iv:'1'},{s:3,f:2,iv:'5'},{s:2,f:2,iv:'1'},{s:1,f:1,iv:'b3'},{s:0,f:0,iv:'5'}] },
   { name:'Bb minor', positions:[{s:4,f:1,iv:'1'},{s:3,f:3,iv:'5'},{s:2,f:3,iv:'1'},{s:1,f:2,iv:'b3'},{s:0,f:1,iv:'5'}] },
   { name:'B minor', positions:[{s:4,f:2,iv:'1'},{s:3,f:4,iv:'5'},{s:2,f:4,iv:'1'},{s:1,f:3,iv:'b3'},{s:0,f:2,iv:'5'}] },
-  // D-shape
+  // minor, D-shape, root on the D string
   { name:'D minor', positions:[{s:3,f:0,iv:'1'},{s:2,f:2,iv:'5'},{s:1,f:3,iv:'1'},{s:0,f:1,iv:'b3'}] },
   { name:'Eb minor', positions:[{s:3,f:1,iv:'1'},{s:2,f:3,iv:'5'},{s:1,f:4,iv:'1'},{s:0,f:2,iv:'b3'}] },
   { name:'E minor', positions:[{s:3,f:2,iv:'1'},{s:2,f:4,iv:'5'},{s:1,f:5,iv:'1'},{s:0,f:3,iv:'b3'}] },
@@ -152,8 +128,7 @@ const CHORD_SHAPES = [
   { name:'A minor', positions:[{s:3,f:7,iv:'1'},{s:2,f:9,iv:'5'},{s:1,f:10,iv:'1'},{s:0,f:8,iv:'b3'}] },
   { name:'Bb minor', positions:[{s:3,f:8,iv:'1'},{s:2,f:10,iv:'5'},{s:1,f:11,iv:'1'},{s:0,f:9,iv:'b3'}] },
   { name:'B minor', positions:[{s:3,f:9,iv:'1'},{s:2,f:11,iv:'5'},{s:1,f:12,iv:'1'},{s:0,f:10,iv:'b3'}] },
-  // ── DOMINANT 7TH ────────────────────────────────────────────────
-  // E-shape
+  // 7, E-shape, root on the low E string
   { name:'C7', positions:[{s:5,f:8,iv:'1'},{s:4,f:10,iv:'5'},{s:3,f:8,iv:'b7'},{s:2,f:9,iv:'3'},{s:1,f:8,iv:'5'},{s:0,f:8,iv:'1'}] },
   { name:'C#7', positions:[{s:5,f:9,iv:'1'},{s:4,f:11,iv:'5'},{s:3,f:9,iv:'b7'},{s:2,f:10,iv:'3'},{s:1,f:9,iv:'5'},{s:0,f:9,iv:'1'}] },
   { name:'D7', positions:[{s:5,f:10,iv:'1'},{s:4,f:12,iv:'5'},{s:3,f:10,iv:'b7'},{s:2,f:11,iv:'3'},{s:1,f:10,iv:'5'},{s:0,f:10,iv:'1'}] },
@@ -165,7 +140,7 @@ const CHORD_SHAPES = [
   { name:'A7', positions:[{s:5,f:5,iv:'1'},{s:4,f:7,iv:'5'},{s:3,f:5,iv:'b7'},{s:2,f:6,iv:'3'},{s:1,f:5,iv:'5'},{s:0,f:5,iv:'1'}] },
   { name:'Bb7', positions:[{s:5,f:6,iv:'1'},{s:4,f:8,iv:'5'},{s:3,f:6,iv:'b7'},{s:2,f:7,iv:'3'},{s:1,f:6,iv:'5'},{s:0,f:6,iv:'1'}] },
   { name:'B7', positions:[{s:5,f:7,iv:'1'},{s:4,f:9,iv:'5'},{s:3,f:7,iv:'b7'},{s:2,f:8,iv:'3'},{s:1,f:7,iv:'5'},{s:0,f:7,iv:'1'}] },
-  // A-shape
+  // 7, A-shape, root on the A string
   { name:'C7', positions:[{s:4,f:3,iv:'1'},{s:3,f:5,iv:'5'},{s:2,f:3,iv:'b7'},{s:1,f:5,iv:'3'},{s:0,f:3,iv:'5'}] },
   { name:'C#7', positions:[{s:4,f:4,iv:'1'},{s:3,f:6,iv:'5'},{s:2,f:4,iv:'b7'},{s:1,f:6,iv:'3'},{s:0,f:4,iv:'5'}] },
   { name:'D7', positions:[{s:4,f:5,iv:'1'},{s:3,f:7,iv:'5'},{s:2,f:5,iv:'b7'},{s:1,f:7,iv:'3'},{s:0,f:5,iv:'5'}] },
@@ -177,7 +152,7 @@ const CHORD_SHAPES = [
   { name:'A7', positions:[{s:4,f:0,iv:'1'},{s:3,f:2,iv:'5'},{s:2,f:0,iv:'b7'},{s:1,f:2,iv:'3'},{s:0,f:0,iv:'5'}] },
   { name:'Bb7', positions:[{s:4,f:1,iv:'1'},{s:3,f:3,iv:'5'},{s:2,f:1,iv:'b7'},{s:1,f:3,iv:'3'},{s:0,f:1,iv:'5'}] },
   { name:'B7', positions:[{s:4,f:2,iv:'1'},{s:3,f:4,iv:'5'},{s:2,f:2,iv:'b7'},{s:1,f:4,iv:'3'},{s:0,f:2,iv:'5'}] },
-  // D-shape
+  // 7, D-shape, root on the D string
   { name:'C7', positions:[{s:3,f:10,iv:'1'},{s:2,f:12,iv:'5'},{s:1,f:11,iv:'b7'},{s:0,f:12,iv:'3'}] },
   { name:'D7', positions:[{s:3,f:0,iv:'1'},{s:2,f:2,iv:'5'},{s:1,f:1,iv:'b7'},{s:0,f:2,iv:'3'}] },
   { name:'Eb7', positions:[{s:3,f:1,iv:'1'},{s:2,f:3,iv:'5'},{s:1,f:2,iv:'b7'},{s:0,f:3,iv:'3'}] },
@@ -189,11 +164,9 @@ const CHORD_SHAPES = [
   { name:'A7', positions:[{s:3,f:7,iv:'1'},{s:2,f:9,iv:'5'},{s:1,f:8,iv:'b7'},{s:0,f:9,iv:'3'}] },
   { name:'Bb7', positions:[{s:3,f:8,iv:'1'},{s:2,f:10,iv:'5'},{s:1,f:9,iv:'b7'},{s:0,f:10,iv:'3'}] },
   { name:'B7', positions:[{s:3,f:9,iv:'1'},{s:2,f:11,iv:'5'},{s:1,f:10,iv:'b7'},{s:0,f:11,iv:'3'}] },
-  // ── SUS2 ────────────────────────────────────────────────────────
-  // E-shape — only the open voicing; barred up the neck this shape spans
-  // 5 frets under a barre, which is not realistically playable.
+  // sus2, E-shape, root on the low E string
   { name:'Esus2', positions:[{s:5,f:0,iv:'1'},{s:4,f:2,iv:'5'},{s:3,f:4,iv:'2'},{s:2,f:4,iv:'5'},{s:1,f:0,iv:'5'},{s:0,f:0,iv:'1'}] },
-  // A-shape
+  // sus2, A-shape, root on the A string
   { name:'Csus2', positions:[{s:4,f:3,iv:'1'},{s:3,f:5,iv:'5'},{s:2,f:5,iv:'1'},{s:1,f:3,iv:'2'},{s:0,f:3,iv:'5'}] },
   { name:'C#sus2', positions:[{s:4,f:4,iv:'1'},{s:3,f:6,iv:'5'},{s:2,f:6,iv:'1'},{s:1,f:4,iv:'2'},{s:0,f:4,iv:'5'}] },
   { name:'Dsus2', positions:[{s:4,f:5,iv:'1'},{s:3,f:7,iv:'5'},{s:2,f:7,iv:'1'},{s:1,f:5,iv:'2'},{s:0,f:5,iv:'5'}] },
@@ -205,7 +178,7 @@ const CHORD_SHAPES = [
   { name:'Asus2', positions:[{s:4,f:0,iv:'1'},{s:3,f:2,iv:'5'},{s:2,f:2,iv:'1'},{s:1,f:0,iv:'2'},{s:0,f:0,iv:'5'}] },
   { name:'Bbsus2', positions:[{s:4,f:1,iv:'1'},{s:3,f:3,iv:'5'},{s:2,f:3,iv:'1'},{s:1,f:1,iv:'2'},{s:0,f:1,iv:'5'}] },
   { name:'Bsus2', positions:[{s:4,f:2,iv:'1'},{s:3,f:4,iv:'5'},{s:2,f:4,iv:'1'},{s:1,f:2,iv:'2'},{s:0,f:2,iv:'5'}] },
-  // D-shape
+  // sus2, D-shape, root on the D string
   { name:'Dsus2', positions:[{s:3,f:0,iv:'1'},{s:2,f:2,iv:'5'},{s:1,f:3,iv:'1'},{s:0,f:0,iv:'2'}] },
   { name:'Ebsus2', positions:[{s:3,f:1,iv:'1'},{s:2,f:3,iv:'5'},{s:1,f:4,iv:'1'},{s:0,f:1,iv:'2'}] },
   { name:'Esus2', positions:[{s:3,f:2,iv:'1'},{s:2,f:4,iv:'5'},{s:1,f:5,iv:'1'},{s:0,f:2,iv:'2'}] },
@@ -216,8 +189,7 @@ const CHORD_SHAPES = [
   { name:'Asus2', positions:[{s:3,f:7,iv:'1'},{s:2,f:9,iv:'5'},{s:1,f:10,iv:'1'},{s:0,f:7,iv:'2'}] },
   { name:'Bbsus2', positions:[{s:3,f:8,iv:'1'},{s:2,f:10,iv:'5'},{s:1,f:11,iv:'1'},{s:0,f:8,iv:'2'}] },
   { name:'Bsus2', positions:[{s:3,f:9,iv:'1'},{s:2,f:11,iv:'5'},{s:1,f:12,iv:'1'},{s:0,f:9,iv:'2'}] },
-  // ── SUS4 ────────────────────────────────────────────────────────
-  // E-shape
+  // sus4, E-shape, root on the low E string
   { name:'Csus4', positions:[{s:5,f:8,iv:'1'},{s:4,f:10,iv:'5'},{s:3,f:10,iv:'1'},{s:2,f:10,iv:'4'},{s:1,f:8,iv:'5'},{s:0,f:8,iv:'1'}] },
   { name:'C#sus4', positions:[{s:5,f:9,iv:'1'},{s:4,f:11,iv:'5'},{s:3,f:11,iv:'1'},{s:2,f:11,iv:'4'},{s:1,f:9,iv:'5'},{s:0,f:9,iv:'1'}] },
   { name:'Dsus4', positions:[{s:5,f:10,iv:'1'},{s:4,f:12,iv:'5'},{s:3,f:12,iv:'1'},{s:2,f:12,iv:'4'},{s:1,f:10,iv:'5'},{s:0,f:10,iv:'1'}] },
@@ -229,7 +201,7 @@ const CHORD_SHAPES = [
   { name:'Asus4', positions:[{s:5,f:5,iv:'1'},{s:4,f:7,iv:'5'},{s:3,f:7,iv:'1'},{s:2,f:7,iv:'4'},{s:1,f:5,iv:'5'},{s:0,f:5,iv:'1'}] },
   { name:'Bbsus4', positions:[{s:5,f:6,iv:'1'},{s:4,f:8,iv:'5'},{s:3,f:8,iv:'1'},{s:2,f:8,iv:'4'},{s:1,f:6,iv:'5'},{s:0,f:6,iv:'1'}] },
   { name:'Bsus4', positions:[{s:5,f:7,iv:'1'},{s:4,f:9,iv:'5'},{s:3,f:9,iv:'1'},{s:2,f:9,iv:'4'},{s:1,f:7,iv:'5'},{s:0,f:7,iv:'1'}] },
-  // A-shape
+  // sus4, A-shape, root on the A string
   { name:'Csus4', positions:[{s:4,f:3,iv:'1'},{s:3,f:5,iv:'5'},{s:2,f:5,iv:'1'},{s:1,f:6,iv:'4'},{s:0,f:3,iv:'5'}] },
   { name:'C#sus4', positions:[{s:4,f:4,iv:'1'},{s:3,f:6,iv:'5'},{s:2,f:6,iv:'1'},{s:1,f:7,iv:'4'},{s:0,f:4,iv:'5'}] },
   { name:'Dsus4', positions:[{s:4,f:5,iv:'1'},{s:3,f:7,iv:'5'},{s:2,f:7,iv:'1'},{s:1,f:8,iv:'4'},{s:0,f:5,iv:'5'}] },
@@ -240,7 +212,7 @@ const CHORD_SHAPES = [
   { name:'Asus4', positions:[{s:4,f:0,iv:'1'},{s:3,f:2,iv:'5'},{s:2,f:2,iv:'1'},{s:1,f:3,iv:'4'},{s:0,f:0,iv:'5'}] },
   { name:'Bbsus4', positions:[{s:4,f:1,iv:'1'},{s:3,f:3,iv:'5'},{s:2,f:3,iv:'1'},{s:1,f:4,iv:'4'},{s:0,f:1,iv:'5'}] },
   { name:'Bsus4', positions:[{s:4,f:2,iv:'1'},{s:3,f:4,iv:'5'},{s:2,f:4,iv:'1'},{s:1,f:5,iv:'4'},{s:0,f:2,iv:'5'}] },
-  // D-shape
+  // sus4, D-shape, root on the D string
   { name:'Dsus4', positions:[{s:3,f:0,iv:'1'},{s:2,f:2,iv:'5'},{s:1,f:3,iv:'1'},{s:0,f:3,iv:'4'}] },
   { name:'Ebsus4', positions:[{s:3,f:1,iv:'1'},{s:2,f:3,iv:'5'},{s:1,f:4,iv:'1'},{s:0,f:4,iv:'4'}] },
   { name:'Esus4', positions:[{s:3,f:2,iv:'1'},{s:2,f:4,iv:'5'},{s:1,f:5,iv:'1'},{s:0,f:5,iv:'4'}] },
@@ -251,8 +223,7 @@ const CHORD_SHAPES = [
   { name:'Asus4', positions:[{s:3,f:7,iv:'1'},{s:2,f:9,iv:'5'},{s:1,f:10,iv:'1'},{s:0,f:10,iv:'4'}] },
   { name:'Bbsus4', positions:[{s:3,f:8,iv:'1'},{s:2,f:10,iv:'5'},{s:1,f:11,iv:'1'},{s:0,f:11,iv:'4'}] },
   { name:'Bsus4', positions:[{s:3,f:9,iv:'1'},{s:2,f:11,iv:'5'},{s:1,f:12,iv:'1'},{s:0,f:12,iv:'4'}] },
-  // ── ADD9 ────────────────────────────────────────────────────────
-  // E-shape
+  // add9, E-shape, root on the low E string
   { name:'Cadd9', positions:[{s:5,f:8,iv:'1'},{s:4,f:10,iv:'5'},{s:3,f:10,iv:'1'},{s:2,f:9,iv:'3'},{s:1,f:8,iv:'5'},{s:0,f:10,iv:'9'}] },
   { name:'C#add9', positions:[{s:5,f:9,iv:'1'},{s:4,f:11,iv:'5'},{s:3,f:11,iv:'1'},{s:2,f:10,iv:'3'},{s:1,f:9,iv:'5'},{s:0,f:11,iv:'9'}] },
   { name:'Dadd9', positions:[{s:5,f:10,iv:'1'},{s:4,f:12,iv:'5'},{s:3,f:12,iv:'1'},{s:2,f:11,iv:'3'},{s:1,f:10,iv:'5'},{s:0,f:12,iv:'9'}] },
@@ -264,9 +235,7 @@ const CHORD_SHAPES = [
   { name:'Aadd9', positions:[{s:5,f:5,iv:'1'},{s:4,f:7,iv:'5'},{s:3,f:7,iv:'1'},{s:2,f:6,iv:'3'},{s:1,f:5,iv:'5'},{s:0,f:7,iv:'9'}] },
   { name:'Bbadd9', positions:[{s:5,f:6,iv:'1'},{s:4,f:8,iv:'5'},{s:3,f:8,iv:'1'},{s:2,f:7,iv:'3'},{s:1,f:6,iv:'5'},{s:0,f:8,iv:'9'}] },
   { name:'Badd9', positions:[{s:5,f:7,iv:'1'},{s:4,f:9,iv:'5'},{s:3,f:9,iv:'1'},{s:2,f:8,iv:'3'},{s:1,f:7,iv:'5'},{s:0,f:9,iv:'9'}] },
-  // (No D-shape add9: without a 3rd those voicings are identical to the
-  // D-shape sus2 entries above — same frets, two names, one accepted answer.)
-  // C-shape
+  // add9, C-shape, root on the A string
   { name:'Cadd9', positions:[{s:4,f:3,iv:'1'},{s:3,f:2,iv:'3'},{s:2,f:0,iv:'5'},{s:1,f:3,iv:'9'},{s:0,f:0,iv:'3'}] },
   { name:'C#add9', positions:[{s:4,f:4,iv:'1'},{s:3,f:3,iv:'3'},{s:2,f:1,iv:'5'},{s:1,f:4,iv:'9'},{s:0,f:1,iv:'3'}] },
   { name:'Dadd9', positions:[{s:4,f:5,iv:'1'},{s:3,f:4,iv:'3'},{s:2,f:2,iv:'5'},{s:1,f:5,iv:'9'},{s:0,f:2,iv:'3'}] },
@@ -276,8 +245,7 @@ const CHORD_SHAPES = [
   { name:'F#add9', positions:[{s:4,f:9,iv:'1'},{s:3,f:8,iv:'3'},{s:2,f:6,iv:'5'},{s:1,f:9,iv:'9'},{s:0,f:6,iv:'3'}] },
   { name:'Gadd9', positions:[{s:4,f:10,iv:'1'},{s:3,f:9,iv:'3'},{s:2,f:7,iv:'5'},{s:1,f:10,iv:'9'},{s:0,f:7,iv:'3'}] },
   { name:'Abadd9', positions:[{s:4,f:11,iv:'1'},{s:3,f:10,iv:'3'},{s:2,f:8,iv:'5'},{s:1,f:11,iv:'9'},{s:0,f:8,iv:'3'}] },
-  // ── MAJ7 ────────────────────────────────────────────────────────
-  // E-shape
+  // maj7, E-shape, root on the low E string
   { name:'Cmaj7', positions:[{s:5,f:8,iv:'1'},{s:4,f:10,iv:'5'},{s:3,f:9,iv:'7'},{s:2,f:9,iv:'3'},{s:1,f:8,iv:'5'},{s:0,f:8,iv:'1'}] },
   { name:'C#maj7', positions:[{s:5,f:9,iv:'1'},{s:4,f:11,iv:'5'},{s:3,f:10,iv:'7'},{s:2,f:10,iv:'3'},{s:1,f:9,iv:'5'},{s:0,f:9,iv:'1'}] },
   { name:'Dmaj7', positions:[{s:5,f:10,iv:'1'},{s:4,f:12,iv:'5'},{s:3,f:11,iv:'7'},{s:2,f:11,iv:'3'},{s:1,f:10,iv:'5'},{s:0,f:10,iv:'1'}] },
@@ -289,7 +257,7 @@ const CHORD_SHAPES = [
   { name:'Amaj7', positions:[{s:5,f:5,iv:'1'},{s:4,f:7,iv:'5'},{s:3,f:6,iv:'7'},{s:2,f:6,iv:'3'},{s:1,f:5,iv:'5'},{s:0,f:5,iv:'1'}] },
   { name:'Bbmaj7', positions:[{s:5,f:6,iv:'1'},{s:4,f:8,iv:'5'},{s:3,f:7,iv:'7'},{s:2,f:7,iv:'3'},{s:1,f:6,iv:'5'},{s:0,f:6,iv:'1'}] },
   { name:'Bmaj7', positions:[{s:5,f:7,iv:'1'},{s:4,f:9,iv:'5'},{s:3,f:8,iv:'7'},{s:2,f:8,iv:'3'},{s:1,f:7,iv:'5'},{s:0,f:7,iv:'1'}] },
-  // A-shape
+  // maj7, A-shape, root on the A string
   { name:'Cmaj7', positions:[{s:4,f:3,iv:'1'},{s:3,f:5,iv:'5'},{s:2,f:4,iv:'7'},{s:1,f:5,iv:'3'},{s:0,f:3,iv:'5'}] },
   { name:'C#maj7', positions:[{s:4,f:4,iv:'1'},{s:3,f:6,iv:'5'},{s:2,f:5,iv:'7'},{s:1,f:6,iv:'3'},{s:0,f:4,iv:'5'}] },
   { name:'Dmaj7', positions:[{s:4,f:5,iv:'1'},{s:3,f:7,iv:'5'},{s:2,f:6,iv:'7'},{s:1,f:7,iv:'3'},{s:0,f:5,iv:'5'}] },
@@ -301,7 +269,7 @@ const CHORD_SHAPES = [
   { name:'Amaj7', positions:[{s:4,f:0,iv:'1'},{s:3,f:2,iv:'5'},{s:2,f:1,iv:'7'},{s:1,f:2,iv:'3'},{s:0,f:0,iv:'5'}] },
   { name:'Bbmaj7', positions:[{s:4,f:1,iv:'1'},{s:3,f:3,iv:'5'},{s:2,f:2,iv:'7'},{s:1,f:3,iv:'3'},{s:0,f:1,iv:'5'}] },
   { name:'Bmaj7', positions:[{s:4,f:2,iv:'1'},{s:3,f:4,iv:'5'},{s:2,f:3,iv:'7'},{s:1,f:4,iv:'3'},{s:0,f:2,iv:'5'}] },
-  // D-shape
+  // maj7, D-shape, root on the D string
   { name:'Cmaj7', positions:[{s:3,f:10,iv:'1'},{s:2,f:12,iv:'5'},{s:1,f:12,iv:'7'},{s:0,f:12,iv:'3'}] },
   { name:'Dmaj7', positions:[{s:3,f:0,iv:'1'},{s:2,f:2,iv:'5'},{s:1,f:2,iv:'7'},{s:0,f:2,iv:'3'}] },
   { name:'Ebmaj7', positions:[{s:3,f:1,iv:'1'},{s:2,f:3,iv:'5'},{s:1,f:3,iv:'7'},{s:0,f:3,iv:'3'}] },
@@ -313,8 +281,7 @@ const CHORD_SHAPES = [
   { name:'Amaj7', positions:[{s:3,f:7,iv:'1'},{s:2,f:9,iv:'5'},{s:1,f:9,iv:'7'},{s:0,f:9,iv:'3'}] },
   { name:'Bbmaj7', positions:[{s:3,f:8,iv:'1'},{s:2,f:10,iv:'5'},{s:1,f:10,iv:'7'},{s:0,f:10,iv:'3'}] },
   { name:'Bmaj7', positions:[{s:3,f:9,iv:'1'},{s:2,f:11,iv:'5'},{s:1,f:11,iv:'7'},{s:0,f:11,iv:'3'}] },
-  // ── MINOR 7TH ───────────────────────────────────────────────────
-  // E-shape
+  // m7, E-shape, root on the low E string
   { name:'Cm7', positions:[{s:5,f:8,iv:'1'},{s:4,f:10,iv:'5'},{s:3,f:8,iv:'b7'},{s:2,f:8,iv:'b3'},{s:1,f:8,iv:'5'},{s:0,f:8,iv:'1'}] },
   { name:'C#m7', positions:[{s:5,f:9,iv:'1'},{s:4,f:11,iv:'5'},{s:3,f:9,iv:'b7'},{s:2,f:9,iv:'b3'},{s:1,f:9,iv:'5'},{s:0,f:9,iv:'1'}] },
   { name:'Dm7', positions:[{s:5,f:10,iv:'1'},{s:4,f:12,iv:'5'},{s:3,f:10,iv:'b7'},{s:2,f:10,iv:'b3'},{s:1,f:10,iv:'5'},{s:0,f:10,iv:'1'}] },
@@ -326,7 +293,7 @@ const CHORD_SHAPES = [
   { name:'Am7', positions:[{s:5,f:5,iv:'1'},{s:4,f:7,iv:'5'},{s:3,f:5,iv:'b7'},{s:2,f:5,iv:'b3'},{s:1,f:5,iv:'5'},{s:0,f:5,iv:'1'}] },
   { name:'Bbm7', positions:[{s:5,f:6,iv:'1'},{s:4,f:8,iv:'5'},{s:3,f:6,iv:'b7'},{s:2,f:6,iv:'b3'},{s:1,f:6,iv:'5'},{s:0,f:6,iv:'1'}] },
   { name:'Bm7', positions:[{s:5,f:7,iv:'1'},{s:4,f:9,iv:'5'},{s:3,f:7,iv:'b7'},{s:2,f:7,iv:'b3'},{s:1,f:7,iv:'5'},{s:0,f:7,iv:'1'}] },
-  // A-shape
+  // m7, A-shape, root on the A string
   { name:'Cm7', positions:[{s:4,f:3,iv:'1'},{s:3,f:5,iv:'5'},{s:2,f:3,iv:'b7'},{s:1,f:4,iv:'b3'},{s:0,f:3,iv:'5'}] },
   { name:'C#m7', positions:[{s:4,f:4,iv:'1'},{s:3,f:6,iv:'5'},{s:2,f:4,iv:'b7'},{s:1,f:5,iv:'b3'},{s:0,f:4,iv:'5'}] },
   { name:'Dm7', positions:[{s:4,f:5,iv:'1'},{s:3,f:7,iv:'5'},{s:2,f:5,iv:'b7'},{s:1,f:6,iv:'b3'},{s:0,f:5,iv:'5'}] },
@@ -338,7 +305,7 @@ const CHORD_SHAPES = [
   { name:'Am7', positions:[{s:4,f:0,iv:'1'},{s:3,f:2,iv:'5'},{s:2,f:0,iv:'b7'},{s:1,f:1,iv:'b3'},{s:0,f:0,iv:'5'}] },
   { name:'Bbm7', positions:[{s:4,f:1,iv:'1'},{s:3,f:3,iv:'5'},{s:2,f:1,iv:'b7'},{s:1,f:2,iv:'b3'},{s:0,f:1,iv:'5'}] },
   { name:'Bm7', positions:[{s:4,f:2,iv:'1'},{s:3,f:4,iv:'5'},{s:2,f:2,iv:'b7'},{s:1,f:3,iv:'b3'},{s:0,f:2,iv:'5'}] },
-  // D-shape
+  // m7, D-shape, root on the D string
   { name:'Cm7', positions:[{s:3,f:10,iv:'1'},{s:2,f:12,iv:'5'},{s:1,f:11,iv:'b7'},{s:0,f:11,iv:'b3'}] },
   { name:'Dm7', positions:[{s:3,f:0,iv:'1'},{s:2,f:2,iv:'5'},{s:1,f:1,iv:'b7'},{s:0,f:1,iv:'b3'}] },
   { name:'Ebm7', positions:[{s:3,f:1,iv:'1'},{s:2,f:3,iv:'5'},{s:1,f:2,iv:'b7'},{s:0,f:2,iv:'b3'}] },
@@ -350,8 +317,7 @@ const CHORD_SHAPES = [
   { name:'Am7', positions:[{s:3,f:7,iv:'1'},{s:2,f:9,iv:'5'},{s:1,f:8,iv:'b7'},{s:0,f:8,iv:'b3'}] },
   { name:'Bbm7', positions:[{s:3,f:8,iv:'1'},{s:2,f:10,iv:'5'},{s:1,f:9,iv:'b7'},{s:0,f:9,iv:'b3'}] },
   { name:'Bm7', positions:[{s:3,f:9,iv:'1'},{s:2,f:11,iv:'5'},{s:1,f:10,iv:'b7'},{s:0,f:10,iv:'b3'}] },
-  // ── 9TH ─────────────────────────────────────────────────────────
-  // E-shape
+  // 9, E-shape, root on the low E string
   { name:'C9', positions:[{s:5,f:8,iv:'1'},{s:4,f:10,iv:'5'},{s:3,f:8,iv:'b7'},{s:2,f:9,iv:'3'},{s:1,f:8,iv:'5'},{s:0,f:10,iv:'9'}] },
   { name:'C#9', positions:[{s:5,f:9,iv:'1'},{s:4,f:11,iv:'5'},{s:3,f:9,iv:'b7'},{s:2,f:10,iv:'3'},{s:1,f:9,iv:'5'},{s:0,f:11,iv:'9'}] },
   { name:'D9', positions:[{s:5,f:10,iv:'1'},{s:4,f:12,iv:'5'},{s:3,f:10,iv:'b7'},{s:2,f:11,iv:'3'},{s:1,f:10,iv:'5'},{s:0,f:12,iv:'9'}] },
@@ -363,9 +329,7 @@ const CHORD_SHAPES = [
   { name:'A9', positions:[{s:5,f:5,iv:'1'},{s:4,f:7,iv:'5'},{s:3,f:5,iv:'b7'},{s:2,f:6,iv:'3'},{s:1,f:5,iv:'5'},{s:0,f:7,iv:'9'}] },
   { name:'Bb9', positions:[{s:5,f:6,iv:'1'},{s:4,f:8,iv:'5'},{s:3,f:6,iv:'b7'},{s:2,f:7,iv:'3'},{s:1,f:6,iv:'5'},{s:0,f:8,iv:'9'}] },
   { name:'B9', positions:[{s:5,f:7,iv:'1'},{s:4,f:9,iv:'5'},{s:3,f:7,iv:'b7'},{s:2,f:8,iv:'3'},{s:1,f:7,iv:'5'},{s:0,f:9,iv:'9'}] },
-  // A-shape
-  { name:'Bb9', positions:[{s:4,f:1,iv:'1'},{s:3,f:0,iv:'3'},{s:2,f:1,iv:'b7'},{s:1,f:1,iv:'9'},{s:0,f:1,iv:'5'}] },
-  { name:'B9', positions:[{s:4,f:2,iv:'1'},{s:3,f:1,iv:'3'},{s:2,f:2,iv:'b7'},{s:1,f:2,iv:'9'},{s:0,f:2,iv:'5'}] },
+  // 9, A-shape, root on the A string
   { name:'C9', positions:[{s:4,f:3,iv:'1'},{s:3,f:2,iv:'3'},{s:2,f:3,iv:'b7'},{s:1,f:3,iv:'9'},{s:0,f:3,iv:'5'}] },
   { name:'C#9', positions:[{s:4,f:4,iv:'1'},{s:3,f:3,iv:'3'},{s:2,f:4,iv:'b7'},{s:1,f:4,iv:'9'},{s:0,f:4,iv:'5'}] },
   { name:'D9', positions:[{s:4,f:5,iv:'1'},{s:3,f:4,iv:'3'},{s:2,f:5,iv:'b7'},{s:1,f:5,iv:'9'},{s:0,f:5,iv:'5'}] },
@@ -376,8 +340,9 @@ const CHORD_SHAPES = [
   { name:'G9', positions:[{s:4,f:10,iv:'1'},{s:3,f:9,iv:'3'},{s:2,f:10,iv:'b7'},{s:1,f:10,iv:'9'},{s:0,f:10,iv:'5'}] },
   { name:'Ab9', positions:[{s:4,f:11,iv:'1'},{s:3,f:10,iv:'3'},{s:2,f:11,iv:'b7'},{s:1,f:11,iv:'9'},{s:0,f:11,iv:'5'}] },
   { name:'A9', positions:[{s:4,f:12,iv:'1'},{s:3,f:11,iv:'3'},{s:2,f:12,iv:'b7'},{s:1,f:12,iv:'9'},{s:0,f:12,iv:'5'}] },
-  // ── 7#9 ─────────────────────────────────────────────────────────
-  // E-shape
+  { name:'Bb9', positions:[{s:4,f:1,iv:'1'},{s:3,f:0,iv:'3'},{s:2,f:1,iv:'b7'},{s:1,f:1,iv:'9'},{s:0,f:1,iv:'5'}] },
+  { name:'B9', positions:[{s:4,f:2,iv:'1'},{s:3,f:1,iv:'3'},{s:2,f:2,iv:'b7'},{s:1,f:2,iv:'9'},{s:0,f:2,iv:'5'}] },
+  // 7#9, E-shape, root on the low E string
   { name:'C7#9', positions:[{s:5,f:8,iv:'1'},{s:4,f:10,iv:'5'},{s:3,f:8,iv:'b7'},{s:2,f:9,iv:'3'},{s:1,f:8,iv:'5'},{s:0,f:11,iv:'#9'}] },
   { name:'C#7#9', positions:[{s:5,f:9,iv:'1'},{s:4,f:11,iv:'5'},{s:3,f:9,iv:'b7'},{s:2,f:10,iv:'3'},{s:1,f:9,iv:'5'},{s:0,f:12,iv:'#9'}] },
   { name:'E7#9', positions:[{s:5,f:0,iv:'1'},{s:4,f:2,iv:'5'},{s:3,f:0,iv:'b7'},{s:2,f:1,iv:'3'},{s:1,f:0,iv:'5'},{s:0,f:3,iv:'#9'}] },
@@ -388,7 +353,7 @@ const CHORD_SHAPES = [
   { name:'A7#9', positions:[{s:5,f:5,iv:'1'},{s:4,f:7,iv:'5'},{s:3,f:5,iv:'b7'},{s:2,f:6,iv:'3'},{s:1,f:5,iv:'5'},{s:0,f:8,iv:'#9'}] },
   { name:'Bb7#9', positions:[{s:5,f:6,iv:'1'},{s:4,f:8,iv:'5'},{s:3,f:6,iv:'b7'},{s:2,f:7,iv:'3'},{s:1,f:6,iv:'5'},{s:0,f:9,iv:'#9'}] },
   { name:'B7#9', positions:[{s:5,f:7,iv:'1'},{s:4,f:9,iv:'5'},{s:3,f:7,iv:'b7'},{s:2,f:8,iv:'3'},{s:1,f:7,iv:'5'},{s:0,f:10,iv:'#9'}] },
-  // compact-shape
+  // 7#9, root on the A string
   { name:'C7#9', positions:[{s:4,f:3,iv:'1'},{s:3,f:2,iv:'3'},{s:2,f:3,iv:'b7'},{s:1,f:4,iv:'#9'}] },
   { name:'C#7#9', positions:[{s:4,f:4,iv:'1'},{s:3,f:3,iv:'3'},{s:2,f:4,iv:'b7'},{s:1,f:5,iv:'#9'}] },
   { name:'D7#9', positions:[{s:4,f:5,iv:'1'},{s:3,f:4,iv:'3'},{s:2,f:5,iv:'b7'},{s:1,f:6,iv:'#9'}] },
@@ -400,58 +365,357 @@ const CHORD_SHAPES = [
   { name:'Ab7#9', positions:[{s:4,f:11,iv:'1'},{s:3,f:10,iv:'3'},{s:2,f:11,iv:'b7'},{s:1,f:12,iv:'#9'}] },
   { name:'Bb7#9', positions:[{s:4,f:1,iv:'1'},{s:3,f:0,iv:'3'},{s:2,f:1,iv:'b7'},{s:1,f:2,iv:'#9'}] },
   { name:'B7#9', positions:[{s:4,f:2,iv:'1'},{s:3,f:1,iv:'3'},{s:2,f:2,iv:'b7'},{s:1,f:3,iv:'#9'}] },
+  // 6, root on the A string
+  { name:'C6', positions:[{s:4,f:3,iv:'1'},{s:3,f:5,iv:'5'},{s:2,f:5,iv:'1'},{s:1,f:5,iv:'3'},{s:0,f:5,iv:'6'}] },
+  { name:'C#6', positions:[{s:4,f:4,iv:'1'},{s:3,f:6,iv:'5'},{s:2,f:6,iv:'1'},{s:1,f:6,iv:'3'},{s:0,f:6,iv:'6'}] },
+  { name:'D6', positions:[{s:4,f:5,iv:'1'},{s:3,f:7,iv:'5'},{s:2,f:7,iv:'1'},{s:1,f:7,iv:'3'},{s:0,f:7,iv:'6'}] },
+  { name:'Eb6', positions:[{s:4,f:6,iv:'1'},{s:3,f:8,iv:'5'},{s:2,f:8,iv:'1'},{s:1,f:8,iv:'3'},{s:0,f:8,iv:'6'}] },
+  { name:'E6', positions:[{s:4,f:7,iv:'1'},{s:3,f:9,iv:'5'},{s:2,f:9,iv:'1'},{s:1,f:9,iv:'3'},{s:0,f:9,iv:'6'}] },
+  { name:'F6', positions:[{s:4,f:8,iv:'1'},{s:3,f:10,iv:'5'},{s:2,f:10,iv:'1'},{s:1,f:10,iv:'3'},{s:0,f:10,iv:'6'}] },
+  { name:'F#6', positions:[{s:4,f:9,iv:'1'},{s:3,f:11,iv:'5'},{s:2,f:11,iv:'1'},{s:1,f:11,iv:'3'},{s:0,f:11,iv:'6'}] },
+  { name:'G6', positions:[{s:4,f:10,iv:'1'},{s:3,f:12,iv:'5'},{s:2,f:12,iv:'1'},{s:1,f:12,iv:'3'},{s:0,f:12,iv:'6'}] },
+  { name:'A6', positions:[{s:4,f:0,iv:'1'},{s:3,f:2,iv:'5'},{s:2,f:2,iv:'1'},{s:1,f:2,iv:'3'},{s:0,f:2,iv:'6'}] },
+  { name:'Bb6', positions:[{s:4,f:1,iv:'1'},{s:3,f:3,iv:'5'},{s:2,f:3,iv:'1'},{s:1,f:3,iv:'3'},{s:0,f:3,iv:'6'}] },
+  { name:'B6', positions:[{s:4,f:2,iv:'1'},{s:3,f:4,iv:'5'},{s:2,f:4,iv:'1'},{s:1,f:4,iv:'3'},{s:0,f:4,iv:'6'}] },
+  // 6, root on the D string
+  { name:'C6', positions:[{s:3,f:10,iv:'1'},{s:2,f:12,iv:'5'},{s:1,f:10,iv:'6'},{s:0,f:12,iv:'3'}] },
+  { name:'D6', positions:[{s:3,f:0,iv:'1'},{s:2,f:2,iv:'5'},{s:1,f:0,iv:'6'},{s:0,f:2,iv:'3'}] },
+  { name:'Eb6', positions:[{s:3,f:1,iv:'1'},{s:2,f:3,iv:'5'},{s:1,f:1,iv:'6'},{s:0,f:3,iv:'3'}] },
+  { name:'E6', positions:[{s:3,f:2,iv:'1'},{s:2,f:4,iv:'5'},{s:1,f:2,iv:'6'},{s:0,f:4,iv:'3'}] },
+  { name:'F6', positions:[{s:3,f:3,iv:'1'},{s:2,f:5,iv:'5'},{s:1,f:3,iv:'6'},{s:0,f:5,iv:'3'}] },
+  { name:'F#6', positions:[{s:3,f:4,iv:'1'},{s:2,f:6,iv:'5'},{s:1,f:4,iv:'6'},{s:0,f:6,iv:'3'}] },
+  { name:'G6', positions:[{s:3,f:5,iv:'1'},{s:2,f:7,iv:'5'},{s:1,f:5,iv:'6'},{s:0,f:7,iv:'3'}] },
+  { name:'Ab6', positions:[{s:3,f:6,iv:'1'},{s:2,f:8,iv:'5'},{s:1,f:6,iv:'6'},{s:0,f:8,iv:'3'}] },
+  { name:'A6', positions:[{s:3,f:7,iv:'1'},{s:2,f:9,iv:'5'},{s:1,f:7,iv:'6'},{s:0,f:9,iv:'3'}] },
+  { name:'Bb6', positions:[{s:3,f:8,iv:'1'},{s:2,f:10,iv:'5'},{s:1,f:8,iv:'6'},{s:0,f:10,iv:'3'}] },
+  { name:'B6', positions:[{s:3,f:9,iv:'1'},{s:2,f:11,iv:'5'},{s:1,f:9,iv:'6'},{s:0,f:11,iv:'3'}] },
+  // m6, root on the A string
+  { name:'Cm6', positions:[{s:4,f:3,iv:'1'},{s:3,f:5,iv:'5'},{s:2,f:5,iv:'1'},{s:1,f:4,iv:'b3'},{s:0,f:5,iv:'6'}] },
+  { name:'C#m6', positions:[{s:4,f:4,iv:'1'},{s:3,f:6,iv:'5'},{s:2,f:6,iv:'1'},{s:1,f:5,iv:'b3'},{s:0,f:6,iv:'6'}] },
+  { name:'Dm6', positions:[{s:4,f:5,iv:'1'},{s:3,f:7,iv:'5'},{s:2,f:7,iv:'1'},{s:1,f:6,iv:'b3'},{s:0,f:7,iv:'6'}] },
+  { name:'Ebm6', positions:[{s:4,f:6,iv:'1'},{s:3,f:8,iv:'5'},{s:2,f:8,iv:'1'},{s:1,f:7,iv:'b3'},{s:0,f:8,iv:'6'}] },
+  { name:'Em6', positions:[{s:4,f:7,iv:'1'},{s:3,f:9,iv:'5'},{s:2,f:9,iv:'1'},{s:1,f:8,iv:'b3'},{s:0,f:9,iv:'6'}] },
+  { name:'Fm6', positions:[{s:4,f:8,iv:'1'},{s:3,f:10,iv:'5'},{s:2,f:10,iv:'1'},{s:1,f:9,iv:'b3'},{s:0,f:10,iv:'6'}] },
+  { name:'F#m6', positions:[{s:4,f:9,iv:'1'},{s:3,f:11,iv:'5'},{s:2,f:11,iv:'1'},{s:1,f:10,iv:'b3'},{s:0,f:11,iv:'6'}] },
+  { name:'Gm6', positions:[{s:4,f:10,iv:'1'},{s:3,f:12,iv:'5'},{s:2,f:12,iv:'1'},{s:1,f:11,iv:'b3'},{s:0,f:12,iv:'6'}] },
+  { name:'Am6', positions:[{s:4,f:0,iv:'1'},{s:3,f:2,iv:'5'},{s:2,f:2,iv:'1'},{s:1,f:1,iv:'b3'},{s:0,f:2,iv:'6'}] },
+  { name:'Bbm6', positions:[{s:4,f:1,iv:'1'},{s:3,f:3,iv:'5'},{s:2,f:3,iv:'1'},{s:1,f:2,iv:'b3'},{s:0,f:3,iv:'6'}] },
+  { name:'Bm6', positions:[{s:4,f:2,iv:'1'},{s:3,f:4,iv:'5'},{s:2,f:4,iv:'1'},{s:1,f:3,iv:'b3'},{s:0,f:4,iv:'6'}] },
+  // m6, root on the D string
+  { name:'Cm6', positions:[{s:3,f:10,iv:'1'},{s:2,f:12,iv:'5'},{s:1,f:10,iv:'6'},{s:0,f:11,iv:'b3'}] },
+  { name:'Dm6', positions:[{s:3,f:0,iv:'1'},{s:2,f:2,iv:'5'},{s:1,f:0,iv:'6'},{s:0,f:1,iv:'b3'}] },
+  { name:'Ebm6', positions:[{s:3,f:1,iv:'1'},{s:2,f:3,iv:'5'},{s:1,f:1,iv:'6'},{s:0,f:2,iv:'b3'}] },
+  { name:'Em6', positions:[{s:3,f:2,iv:'1'},{s:2,f:4,iv:'5'},{s:1,f:2,iv:'6'},{s:0,f:3,iv:'b3'}] },
+  { name:'Fm6', positions:[{s:3,f:3,iv:'1'},{s:2,f:5,iv:'5'},{s:1,f:3,iv:'6'},{s:0,f:4,iv:'b3'}] },
+  { name:'F#m6', positions:[{s:3,f:4,iv:'1'},{s:2,f:6,iv:'5'},{s:1,f:4,iv:'6'},{s:0,f:5,iv:'b3'}] },
+  { name:'Gm6', positions:[{s:3,f:5,iv:'1'},{s:2,f:7,iv:'5'},{s:1,f:5,iv:'6'},{s:0,f:6,iv:'b3'}] },
+  { name:'Abm6', positions:[{s:3,f:6,iv:'1'},{s:2,f:8,iv:'5'},{s:1,f:6,iv:'6'},{s:0,f:7,iv:'b3'}] },
+  { name:'Am6', positions:[{s:3,f:7,iv:'1'},{s:2,f:9,iv:'5'},{s:1,f:7,iv:'6'},{s:0,f:8,iv:'b3'}] },
+  { name:'Bbm6', positions:[{s:3,f:8,iv:'1'},{s:2,f:10,iv:'5'},{s:1,f:8,iv:'6'},{s:0,f:9,iv:'b3'}] },
+  { name:'Bm6', positions:[{s:3,f:9,iv:'1'},{s:2,f:11,iv:'5'},{s:1,f:9,iv:'6'},{s:0,f:10,iv:'b3'}] },
+  // 6/9, root on the A string
+  { name:'C6/9', positions:[{s:4,f:3,iv:'1'},{s:3,f:2,iv:'3'},{s:2,f:2,iv:'6'},{s:1,f:3,iv:'9'},{s:0,f:3,iv:'5'}] },
+  { name:'C#6/9', positions:[{s:4,f:4,iv:'1'},{s:3,f:3,iv:'3'},{s:2,f:3,iv:'6'},{s:1,f:4,iv:'9'},{s:0,f:4,iv:'5'}] },
+  { name:'D6/9', positions:[{s:4,f:5,iv:'1'},{s:3,f:4,iv:'3'},{s:2,f:4,iv:'6'},{s:1,f:5,iv:'9'},{s:0,f:5,iv:'5'}] },
+  { name:'Eb6/9', positions:[{s:4,f:6,iv:'1'},{s:3,f:5,iv:'3'},{s:2,f:5,iv:'6'},{s:1,f:6,iv:'9'},{s:0,f:6,iv:'5'}] },
+  { name:'E6/9', positions:[{s:4,f:7,iv:'1'},{s:3,f:6,iv:'3'},{s:2,f:6,iv:'6'},{s:1,f:7,iv:'9'},{s:0,f:7,iv:'5'}] },
+  { name:'F6/9', positions:[{s:4,f:8,iv:'1'},{s:3,f:7,iv:'3'},{s:2,f:7,iv:'6'},{s:1,f:8,iv:'9'},{s:0,f:8,iv:'5'}] },
+  { name:'F#6/9', positions:[{s:4,f:9,iv:'1'},{s:3,f:8,iv:'3'},{s:2,f:8,iv:'6'},{s:1,f:9,iv:'9'},{s:0,f:9,iv:'5'}] },
+  { name:'G6/9', positions:[{s:4,f:10,iv:'1'},{s:3,f:9,iv:'3'},{s:2,f:9,iv:'6'},{s:1,f:10,iv:'9'},{s:0,f:10,iv:'5'}] },
+  { name:'Ab6/9', positions:[{s:4,f:11,iv:'1'},{s:3,f:10,iv:'3'},{s:2,f:10,iv:'6'},{s:1,f:11,iv:'9'},{s:0,f:11,iv:'5'}] },
+  { name:'A6/9', positions:[{s:4,f:12,iv:'1'},{s:3,f:11,iv:'3'},{s:2,f:11,iv:'6'},{s:1,f:12,iv:'9'},{s:0,f:12,iv:'5'}] },
+  { name:'Bb6/9', positions:[{s:4,f:1,iv:'1'},{s:3,f:0,iv:'3'},{s:2,f:0,iv:'6'},{s:1,f:1,iv:'9'},{s:0,f:1,iv:'5'}] },
+  { name:'B6/9', positions:[{s:4,f:2,iv:'1'},{s:3,f:1,iv:'3'},{s:2,f:1,iv:'6'},{s:1,f:2,iv:'9'},{s:0,f:2,iv:'5'}] },
+  // maj9, root on the A string
+  { name:'Cmaj9', positions:[{s:4,f:3,iv:'1'},{s:3,f:2,iv:'3'},{s:2,f:4,iv:'7'},{s:1,f:3,iv:'9'}] },
+  { name:'C#maj9', positions:[{s:4,f:4,iv:'1'},{s:3,f:3,iv:'3'},{s:2,f:5,iv:'7'},{s:1,f:4,iv:'9'}] },
+  { name:'Dmaj9', positions:[{s:4,f:5,iv:'1'},{s:3,f:4,iv:'3'},{s:2,f:6,iv:'7'},{s:1,f:5,iv:'9'}] },
+  { name:'Ebmaj9', positions:[{s:4,f:6,iv:'1'},{s:3,f:5,iv:'3'},{s:2,f:7,iv:'7'},{s:1,f:6,iv:'9'}] },
+  { name:'Emaj9', positions:[{s:4,f:7,iv:'1'},{s:3,f:6,iv:'3'},{s:2,f:8,iv:'7'},{s:1,f:7,iv:'9'}] },
+  { name:'Fmaj9', positions:[{s:4,f:8,iv:'1'},{s:3,f:7,iv:'3'},{s:2,f:9,iv:'7'},{s:1,f:8,iv:'9'}] },
+  { name:'F#maj9', positions:[{s:4,f:9,iv:'1'},{s:3,f:8,iv:'3'},{s:2,f:10,iv:'7'},{s:1,f:9,iv:'9'}] },
+  { name:'Gmaj9', positions:[{s:4,f:10,iv:'1'},{s:3,f:9,iv:'3'},{s:2,f:11,iv:'7'},{s:1,f:10,iv:'9'}] },
+  { name:'Abmaj9', positions:[{s:4,f:11,iv:'1'},{s:3,f:10,iv:'3'},{s:2,f:12,iv:'7'},{s:1,f:11,iv:'9'}] },
+  { name:'Bbmaj9', positions:[{s:4,f:1,iv:'1'},{s:3,f:0,iv:'3'},{s:2,f:2,iv:'7'},{s:1,f:1,iv:'9'}] },
+  { name:'Bmaj9', positions:[{s:4,f:2,iv:'1'},{s:3,f:1,iv:'3'},{s:2,f:3,iv:'7'},{s:1,f:2,iv:'9'}] },
+  // m9, root on the A string
+  { name:'Cm9', positions:[{s:4,f:3,iv:'1'},{s:3,f:1,iv:'b3'},{s:2,f:3,iv:'b7'},{s:1,f:3,iv:'9'},{s:0,f:3,iv:'5'}] },
+  { name:'C#m9', positions:[{s:4,f:4,iv:'1'},{s:3,f:2,iv:'b3'},{s:2,f:4,iv:'b7'},{s:1,f:4,iv:'9'},{s:0,f:4,iv:'5'}] },
+  { name:'Dm9', positions:[{s:4,f:5,iv:'1'},{s:3,f:3,iv:'b3'},{s:2,f:5,iv:'b7'},{s:1,f:5,iv:'9'},{s:0,f:5,iv:'5'}] },
+  { name:'Ebm9', positions:[{s:4,f:6,iv:'1'},{s:3,f:4,iv:'b3'},{s:2,f:6,iv:'b7'},{s:1,f:6,iv:'9'},{s:0,f:6,iv:'5'}] },
+  { name:'Em9', positions:[{s:4,f:7,iv:'1'},{s:3,f:5,iv:'b3'},{s:2,f:7,iv:'b7'},{s:1,f:7,iv:'9'},{s:0,f:7,iv:'5'}] },
+  { name:'Fm9', positions:[{s:4,f:8,iv:'1'},{s:3,f:6,iv:'b3'},{s:2,f:8,iv:'b7'},{s:1,f:8,iv:'9'},{s:0,f:8,iv:'5'}] },
+  { name:'F#m9', positions:[{s:4,f:9,iv:'1'},{s:3,f:7,iv:'b3'},{s:2,f:9,iv:'b7'},{s:1,f:9,iv:'9'},{s:0,f:9,iv:'5'}] },
+  { name:'Gm9', positions:[{s:4,f:10,iv:'1'},{s:3,f:8,iv:'b3'},{s:2,f:10,iv:'b7'},{s:1,f:10,iv:'9'},{s:0,f:10,iv:'5'}] },
+  { name:'Abm9', positions:[{s:4,f:11,iv:'1'},{s:3,f:9,iv:'b3'},{s:2,f:11,iv:'b7'},{s:1,f:11,iv:'9'},{s:0,f:11,iv:'5'}] },
+  { name:'Am9', positions:[{s:4,f:12,iv:'1'},{s:3,f:10,iv:'b3'},{s:2,f:12,iv:'b7'},{s:1,f:12,iv:'9'},{s:0,f:12,iv:'5'}] },
+  { name:'Bm9', positions:[{s:4,f:2,iv:'1'},{s:3,f:0,iv:'b3'},{s:2,f:2,iv:'b7'},{s:1,f:2,iv:'9'},{s:0,f:2,iv:'5'}] },
+  // 13, root on the low E string
+  { name:'C13', positions:[{s:5,f:8,iv:'1'},{s:3,f:8,iv:'b7'},{s:2,f:9,iv:'3'},{s:1,f:10,iv:'13'}] },
+  { name:'C#13', positions:[{s:5,f:9,iv:'1'},{s:3,f:9,iv:'b7'},{s:2,f:10,iv:'3'},{s:1,f:11,iv:'13'}] },
+  { name:'D13', positions:[{s:5,f:10,iv:'1'},{s:3,f:10,iv:'b7'},{s:2,f:11,iv:'3'},{s:1,f:12,iv:'13'}] },
+  { name:'E13', positions:[{s:5,f:0,iv:'1'},{s:3,f:0,iv:'b7'},{s:2,f:1,iv:'3'},{s:1,f:2,iv:'13'}] },
+  { name:'F13', positions:[{s:5,f:1,iv:'1'},{s:3,f:1,iv:'b7'},{s:2,f:2,iv:'3'},{s:1,f:3,iv:'13'}] },
+  { name:'F#13', positions:[{s:5,f:2,iv:'1'},{s:3,f:2,iv:'b7'},{s:2,f:3,iv:'3'},{s:1,f:4,iv:'13'}] },
+  { name:'G13', positions:[{s:5,f:3,iv:'1'},{s:3,f:3,iv:'b7'},{s:2,f:4,iv:'3'},{s:1,f:5,iv:'13'}] },
+  { name:'Ab13', positions:[{s:5,f:4,iv:'1'},{s:3,f:4,iv:'b7'},{s:2,f:5,iv:'3'},{s:1,f:6,iv:'13'}] },
+  { name:'A13', positions:[{s:5,f:5,iv:'1'},{s:3,f:5,iv:'b7'},{s:2,f:6,iv:'3'},{s:1,f:7,iv:'13'}] },
+  { name:'Bb13', positions:[{s:5,f:6,iv:'1'},{s:3,f:6,iv:'b7'},{s:2,f:7,iv:'3'},{s:1,f:8,iv:'13'}] },
+  { name:'B13', positions:[{s:5,f:7,iv:'1'},{s:3,f:7,iv:'b7'},{s:2,f:8,iv:'3'},{s:1,f:9,iv:'13'}] },
+  // m(maj7), root on the A string
+  { name:'Cm(maj7)', positions:[{s:4,f:3,iv:'1'},{s:3,f:5,iv:'5'},{s:2,f:4,iv:'7'},{s:1,f:4,iv:'b3'}] },
+  { name:'C#m(maj7)', positions:[{s:4,f:4,iv:'1'},{s:3,f:6,iv:'5'},{s:2,f:5,iv:'7'},{s:1,f:5,iv:'b3'}] },
+  { name:'Dm(maj7)', positions:[{s:4,f:5,iv:'1'},{s:3,f:7,iv:'5'},{s:2,f:6,iv:'7'},{s:1,f:6,iv:'b3'}] },
+  { name:'Ebm(maj7)', positions:[{s:4,f:6,iv:'1'},{s:3,f:8,iv:'5'},{s:2,f:7,iv:'7'},{s:1,f:7,iv:'b3'}] },
+  { name:'Em(maj7)', positions:[{s:4,f:7,iv:'1'},{s:3,f:9,iv:'5'},{s:2,f:8,iv:'7'},{s:1,f:8,iv:'b3'}] },
+  { name:'Fm(maj7)', positions:[{s:4,f:8,iv:'1'},{s:3,f:10,iv:'5'},{s:2,f:9,iv:'7'},{s:1,f:9,iv:'b3'}] },
+  { name:'F#m(maj7)', positions:[{s:4,f:9,iv:'1'},{s:3,f:11,iv:'5'},{s:2,f:10,iv:'7'},{s:1,f:10,iv:'b3'}] },
+  { name:'Gm(maj7)', positions:[{s:4,f:10,iv:'1'},{s:3,f:12,iv:'5'},{s:2,f:11,iv:'7'},{s:1,f:11,iv:'b3'}] },
+  { name:'Am(maj7)', positions:[{s:4,f:0,iv:'1'},{s:3,f:2,iv:'5'},{s:2,f:1,iv:'7'},{s:1,f:1,iv:'b3'}] },
+  { name:'Bbm(maj7)', positions:[{s:4,f:1,iv:'1'},{s:3,f:3,iv:'5'},{s:2,f:2,iv:'7'},{s:1,f:2,iv:'b3'}] },
+  { name:'Bm(maj7)', positions:[{s:4,f:2,iv:'1'},{s:3,f:4,iv:'5'},{s:2,f:3,iv:'7'},{s:1,f:3,iv:'b3'}] },
+  // m7b5, root on the A string
+  { name:'Cm7b5', positions:[{s:4,f:3,iv:'1'},{s:3,f:4,iv:'b5'},{s:2,f:3,iv:'b7'},{s:1,f:4,iv:'b3'}] },
+  { name:'C#m7b5', positions:[{s:4,f:4,iv:'1'},{s:3,f:5,iv:'b5'},{s:2,f:4,iv:'b7'},{s:1,f:5,iv:'b3'}] },
+  { name:'Dm7b5', positions:[{s:4,f:5,iv:'1'},{s:3,f:6,iv:'b5'},{s:2,f:5,iv:'b7'},{s:1,f:6,iv:'b3'}] },
+  { name:'Ebm7b5', positions:[{s:4,f:6,iv:'1'},{s:3,f:7,iv:'b5'},{s:2,f:6,iv:'b7'},{s:1,f:7,iv:'b3'}] },
+  { name:'Em7b5', positions:[{s:4,f:7,iv:'1'},{s:3,f:8,iv:'b5'},{s:2,f:7,iv:'b7'},{s:1,f:8,iv:'b3'}] },
+  { name:'Fm7b5', positions:[{s:4,f:8,iv:'1'},{s:3,f:9,iv:'b5'},{s:2,f:8,iv:'b7'},{s:1,f:9,iv:'b3'}] },
+  { name:'F#m7b5', positions:[{s:4,f:9,iv:'1'},{s:3,f:10,iv:'b5'},{s:2,f:9,iv:'b7'},{s:1,f:10,iv:'b3'}] },
+  { name:'Gm7b5', positions:[{s:4,f:10,iv:'1'},{s:3,f:11,iv:'b5'},{s:2,f:10,iv:'b7'},{s:1,f:11,iv:'b3'}] },
+  { name:'Abm7b5', positions:[{s:4,f:11,iv:'1'},{s:3,f:12,iv:'b5'},{s:2,f:11,iv:'b7'},{s:1,f:12,iv:'b3'}] },
+  { name:'Am7b5', positions:[{s:4,f:0,iv:'1'},{s:3,f:1,iv:'b5'},{s:2,f:0,iv:'b7'},{s:1,f:1,iv:'b3'}] },
+  { name:'Bbm7b5', positions:[{s:4,f:1,iv:'1'},{s:3,f:2,iv:'b5'},{s:2,f:1,iv:'b7'},{s:1,f:2,iv:'b3'}] },
+  { name:'Bm7b5', positions:[{s:4,f:2,iv:'1'},{s:3,f:3,iv:'b5'},{s:2,f:2,iv:'b7'},{s:1,f:3,iv:'b3'}] },
+  // m7b5, root on the low E string
+  { name:'Cm7b5', positions:[{s:5,f:8,iv:'1'},{s:3,f:8,iv:'b7'},{s:2,f:8,iv:'b3'},{s:1,f:7,iv:'b5'}] },
+  { name:'C#m7b5', positions:[{s:5,f:9,iv:'1'},{s:3,f:9,iv:'b7'},{s:2,f:9,iv:'b3'},{s:1,f:8,iv:'b5'}] },
+  { name:'Dm7b5', positions:[{s:5,f:10,iv:'1'},{s:3,f:10,iv:'b7'},{s:2,f:10,iv:'b3'},{s:1,f:9,iv:'b5'}] },
+  { name:'Ebm7b5', positions:[{s:5,f:11,iv:'1'},{s:3,f:11,iv:'b7'},{s:2,f:11,iv:'b3'},{s:1,f:10,iv:'b5'}] },
+  { name:'Em7b5', positions:[{s:5,f:12,iv:'1'},{s:3,f:12,iv:'b7'},{s:2,f:12,iv:'b3'},{s:1,f:11,iv:'b5'}] },
+  { name:'Fm7b5', positions:[{s:5,f:1,iv:'1'},{s:3,f:1,iv:'b7'},{s:2,f:1,iv:'b3'},{s:1,f:0,iv:'b5'}] },
+  { name:'F#m7b5', positions:[{s:5,f:2,iv:'1'},{s:3,f:2,iv:'b7'},{s:2,f:2,iv:'b3'},{s:1,f:1,iv:'b5'}] },
+  { name:'Gm7b5', positions:[{s:5,f:3,iv:'1'},{s:3,f:3,iv:'b7'},{s:2,f:3,iv:'b3'},{s:1,f:2,iv:'b5'}] },
+  { name:'Abm7b5', positions:[{s:5,f:4,iv:'1'},{s:3,f:4,iv:'b7'},{s:2,f:4,iv:'b3'},{s:1,f:3,iv:'b5'}] },
+  { name:'Am7b5', positions:[{s:5,f:5,iv:'1'},{s:3,f:5,iv:'b7'},{s:2,f:5,iv:'b3'},{s:1,f:4,iv:'b5'}] },
+  { name:'Bbm7b5', positions:[{s:5,f:6,iv:'1'},{s:3,f:6,iv:'b7'},{s:2,f:6,iv:'b3'},{s:1,f:5,iv:'b5'}] },
+  { name:'Bm7b5', positions:[{s:5,f:7,iv:'1'},{s:3,f:7,iv:'b7'},{s:2,f:7,iv:'b3'},{s:1,f:6,iv:'b5'}] },
+  // dim7, root on the A string
+  { name:'Cdim7', positions:[{s:4,f:3,iv:'1'},{s:3,f:4,iv:'b5'},{s:2,f:2,iv:'bb7'},{s:1,f:4,iv:'b3'}] },
+  { name:'C#dim7', positions:[{s:4,f:4,iv:'1'},{s:3,f:5,iv:'b5'},{s:2,f:3,iv:'bb7'},{s:1,f:5,iv:'b3'}] },
+  { name:'Ddim7', positions:[{s:4,f:5,iv:'1'},{s:3,f:6,iv:'b5'},{s:2,f:4,iv:'bb7'},{s:1,f:6,iv:'b3'}] },
+  { name:'Ebdim7', positions:[{s:4,f:6,iv:'1'},{s:3,f:7,iv:'b5'},{s:2,f:5,iv:'bb7'},{s:1,f:7,iv:'b3'}] },
+  { name:'Edim7', positions:[{s:4,f:7,iv:'1'},{s:3,f:8,iv:'b5'},{s:2,f:6,iv:'bb7'},{s:1,f:8,iv:'b3'}] },
+  { name:'Fdim7', positions:[{s:4,f:8,iv:'1'},{s:3,f:9,iv:'b5'},{s:2,f:7,iv:'bb7'},{s:1,f:9,iv:'b3'}] },
+  { name:'F#dim7', positions:[{s:4,f:9,iv:'1'},{s:3,f:10,iv:'b5'},{s:2,f:8,iv:'bb7'},{s:1,f:10,iv:'b3'}] },
+  { name:'Gdim7', positions:[{s:4,f:10,iv:'1'},{s:3,f:11,iv:'b5'},{s:2,f:9,iv:'bb7'},{s:1,f:11,iv:'b3'}] },
+  { name:'Abdim7', positions:[{s:4,f:11,iv:'1'},{s:3,f:12,iv:'b5'},{s:2,f:10,iv:'bb7'},{s:1,f:12,iv:'b3'}] },
+  { name:'Bbdim7', positions:[{s:4,f:1,iv:'1'},{s:3,f:2,iv:'b5'},{s:2,f:0,iv:'bb7'},{s:1,f:2,iv:'b3'}] },
+  { name:'Bdim7', positions:[{s:4,f:2,iv:'1'},{s:3,f:3,iv:'b5'},{s:2,f:1,iv:'bb7'},{s:1,f:3,iv:'b3'}] },
+  // dim7, root on the D string
+  { name:'Cdim7', positions:[{s:3,f:10,iv:'1'},{s:2,f:11,iv:'b5'},{s:1,f:10,iv:'bb7'},{s:0,f:11,iv:'b3'}] },
+  { name:'C#dim7', positions:[{s:3,f:11,iv:'1'},{s:2,f:12,iv:'b5'},{s:1,f:11,iv:'bb7'},{s:0,f:12,iv:'b3'}] },
+  { name:'Ddim7', positions:[{s:3,f:0,iv:'1'},{s:2,f:1,iv:'b5'},{s:1,f:0,iv:'bb7'},{s:0,f:1,iv:'b3'}] },
+  { name:'Ebdim7', positions:[{s:3,f:1,iv:'1'},{s:2,f:2,iv:'b5'},{s:1,f:1,iv:'bb7'},{s:0,f:2,iv:'b3'}] },
+  { name:'Edim7', positions:[{s:3,f:2,iv:'1'},{s:2,f:3,iv:'b5'},{s:1,f:2,iv:'bb7'},{s:0,f:3,iv:'b3'}] },
+  { name:'Fdim7', positions:[{s:3,f:3,iv:'1'},{s:2,f:4,iv:'b5'},{s:1,f:3,iv:'bb7'},{s:0,f:4,iv:'b3'}] },
+  { name:'F#dim7', positions:[{s:3,f:4,iv:'1'},{s:2,f:5,iv:'b5'},{s:1,f:4,iv:'bb7'},{s:0,f:5,iv:'b3'}] },
+  { name:'Gdim7', positions:[{s:3,f:5,iv:'1'},{s:2,f:6,iv:'b5'},{s:1,f:5,iv:'bb7'},{s:0,f:6,iv:'b3'}] },
+  { name:'Abdim7', positions:[{s:3,f:6,iv:'1'},{s:2,f:7,iv:'b5'},{s:1,f:6,iv:'bb7'},{s:0,f:7,iv:'b3'}] },
+  { name:'Adim7', positions:[{s:3,f:7,iv:'1'},{s:2,f:8,iv:'b5'},{s:1,f:7,iv:'bb7'},{s:0,f:8,iv:'b3'}] },
+  { name:'Bbdim7', positions:[{s:3,f:8,iv:'1'},{s:2,f:9,iv:'b5'},{s:1,f:8,iv:'bb7'},{s:0,f:9,iv:'b3'}] },
+  { name:'Bdim7', positions:[{s:3,f:9,iv:'1'},{s:2,f:10,iv:'b5'},{s:1,f:9,iv:'bb7'},{s:0,f:10,iv:'b3'}] },
+  // aug, root on the A string
+  { name:'Caug', positions:[{s:4,f:3,iv:'1'},{s:3,f:2,iv:'3'},{s:2,f:1,iv:'#5'},{s:1,f:1,iv:'1'}] },
+  { name:'C#aug', positions:[{s:4,f:4,iv:'1'},{s:3,f:3,iv:'3'},{s:2,f:2,iv:'#5'},{s:1,f:2,iv:'1'}] },
+  { name:'Daug', positions:[{s:4,f:5,iv:'1'},{s:3,f:4,iv:'3'},{s:2,f:3,iv:'#5'},{s:1,f:3,iv:'1'}] },
+  { name:'Ebaug', positions:[{s:4,f:6,iv:'1'},{s:3,f:5,iv:'3'},{s:2,f:4,iv:'#5'},{s:1,f:4,iv:'1'}] },
+  { name:'Eaug', positions:[{s:4,f:7,iv:'1'},{s:3,f:6,iv:'3'},{s:2,f:5,iv:'#5'},{s:1,f:5,iv:'1'}] },
+  { name:'Faug', positions:[{s:4,f:8,iv:'1'},{s:3,f:7,iv:'3'},{s:2,f:6,iv:'#5'},{s:1,f:6,iv:'1'}] },
+  { name:'F#aug', positions:[{s:4,f:9,iv:'1'},{s:3,f:8,iv:'3'},{s:2,f:7,iv:'#5'},{s:1,f:7,iv:'1'}] },
+  { name:'Gaug', positions:[{s:4,f:10,iv:'1'},{s:3,f:9,iv:'3'},{s:2,f:8,iv:'#5'},{s:1,f:8,iv:'1'}] },
+  { name:'Abaug', positions:[{s:4,f:11,iv:'1'},{s:3,f:10,iv:'3'},{s:2,f:9,iv:'#5'},{s:1,f:9,iv:'1'}] },
+  { name:'Aaug', positions:[{s:4,f:12,iv:'1'},{s:3,f:11,iv:'3'},{s:2,f:10,iv:'#5'},{s:1,f:10,iv:'1'}] },
+  { name:'Baug', positions:[{s:4,f:2,iv:'1'},{s:3,f:1,iv:'3'},{s:2,f:0,iv:'#5'},{s:1,f:0,iv:'1'}] },
+  // aug, root on the low E string
+  { name:'Caug', positions:[{s:5,f:8,iv:'1'},{s:3,f:10,iv:'1'},{s:2,f:9,iv:'3'},{s:1,f:9,iv:'#5'}] },
+  { name:'C#aug', positions:[{s:5,f:9,iv:'1'},{s:3,f:11,iv:'1'},{s:2,f:10,iv:'3'},{s:1,f:10,iv:'#5'}] },
+  { name:'Daug', positions:[{s:5,f:10,iv:'1'},{s:3,f:12,iv:'1'},{s:2,f:11,iv:'3'},{s:1,f:11,iv:'#5'}] },
+  { name:'Eaug', positions:[{s:5,f:0,iv:'1'},{s:3,f:2,iv:'1'},{s:2,f:1,iv:'3'},{s:1,f:1,iv:'#5'}] },
+  { name:'Faug', positions:[{s:5,f:1,iv:'1'},{s:3,f:3,iv:'1'},{s:2,f:2,iv:'3'},{s:1,f:2,iv:'#5'}] },
+  { name:'F#aug', positions:[{s:5,f:2,iv:'1'},{s:3,f:4,iv:'1'},{s:2,f:3,iv:'3'},{s:1,f:3,iv:'#5'}] },
+  { name:'Gaug', positions:[{s:5,f:3,iv:'1'},{s:3,f:5,iv:'1'},{s:2,f:4,iv:'3'},{s:1,f:4,iv:'#5'}] },
+  { name:'Abaug', positions:[{s:5,f:4,iv:'1'},{s:3,f:6,iv:'1'},{s:2,f:5,iv:'3'},{s:1,f:5,iv:'#5'}] },
+  { name:'Aaug', positions:[{s:5,f:5,iv:'1'},{s:3,f:7,iv:'1'},{s:2,f:6,iv:'3'},{s:1,f:6,iv:'#5'}] },
+  { name:'Bbaug', positions:[{s:5,f:6,iv:'1'},{s:3,f:8,iv:'1'},{s:2,f:7,iv:'3'},{s:1,f:7,iv:'#5'}] },
+  { name:'Baug', positions:[{s:5,f:7,iv:'1'},{s:3,f:9,iv:'1'},{s:2,f:8,iv:'3'},{s:1,f:8,iv:'#5'}] },
+  // 7sus4, root on the low E string
+  { name:'C7sus4', positions:[{s:5,f:8,iv:'1'},{s:4,f:10,iv:'5'},{s:3,f:8,iv:'b7'},{s:2,f:10,iv:'4'},{s:1,f:8,iv:'5'},{s:0,f:8,iv:'1'}] },
+  { name:'C#7sus4', positions:[{s:5,f:9,iv:'1'},{s:4,f:11,iv:'5'},{s:3,f:9,iv:'b7'},{s:2,f:11,iv:'4'},{s:1,f:9,iv:'5'},{s:0,f:9,iv:'1'}] },
+  { name:'D7sus4', positions:[{s:5,f:10,iv:'1'},{s:4,f:12,iv:'5'},{s:3,f:10,iv:'b7'},{s:2,f:12,iv:'4'},{s:1,f:10,iv:'5'},{s:0,f:10,iv:'1'}] },
+  { name:'E7sus4', positions:[{s:5,f:0,iv:'1'},{s:4,f:2,iv:'5'},{s:3,f:0,iv:'b7'},{s:2,f:2,iv:'4'},{s:1,f:0,iv:'5'},{s:0,f:0,iv:'1'}] },
+  { name:'F7sus4', positions:[{s:5,f:1,iv:'1'},{s:4,f:3,iv:'5'},{s:3,f:1,iv:'b7'},{s:2,f:3,iv:'4'},{s:1,f:1,iv:'5'},{s:0,f:1,iv:'1'}] },
+  { name:'F#7sus4', positions:[{s:5,f:2,iv:'1'},{s:4,f:4,iv:'5'},{s:3,f:2,iv:'b7'},{s:2,f:4,iv:'4'},{s:1,f:2,iv:'5'},{s:0,f:2,iv:'1'}] },
+  { name:'G7sus4', positions:[{s:5,f:3,iv:'1'},{s:4,f:5,iv:'5'},{s:3,f:3,iv:'b7'},{s:2,f:5,iv:'4'},{s:1,f:3,iv:'5'},{s:0,f:3,iv:'1'}] },
+  { name:'Ab7sus4', positions:[{s:5,f:4,iv:'1'},{s:4,f:6,iv:'5'},{s:3,f:4,iv:'b7'},{s:2,f:6,iv:'4'},{s:1,f:4,iv:'5'},{s:0,f:4,iv:'1'}] },
+  { name:'A7sus4', positions:[{s:5,f:5,iv:'1'},{s:4,f:7,iv:'5'},{s:3,f:5,iv:'b7'},{s:2,f:7,iv:'4'},{s:1,f:5,iv:'5'},{s:0,f:5,iv:'1'}] },
+  { name:'Bb7sus4', positions:[{s:5,f:6,iv:'1'},{s:4,f:8,iv:'5'},{s:3,f:6,iv:'b7'},{s:2,f:8,iv:'4'},{s:1,f:6,iv:'5'},{s:0,f:6,iv:'1'}] },
+  { name:'B7sus4', positions:[{s:5,f:7,iv:'1'},{s:4,f:9,iv:'5'},{s:3,f:7,iv:'b7'},{s:2,f:9,iv:'4'},{s:1,f:7,iv:'5'},{s:0,f:7,iv:'1'}] },
+  // 7sus4, root on the A string
+  { name:'C7sus4', positions:[{s:4,f:3,iv:'1'},{s:3,f:5,iv:'5'},{s:2,f:3,iv:'b7'},{s:1,f:6,iv:'4'}] },
+  { name:'C#7sus4', positions:[{s:4,f:4,iv:'1'},{s:3,f:6,iv:'5'},{s:2,f:4,iv:'b7'},{s:1,f:7,iv:'4'}] },
+  { name:'D7sus4', positions:[{s:4,f:5,iv:'1'},{s:3,f:7,iv:'5'},{s:2,f:5,iv:'b7'},{s:1,f:8,iv:'4'}] },
+  { name:'Eb7sus4', positions:[{s:4,f:6,iv:'1'},{s:3,f:8,iv:'5'},{s:2,f:6,iv:'b7'},{s:1,f:9,iv:'4'}] },
+  { name:'E7sus4', positions:[{s:4,f:7,iv:'1'},{s:3,f:9,iv:'5'},{s:2,f:7,iv:'b7'},{s:1,f:10,iv:'4'}] },
+  { name:'F7sus4', positions:[{s:4,f:8,iv:'1'},{s:3,f:10,iv:'5'},{s:2,f:8,iv:'b7'},{s:1,f:11,iv:'4'}] },
+  { name:'F#7sus4', positions:[{s:4,f:9,iv:'1'},{s:3,f:11,iv:'5'},{s:2,f:9,iv:'b7'},{s:1,f:12,iv:'4'}] },
+  { name:'A7sus4', positions:[{s:4,f:0,iv:'1'},{s:3,f:2,iv:'5'},{s:2,f:0,iv:'b7'},{s:1,f:3,iv:'4'}] },
+  { name:'Bb7sus4', positions:[{s:4,f:1,iv:'1'},{s:3,f:3,iv:'5'},{s:2,f:1,iv:'b7'},{s:1,f:4,iv:'4'}] },
+  { name:'B7sus4', positions:[{s:4,f:2,iv:'1'},{s:3,f:4,iv:'5'},{s:2,f:2,iv:'b7'},{s:1,f:5,iv:'4'}] },
+  // 7b5, root on the low E string
+  { name:'C7b5', positions:[{s:5,f:8,iv:'1'},{s:3,f:8,iv:'b7'},{s:2,f:9,iv:'3'},{s:1,f:7,iv:'b5'}] },
+  { name:'C#7b5', positions:[{s:5,f:9,iv:'1'},{s:3,f:9,iv:'b7'},{s:2,f:10,iv:'3'},{s:1,f:8,iv:'b5'}] },
+  { name:'D7b5', positions:[{s:5,f:10,iv:'1'},{s:3,f:10,iv:'b7'},{s:2,f:11,iv:'3'},{s:1,f:9,iv:'b5'}] },
+  { name:'Eb7b5', positions:[{s:5,f:11,iv:'1'},{s:3,f:11,iv:'b7'},{s:2,f:12,iv:'3'},{s:1,f:10,iv:'b5'}] },
+  { name:'F7b5', positions:[{s:5,f:1,iv:'1'},{s:3,f:1,iv:'b7'},{s:2,f:2,iv:'3'},{s:1,f:0,iv:'b5'}] },
+  { name:'F#7b5', positions:[{s:5,f:2,iv:'1'},{s:3,f:2,iv:'b7'},{s:2,f:3,iv:'3'},{s:1,f:1,iv:'b5'}] },
+  { name:'G7b5', positions:[{s:5,f:3,iv:'1'},{s:3,f:3,iv:'b7'},{s:2,f:4,iv:'3'},{s:1,f:2,iv:'b5'}] },
+  { name:'Ab7b5', positions:[{s:5,f:4,iv:'1'},{s:3,f:4,iv:'b7'},{s:2,f:5,iv:'3'},{s:1,f:3,iv:'b5'}] },
+  { name:'A7b5', positions:[{s:5,f:5,iv:'1'},{s:3,f:5,iv:'b7'},{s:2,f:6,iv:'3'},{s:1,f:4,iv:'b5'}] },
+  { name:'Bb7b5', positions:[{s:5,f:6,iv:'1'},{s:3,f:6,iv:'b7'},{s:2,f:7,iv:'3'},{s:1,f:5,iv:'b5'}] },
+  { name:'B7b5', positions:[{s:5,f:7,iv:'1'},{s:3,f:7,iv:'b7'},{s:2,f:8,iv:'3'},{s:1,f:6,iv:'b5'}] },
+  // 7#5, root on the low E string
+  { name:'C7#5', positions:[{s:5,f:8,iv:'1'},{s:3,f:8,iv:'b7'},{s:2,f:9,iv:'3'},{s:1,f:9,iv:'#5'}] },
+  { name:'C#7#5', positions:[{s:5,f:9,iv:'1'},{s:3,f:9,iv:'b7'},{s:2,f:10,iv:'3'},{s:1,f:10,iv:'#5'}] },
+  { name:'D7#5', positions:[{s:5,f:10,iv:'1'},{s:3,f:10,iv:'b7'},{s:2,f:11,iv:'3'},{s:1,f:11,iv:'#5'}] },
+  { name:'Eb7#5', positions:[{s:5,f:11,iv:'1'},{s:3,f:11,iv:'b7'},{s:2,f:12,iv:'3'},{s:1,f:12,iv:'#5'}] },
+  { name:'E7#5', positions:[{s:5,f:0,iv:'1'},{s:3,f:0,iv:'b7'},{s:2,f:1,iv:'3'},{s:1,f:1,iv:'#5'}] },
+  { name:'F7#5', positions:[{s:5,f:1,iv:'1'},{s:3,f:1,iv:'b7'},{s:2,f:2,iv:'3'},{s:1,f:2,iv:'#5'}] },
+  { name:'F#7#5', positions:[{s:5,f:2,iv:'1'},{s:3,f:2,iv:'b7'},{s:2,f:3,iv:'3'},{s:1,f:3,iv:'#5'}] },
+  { name:'G7#5', positions:[{s:5,f:3,iv:'1'},{s:3,f:3,iv:'b7'},{s:2,f:4,iv:'3'},{s:1,f:4,iv:'#5'}] },
+  { name:'Ab7#5', positions:[{s:5,f:4,iv:'1'},{s:3,f:4,iv:'b7'},{s:2,f:5,iv:'3'},{s:1,f:5,iv:'#5'}] },
+  { name:'A7#5', positions:[{s:5,f:5,iv:'1'},{s:3,f:5,iv:'b7'},{s:2,f:6,iv:'3'},{s:1,f:6,iv:'#5'}] },
+  { name:'Bb7#5', positions:[{s:5,f:6,iv:'1'},{s:3,f:6,iv:'b7'},{s:2,f:7,iv:'3'},{s:1,f:7,iv:'#5'}] },
+  { name:'B7#5', positions:[{s:5,f:7,iv:'1'},{s:3,f:7,iv:'b7'},{s:2,f:8,iv:'3'},{s:1,f:8,iv:'#5'}] },
+  // 7b9, root on the A string
+  { name:'C7b9', positions:[{s:4,f:3,iv:'1'},{s:3,f:2,iv:'3'},{s:2,f:3,iv:'b7'},{s:1,f:2,iv:'b9'}] },
+  { name:'C#7b9', positions:[{s:4,f:4,iv:'1'},{s:3,f:3,iv:'3'},{s:2,f:4,iv:'b7'},{s:1,f:3,iv:'b9'}] },
+  { name:'D7b9', positions:[{s:4,f:5,iv:'1'},{s:3,f:4,iv:'3'},{s:2,f:5,iv:'b7'},{s:1,f:4,iv:'b9'}] },
+  { name:'Eb7b9', positions:[{s:4,f:6,iv:'1'},{s:3,f:5,iv:'3'},{s:2,f:6,iv:'b7'},{s:1,f:5,iv:'b9'}] },
+  { name:'E7b9', positions:[{s:4,f:7,iv:'1'},{s:3,f:6,iv:'3'},{s:2,f:7,iv:'b7'},{s:1,f:6,iv:'b9'}] },
+  { name:'F7b9', positions:[{s:4,f:8,iv:'1'},{s:3,f:7,iv:'3'},{s:2,f:8,iv:'b7'},{s:1,f:7,iv:'b9'}] },
+  { name:'F#7b9', positions:[{s:4,f:9,iv:'1'},{s:3,f:8,iv:'3'},{s:2,f:9,iv:'b7'},{s:1,f:8,iv:'b9'}] },
+  { name:'G7b9', positions:[{s:4,f:10,iv:'1'},{s:3,f:9,iv:'3'},{s:2,f:10,iv:'b7'},{s:1,f:9,iv:'b9'}] },
+  { name:'Ab7b9', positions:[{s:4,f:11,iv:'1'},{s:3,f:10,iv:'3'},{s:2,f:11,iv:'b7'},{s:1,f:10,iv:'b9'}] },
+  { name:'A7b9', positions:[{s:4,f:12,iv:'1'},{s:3,f:11,iv:'3'},{s:2,f:12,iv:'b7'},{s:1,f:11,iv:'b9'}] },
+  { name:'Bb7b9', positions:[{s:4,f:1,iv:'1'},{s:3,f:0,iv:'3'},{s:2,f:1,iv:'b7'},{s:1,f:0,iv:'b9'}] },
+  { name:'B7b9', positions:[{s:4,f:2,iv:'1'},{s:3,f:1,iv:'3'},{s:2,f:2,iv:'b7'},{s:1,f:1,iv:'b9'}] },
 ];
 
-/* ── PRO-TIER SHAPES ────────────────────────────────────────────────
-   Generated from movable templates so every root gets the same trusted
-   voicing. `off` entries are [string, fretOffset, interval] relative to the
-   root fret on `rootS`; a chord is emitted for every root whose frets all
-   land within 0..12. All voicings are standard grips (drop-2 / CAGED-derived)
-   with a fretted span ≤ 3. Extended chords omit the 5th where that is the
-   common guitar voicing (9ths/13ths). */
-const PRO_ROOT_NAME = ['C','C#','D','Eb','E','F','F#','G','Ab','A','Bb','B'];
-const PRO_TEMPLATES = [
-  // Sixths
-  { suf:'6',       rootS:4, off:[[4,0,'1'],[3,2,'5'],[2,2,'1'],[1,2,'3'],[0,2,'6']] },        // x-R-R+2-R+2-R+2-R+2 (C6 = x35555)
-  { suf:'6',       rootS:3, off:[[3,0,'1'],[2,2,'5'],[1,0,'6'],[0,2,'3']] },                  // xx-R-R+2-R-R+2 (D6 = xx0202)
-  { suf:'m6',      rootS:4, off:[[4,0,'1'],[3,2,'5'],[2,2,'1'],[1,1,'b3'],[0,2,'6']] },       // Am6 = x02212
-  { suf:'m6',      rootS:3, off:[[3,0,'1'],[2,2,'5'],[1,0,'6'],[0,1,'b3']] },                 // Dm6 = xx0201
-  { suf:'6/9',     rootS:4, off:[[4,0,'1'],[3,-1,'3'],[2,-1,'6'],[1,0,'9'],[0,0,'5']] },      // C6/9 = x32233
-  // Extended
-  { suf:'maj9',    rootS:4, off:[[4,0,'1'],[3,-1,'3'],[2,1,'7'],[1,0,'9']] },                 // Cmaj9 = x324x x
-  { suf:'m9',      rootS:4, off:[[4,0,'1'],[3,-2,'b3'],[2,0,'b7'],[1,0,'9'],[0,0,'5']] },     // Cm9 = x31333
-  { suf:'13',      rootS:5, off:[[5,0,'1'],[3,0,'b7'],[2,1,'3'],[1,2,'13']] },                // F13 = 1x122x
-  // Minor-major
-  { suf:'m(maj7)', rootS:4, off:[[4,0,'1'],[3,2,'5'],[2,1,'7'],[1,1,'b3']] },                 // Am(maj7) = x0211x
-  // Diminished / half-diminished / augmented
-  { suf:'m7b5',    rootS:4, off:[[4,0,'1'],[3,1,'b5'],[2,0,'b7'],[1,1,'b3']] },               // Bm7b5 = x2323x
-  { suf:'m7b5',    rootS:5, off:[[5,0,'1'],[3,0,'b7'],[2,0,'b3'],[1,-1,'b5']] },              // F#m7b5 = 2x221x
-  { suf:'dim7',    rootS:4, off:[[4,0,'1'],[3,1,'b5'],[2,-1,'bb7'],[1,1,'b3']] },             // Bdim7 = x2313x
-  { suf:'dim7',    rootS:3, off:[[3,0,'1'],[2,1,'b5'],[1,0,'bb7'],[0,1,'b3']] },              // Edim7 = xx2323
-  { suf:'aug',     rootS:4, off:[[4,0,'1'],[3,-1,'3'],[2,-2,'#5'],[1,-2,'1']] },              // Caug = x3211x
-  { suf:'aug',     rootS:5, off:[[5,0,'1'],[3,2,'1'],[2,1,'3'],[1,1,'#5']] },                 // Faug = 1x322x
-  // Dominant colors
-  { suf:'7sus4',   rootS:5, off:[[5,0,'1'],[4,2,'5'],[3,0,'b7'],[2,2,'4'],[1,0,'5'],[0,0,'1']] }, // F7sus4 = 131311
-  { suf:'7sus4',   rootS:4, off:[[4,0,'1'],[3,2,'5'],[2,0,'b7'],[1,3,'4']] },                 // A7sus4 = x0203x
-  { suf:'7b5',     rootS:5, off:[[5,0,'1'],[3,0,'b7'],[2,1,'3'],[1,-1,'b5']] },               // C7b5 = 8x897x
-  { suf:'7#5',     rootS:5, off:[[5,0,'1'],[3,0,'b7'],[2,1,'3'],[1,1,'#5']] },                // C7#5 = 8x899x
-  { suf:'7b9',     rootS:4, off:[[4,0,'1'],[3,-1,'3'],[2,0,'b7'],[1,-1,'b9']] },              // C7b9 = x3232x
+// Degree token → [semitones from root, human-readable label]
+const DEGREE = {
+  '1': [0, 'root'],
+  'b2': [1, 'flat 2nd'],
+  'b9': [1, 'flat 9th'],
+  '2': [2, '2nd'],
+  '9': [2, '9th'],
+  'b3': [3, 'minor 3rd'],
+  '#9': [3, 'sharp 9th'],
+  '3': [4, 'major 3rd'],
+  '4': [5, '4th'],
+  '11': [5, '11th'],
+  'b5': [6, 'flat 5th'],
+  '#11': [6, 'sharp 11th'],
+  '5': [7, 'perfect 5th'],
+  '#5': [8, 'sharp 5th'],
+  'b13': [8, 'flat 13th'],
+  '6': [9, '6th'],
+  '13': [9, '13th'],
+  'bb7': [9, 'dim 7th'],
+  'b7': [10, 'minor 7th'],
+  '7': [11, 'major 7th'],
+};
+
+// Ordered roughly most → least common; the order breaks ties when several
+// names describe the same set of notes. `suf` is appended to the root name.
+const CHORD_FORMULAS = [
+  { suf: ' major', deg: ['1','3','5'] },
+  { suf: ' minor', deg: ['1','b3','5'] },
+  { suf: ' dim', deg: ['1','b3','b5'] },
+  { suf: ' aug', deg: ['1','3','#5'] },
+  { suf: ' sus2', deg: ['1','2','5'] },
+  { suf: ' sus4', deg: ['1','4','5'] },
+  { suf: '(b5)', deg: ['1','3','b5'] },
+  { suf: '5', deg: ['1','5'] },
+  { suf: '7', deg: ['1','3','5','b7'] },
+  { suf: 'maj7', deg: ['1','3','5','7'] },
+  { suf: 'm7', deg: ['1','b3','5','b7'] },
+  { suf: '6', deg: ['1','3','5','6'] },
+  { suf: 'm6', deg: ['1','b3','5','6'] },
+  { suf: 'm(maj7)', deg: ['1','b3','5','7'] },
+  { suf: 'm7b5', deg: ['1','b3','b5','b7'] },
+  { suf: 'dim7', deg: ['1','b3','b5','bb7'] },
+  { suf: 'dim(maj7)', deg: ['1','b3','b5','7'] },
+  { suf: '7sus4', deg: ['1','4','5','b7'] },
+  { suf: '7sus2', deg: ['1','2','5','b7'] },
+  { suf: '7b5', deg: ['1','3','b5','b7'] },
+  { suf: '7#5', deg: ['1','3','#5','b7'] },
+  { suf: 'maj7b5', deg: ['1','3','b5','7'] },
+  { suf: 'maj7#5', deg: ['1','3','#5','7'] },
+  { suf: 'add9', deg: ['1','9','3','5'] },
+  { suf: 'madd9', deg: ['1','9','b3','5'] },
+  { suf: 'add11', deg: ['1','3','11','5'] },
+  { suf: 'madd11', deg: ['1','b3','11','5'] },
+  { suf: '9', deg: ['1','9','3','5','b7'] },
+  { suf: 'maj9', deg: ['1','9','3','5','7'] },
+  { suf: 'm9', deg: ['1','9','b3','5','b7'] },
+  { suf: 'm(maj9)', deg: ['1','9','b3','5','7'] },
+  { suf: '6/9', deg: ['1','9','3','5','6'] },
+  { suf: 'm6/9', deg: ['1','9','b3','5','6'] },
+  { suf: '7b9', deg: ['1','b9','3','5','b7'] },
+  { suf: '7#9', deg: ['1','#9','3','5','b7'] },
+  { suf: '9sus4', deg: ['1','9','4','5','b7'] },
+  { suf: '9b5', deg: ['1','9','3','b5','b7'] },
+  { suf: '9#5', deg: ['1','9','3','#5','b7'] },
+  { suf: '7#11', deg: ['1','3','#11','5','b7'] },
+  { suf: 'maj7#11', deg: ['1','3','#11','5','7'] },
+  { suf: '7b13', deg: ['1','3','5','b13','b7'] },
+  { suf: '11', deg: ['1','9','3','11','5','b7'] },
+  { suf: 'm11', deg: ['1','9','b3','11','5','b7'] },
+  { suf: 'maj11', deg: ['1','9','3','11','5','7'] },
+  { suf: '13', deg: ['1','9','3','5','13','b7'] },
+  { suf: 'm13', deg: ['1','9','b3','5','13','b7'] },
+  { suf: 'maj13', deg: ['1','9','3','5','13','7'] },
 ];
-{
-  const OPEN_PC = [4, 11, 7, 2, 9, 4]; // E B G D A E, top → bottom
-  for (const t of PRO_TEMPLATES) {
-    for (let pc = 0; pc < 12; pc++) {
-      const base = (pc - OPEN_PC[t.rootS] + 12) % 12;
-      for (const R of [base, base + 12]) {
-        if (t.off.some(([, d]) => R + d < 0 || R + d > 12)) continue;
-        CHORD_SHAPES.push({
-          name: PRO_ROOT_NAME[pc] + t.suf,
-          positions: t.off.map(([s, d, iv]) => ({ s, f: R + d, iv })),
-        });
-        break;
-      }
-    }
-  }
+// END GENERATED music-theory data.
+
+const showNote = (n, style='both') => {
+  if (style==='flat')  return DISPLAY_FLAT[n]  || n;
+  if (style==='sharp') return n;
+  return DISPLAY_BOTH[n] || n;
+};
+
+const stringsFor = inst => TUNINGS[inst] || TUNINGS.guitar;
+
+// Open-string MIDI numbers, high → low (matches TUNINGS layout). Used for
+// audio playback and for finding the lowest sounding note of a voicing.
+const OPEN_MIDI = { guitar: [64,59,55,50,45,40], bass: [43,38,33,28] };
+
+function noteAt(si, fret, inst='guitar') {
+  const strings = stringsFor(inst);
+  const idx = CHROMATIC.indexOf(strings[si]);
+  return CHROMATIC[(idx + fret) % 12];
+}
+
+function matchNote(input, expected) {
+  const FLAT_MAP = {'DB':'C#','EB':'D#','GB':'F#','AB':'G#','BB':'A#'};
+  const n = input.toUpperCase().replace(/♭/g,'B').replace(/♯/g,'#').replace('/','').replace(/\s+/g,'');
+  return (FLAT_MAP[n] || n) === expected;
+}
+
+function scaleNotes(root, intervals) {
+  const ri = CHROMATIC.indexOf(root);
+  return intervals.map(i => CHROMATIC[(ri+i)%12]);
 }
 
 const CHORD_GOAL = 20;
@@ -601,11 +865,21 @@ const CHORD_ENHARMONIC = {
 };
 
 function expandChordInput(s) {
-  return s.replace(/maj$/, ' major').replace(/min$/, ' minor')
+  return s.replace(/ ?major$/, ' major').replace(/ ?minor$/, ' minor')
+    .replace(/maj$/, ' major').replace(/min$/, ' minor')
     .replace(/(?<!aj)(?<!di)m(?!aj|7|9|in)/, ' minor')
     .trim().replace(/\s+/g,' ');
 }
 
+// normalizeChordAnswer: a typed chord name, ready for chordNameCorrect. A
+// capital M right after the root means major ("CM", "F#M"), as in the
+// terminal game; everything else is case-insensitive.
+function normalizeChordAnswer(input) {
+  const s = input.trim().replace(/\s+/g,' ');
+  const major = s.match(/^([A-Ga-g][#b]?) ?M$/);
+  return (major ? major[1] + ' major' : s).toLowerCase();
+}
+
 function normalizeChordName(s) {
   return s.trim().toLowerCase().replace(/[()]/g,'').replace(/\s+/g,' ');
 }
@@ -733,80 +1007,6 @@ function triadKey(positions) {
    dim7's four names, Csus2 = Gsus4, …).
 ═══════════════════════════════════════ */
 
-// Degree token → [semitones from root, human-readable label]
-const DEGREE = {
-  '1':  [0,  'root'],
-  'b2': [1,  'flat 2nd'],   'b9': [1,  'flat 9th'],
-  '2':  [2,  '2nd'],        '9':  [2,  '9th'],
-  'b3': [3,  'minor 3rd'],  '#9': [3,  'sharp 9th'],
-  '3':  [4,  'major 3rd'],
-  '4':  [5,  '4th'],        '11': [5,  '11th'],
-  'b5': [6,  'flat 5th'],   '#11':[6,  'sharp 11th'],
-  '5':  [7,  'perfect 5th'],
-  '#5': [8,  'sharp 5th'],  'b13':[8,  'flat 13th'],
-  '6':  [9,  '6th'],        '13': [9,  '13th'],  'bb7':[9, 'dim 7th'],
-  'b7': [10, 'minor 7th'],
-  '7':  [11, 'major 7th'],
-};
-
-// Ordered roughly most → least common; the order breaks ties when several
-// names describe the same set of notes. `suf` is appended to the root name.
-const CHORD_FORMULAS = [
-  // Triads
-  { suf: ' major',    deg: ['1','3','5'] },
-  { suf: ' minor',    deg: ['1','b3','5'] },
-  { suf: ' dim',      deg: ['1','b3','b5'] },
-  { suf: ' aug',      deg: ['1','3','#5'] },
-  { suf: ' sus2',     deg: ['1','2','5'] },
-  { suf: ' sus4',     deg: ['1','4','5'] },
-  { suf: '(b5)',      deg: ['1','3','b5'] },
-  // Power chord
-  { suf: '5',         deg: ['1','5'] },
-  // Sixths & sevenths
-  { suf: '7',         deg: ['1','3','5','b7'] },
-  { suf: 'maj7',      deg: ['1','3','5','7'] },
-  { suf: 'm7',        deg: ['1','b3','5','b7'] },
-  { suf: '6',         deg: ['1','3','5','6'] },
-  { suf: 'm6',        deg: ['1','b3','5','6'] },
-  { suf: 'm(maj7)',   deg: ['1','b3','5','7'] },
-  { suf: 'm7b5',      deg: ['1','b3','b5','b7'] },
-  { suf: 'dim7',      deg: ['1','b3','b5','bb7'] },
-  { suf: 'dim(maj7)', deg: ['1','b3','b5','7'] },
-  { suf: '7sus4',     deg: ['1','4','5','b7'] },
-  { suf: '7sus2',     deg: ['1','2','5','b7'] },
-  { suf: '7b5',       deg: ['1','3','b5','b7'] },
-  { suf: '7#5',       deg: ['1','3','#5','b7'] },
-  { suf: 'maj7b5',    deg: ['1','3','b5','7'] },
-  { suf: 'maj7#5',    deg: ['1','3','#5','7'] },
-  // Added-tone chords
-  { suf: 'add9',      deg: ['1','9','3','5'] },
-  { suf: 'madd9',     deg: ['1','9','b3','5'] },
-  { suf: 'add11',     deg: ['1','3','11','5'] },
-  { suf: 'madd11',    deg: ['1','b3','11','5'] },
-  // Ninths
-  { suf: '9',         deg: ['1','9','3','5','b7'] },
-  { suf: 'maj9',      deg: ['1','9','3','5','7'] },
-  { suf: 'm9',        deg: ['1','9','b3','5','b7'] },
-  { suf: 'm(maj9)',   deg: ['1','9','b3','5','7'] },
-  { suf: '6/9',       deg: ['1','9','3','5','6'] },
-  { suf: 'm6/9',      deg: ['1','9','b3','5','6'] },
-  { suf: '7b9',       deg: ['1','b9','3','5','b7'] },
-  { suf: '7#9',       deg: ['1','#9','3','5','b7'] },
-  { suf: '9sus4',     deg: ['1','9','4','5','b7'] },
-  { suf: '9b5',       deg: ['1','9','3','b5','b7'] },
-  { suf: '9#5',       deg: ['1','9','3','#5','b7'] },
-  { suf: '7#11',      deg: ['1','3','#11','5','b7'] },
-  { suf: 'maj7#11',   deg: ['1','3','#11','5','7'] },
-  { suf: '7b13',      deg: ['1','3','5','b13','b7'] },
-  // Elevenths & thirteenths (13ths omit the 11th, as commonly voiced)
-  { suf: '11',        deg: ['1','9','3','11','5','b7'] },
-  { suf: 'm11',       deg: ['1','9','b3','11','5','b7'] },
-  { suf: 'maj11',     deg: ['1','9','3','11','5','7'] },
-  { suf: '13',        deg: ['1','9','3','5','13','b7'] },
-  { suf: 'm13',       deg: ['1','9','b3','5','13','b7'] },
-  { suf: 'maj13',     deg: ['1','9','3','5','13','7'] },
-];
-
 // identifyChords: name the chord(s) formed by a set of pitch classes (0-11).
 // Returns every matching interpretation, best first. Each match:
 //   { root, suf, no5, bass, tones: [{ note, label }] }
@@ -848,6 +1048,80 @@ function identifyChords(pcs, bassPc = null) {
   return out;
 }
 
+/* ═══════════════════════════════════════
+   STATS SYNC
+═══════════════════════════════════════ */
+// The server stores the page's modes under the terminal's names for the
+// same game (stats.CanonicalMode); mergeStats maps them back.
+const WEB_MODES = { single: 'random', fretset: 'find', chords: 'chord' };
+
+// mergeStats: union of two session-record lists (localStorage + server),
+// deduplicated by mode + timestamp and sorted oldest first. Records from
+// `local` win, so fields the server does not store survive a sync.
+function mergeStats(local, remote) {
+  const key = s => s.mode + '@' + s.timestamp;
+  const byKey = new Map();
+  for (const s of remote || []) {
+    const r = WEB_MODES[s.mode] ? { ...s, mode: WEB_MODES[s.mode] } : s;
+    byKey.set(key(r), r);
+  }
+  for (const s of local || []) byKey.set(key(s), s);
+  return [...byKey.values()].sort((a, b) => a.timestamp - b.timestamp);
+}
+
+/* ═══════════════════════════════════════
+   SAMPLE BANK
+═══════════════════════════════════════ */
+// The server's sample manifest lists velocity layers softest first, each
+// with the MIDI notes it has samples for.
+
+// sampleLayerFor: the layer for a playback volume (0..~0.9). The page's
+// default single-note volume (0.6) lands on the middle layer; quiet chord
+// tones use softer layers.
+function sampleLayerFor(layers, vol) {
+  if (!layers || !layers.length) return null;
+  const t = Math.min(1, Math.max(0, (vol - 0.3) / 0.6));
+  return layers[Math.round(t * (layers.length - 1))];
+}
+
+// nearestSampleMidi: the sampled note closest to target; playback
+// pitch-shifts it the rest of the way. Ties go to the lower note.
+function nearestSampleMidi(notes, target) {
+  let best = notes[0], bestDiff = Math.abs(target - best);
+  for (const m of notes) {
+    const d = Math.abs(target - m);
+    if (d < bestDiff) { bestDiff = d; best = m; }
+  }
+  return best;
+}
+
+// usableSampleBanks: the banks of the server's sample manifest that have
+// samples, with relative paths so the page works below a path prefix. A
+// missing or malformed manifest, or one without a guitar bank, gives null:
+// the page then keeps its built-in guitar layer.
+function usableSampleBanks(manifest) {
+  if (!manifest || !manifest.banks || typeof manifest.banks !== 'object') return null;
+  const usable = {};
+  for (const [inst, bank] of Object.entries(manifest.banks)) {
+    if (!bank || typeof bank.path !== 'string' || !Array.isArray(bank.layers)) continue;
+    const layers = bank.layers.filter(l => l && typeof l.name === 'string' && Array.isArray(l.notes) && l.notes.length);
+    if (layers.length) usable[inst] = { ...bank, path: bank.path.replace(/^\//, ''), layers };
+  }
+  return usable.guitar ? usable : null;
+}
+
+// sampleFor: the layer and sampled note to play midi with at volume vol.
+// Until the sample of the velocity layer for vol is loaded (loaded(layer,
+// midi) tells), the layer for defaultVol plays instead and prefetch names the
+// sample to load for next time.
+function sampleFor(bank, midi, vol, defaultVol, loaded) {
+  const base = sampleLayerFor(bank.layers, defaultVol);
+  const layer = sampleLayerFor(bank.layers, vol);
+  const note = nearestSampleMidi(layer.notes, midi);
+  if (layer === base || loaded(layer, note)) return { layer, midi: note, prefetch: null };
+  return { layer: base, midi: nearestSampleMidi(base.notes, midi), prefetch: { layer, midi: note } };
+}
+
 const api = {
   CHROMATIC, DISPLAY_BOTH, DISPLAY_FLAT, showNote,
   TUNINGS, stringsFor, noteAt, matchNote, OPEN_MIDI,
@@ -859,10 +1133,11 @@ const api = {
   chordsForDifficulty, qualitiesForDifficulty, buildShapeChart,
   FRET_SETS_MODE2, notesInSet, positionsOfNote,
   IV_ORDER, IV_LABEL, buildChordIntervals,
-  CHORD_ENHARMONIC, expandChordInput, normalizeChordName,
+  CHORD_ENHARMONIC, expandChordInput, normalizeChordAnswer, normalizeChordName,
   matchesChordName, chordNameCorrect, displayChordName,
   TRIAD_MAX_SPAN, triadStringSets, findTriads, triadKey,
   CHORD_FORMULAS, identifyChords,
+  mergeStats, sampleLayerFor, nearestSampleMidi, usableSampleBanks, sampleFor,
 };
 
 if (typeof module !== 'undefined' && module.exports) {
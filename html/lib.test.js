@@ -7,7 +7,7 @@ const assert = require('node:assert/strict');
 const {
   noteAt, matchNote, scaleNotes, stringsFor, showNote,
   CHROMATIC, CHORD_SHAPES,
-  normalizeChordName, expandChordInput, matchesChordName, chordNameCorrect,
+  normalizeChordName, normalizeChordAnswer, expandChordInput, matchesChordName, chordNameCorrect,
   getChordDifficulty, isBasicBarreChord, chordsForDifficulty, qualitiesForDifficulty,
   buildChordIntervals,
   triadStringSets, findTriads, triadKey, TRIAD_MAX_SPAN,
@@ -62,6 +62,13 @@ test('matchNote: accepts sharps, flats, and unicode flat', () => {
   assert.ok(!matchNote('D', 'C#'));
 });
 
+test('matchNote: accepts unicode sharps and spaces before the accidental', () => {
+  assert.ok(matchNote('C♯', 'C#'));
+  assert.ok(matchNote('c #', 'C#'));
+  assert.ok(matchNote('D ♭', 'C#'));
+  assert.ok(!matchNote('C#/Db', 'C#'));
+});
+
 test('scaleNotes: major scale from C is the natural notes', () => {
   assert.deepEqual(
     scaleNotes('C', [0,2,4,5,7,9,11]),
@@ -94,6 +101,20 @@ test('expandChordInput: maj/min suffixes expand to full words', () => {
   assert.equal(expandChordInput('Amin'), 'A minor');
 });
 
+test('expandChordInput: major/minor written without a space', () => {
+  assert.equal(expandChordInput('Cmajor'),  'C major');
+  assert.equal(expandChordInput('F#minor'), 'F# minor');
+});
+
+test('normalizeChordAnswer: capital M after the root means major', () => {
+  assert.equal(normalizeChordAnswer('CM'),     'c major');
+  assert.equal(normalizeChordAnswer(' F# M '), 'f# major');
+  assert.equal(normalizeChordAnswer('Cm'),     'cm');
+  assert.equal(normalizeChordAnswer('Cmaj7'),  'cmaj7');
+  assert.ok(chordNameCorrect(normalizeChordAnswer('BbM'), 'bb major'));
+  assert.ok(!chordNameCorrect(normalizeChordAnswer('BbM'), 'bb minor'));
+});
+
 test('expandChordInput: bare lowercase m expands to minor', () => {
   // Cm → C minor
   assert.equal(expandChordInput('Cm'), 'C minor');
@@ -641,3 +662,108 @@ test('scales: dorian, mixolydian, harmonic minor', () => {
   assert.deepEqual(scaleNotes('G', MIXOLYDIAN_SCALE), ['G','A','B','C','D','E','F']);
   assert.deepEqual(scaleNotes('A', HARMONIC_MINOR_SCALE), ['A','B','C','D','E','F','G#']);
 });
+
+const { mergeStats } = require('./lib.js');
+
+test('mergeStats: union by mode + timestamp, oldest first, local wins', () => {
+  const local = [
+    { mode: 'random', timestamp: 3000, timeSeconds: 50, strings: [true, false] },
+    { mode: 'chord',  timestamp: 1000, timeSeconds: 90 },
+  ];
+  const remote = [
+    { mode: 'random', timestamp: 3000, timeSeconds: 50 },
+    { mode: 'find',   timestamp: 2000, timeSeconds: 70 },
+    { mode: 'chord',  timestamp: 3000, timeSeconds: 80 }, // same time, other mode
+  ];
+  const merged = mergeStats(local, remote);
+  assert.deepEqual(merged.map(s => s.mode + '@' + s.timestamp),
+    ['chord@1000', 'find@2000', 'random@3000', 'chord@3000']);
+  assert.deepEqual(merged.find(s => s.mode === 'random').strings, [true, false]);
+  assert.deepEqual(mergeStats([], null), []);
+});
+
+test('mergeStats: terminal mode names from the server join the page modes', () => {
+  const local = [{ mode: 'random', timestamp: 1000, timeSeconds: 50 }];
+  const remote = [
+    { mode: 'single',  timestamp: 1000, timeSeconds: 50 }, // the page's own upload
+    { mode: 'fretset', timestamp: 2000, timeSeconds: 70 },
+    { mode: 'chords',  timestamp: 3000, timeSeconds: 80 },
+    { mode: 'daily',   timestamp: 4000, timeSeconds: 90 },
+  ];
+  assert.deepEqual(mergeStats(local, remote).map(s => s.mode + '@' + s.timestamp),
+    ['random@1000', 'find@2000', 'chord@3000', 'daily@4000']);
+});
+
+/* ────────────────────────────────────────────────────────────────
+   Sample bank — velocity layer choice and nearest sampled note.
+   ──────────────────────────────────────────────────────────────── */
+
+const { sampleLayerFor, nearestSampleMidi, usableSampleBanks, sampleFor } = require('./lib.js');
+
+test('sampleLayerFor: default volume picks the middle layer, chords go softer', () => {
+  const layers = [{ name: 'gt1' }, { name: 'gt2' }, { name: 'gt3' }];
+  assert.equal(sampleLayerFor(layers, 0.6).name, 'gt2');
+  assert.equal(sampleLayerFor(layers, 0.37).name, 'gt1');   // 6-note chord
+  assert.equal(sampleLayerFor(layers, 0.9).name, 'gt3');
+  assert.equal(sampleLayerFor([{ name: 'only' }], 0.1).name, 'only');
+  assert.equal(sampleLayerFor([], 0.6), null);
+});
+
+test('nearestSampleMidi: closest sampled note, lower one on ties', () => {
+  const notes = [40, 42, 45];
+  assert.equal(nearestSampleMidi(notes, 42), 42);
+  assert.equal(nearestSampleMidi(notes, 41), 40);
+  assert.equal(nearestSampleMidi(notes, 44), 45);
+  assert.equal(nearestSampleMidi(notes, 30), 40);
+  assert.equal(nearestSampleMidi(notes, 90), 45);
+});
+
+test('usableSampleBanks: bad or missing manifests keep the built-in bank', () => {
+  assert.equal(usableSampleBanks(null), null);            // fetch failed
+  assert.equal(usableSampleBanks({}), null);
+  assert.equal(usableSampleBanks({ banks: 'strat' }), null);
+  assert.equal(usableSampleBanks({ banks: {
+    bass: { path: '/assets/sounds/bass/', layers: [{ name: 'bs1', notes: [28] }] },
+  } }), null);                                             // no guitar
+  assert.equal(usableSampleBanks({ banks: {
+    guitar: { path: '/assets/sounds/strat/', layers: [{ name: 'gt1', notes: [] }] },
+  } }), null);                                             // no samples
+
+  const banks = usableSampleBanks({ banks: {
+    guitar: { path: '/assets/sounds/strat/', low: 39, high: 85, layers: [
+      { name: 'gt1', notes: [40] }, { name: 'gt2', notes: [] }, { name: 'gt3' }, null,
+    ] },
+    bass: { path: '/assets/sounds/bass/' },
+    ukulele: null,
+  } });
+  assert.deepEqual(Object.keys(banks), ['guitar']);
+  assert.equal(banks.guitar.path, 'assets/sounds/strat/');
+  assert.deepEqual(banks.guitar.layers, [{ name: 'gt1', notes: [40] }]);
+  assert.equal(banks.guitar.low, 39);
+});
+
+test('sampleFor: unloaded velocity layers fall back to the default layer', () => {
+  const bank = { layers: [
+    { name: 'gt1', notes: [40, 52] },
+    { name: 'gt2', notes: [40, 45, 52] },
+    { name: 'gt3', notes: [52] },
+  ] };
+  const none = () => false;
+
+  // The default volume plays the default layer, loaded or not.
+  let s = sampleFor(bank, 45, 0.6, 0.6, none);
+  assert.deepEqual([s.layer.name, s.midi, s.prefetch], ['gt2', 45, null]);
+
+  // A loud note plays the default layer now and fetches gt3 for next time.
+  s = sampleFor(bank, 45, 0.9, 0.6, none);
+  assert.deepEqual([s.layer.name, s.midi], ['gt2', 45]);
+  assert.deepEqual([s.prefetch.layer.name, s.prefetch.midi], ['gt3', 52]);
+
+  // Once loaded, the velocity layer plays, pitch-shifted from its nearest note.
+  s = sampleFor(bank, 45, 0.9, 0.6, (l, m) => l.name === 'gt3' && m === 52);
+  assert.deepEqual([s.layer.name, s.midi, s.prefetch], ['gt3', 52, null]);
+
+  // A bank with one layer never falls back.
+  s = sampleFor({ layers: [bank.layers[0]] }, 41, 0.37, 0.6, none);
+  assert.deepEqual([s.layer.name, s.midi, s.prefetch], ['gt1', 40, null]);
+});
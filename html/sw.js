@@ -0,0 +1,115 @@
+// Fremorizer — service worker for offline play.
+//
+// The server fills in the version constants and the content-hashed asset
+// URLs below (see serviceWorker in pwa.go), so every deploy that changes the page
+// or its assets installs a new worker with fresh caches. Shell and samples
+// are cached separately: a page update keeps the samples already downloaded.
+//
+//   shell    the page, its scripts, stylesheets, fonts and icons, cached at
+//            install time
+//   samples  WAV files, cached the first time the page plays or preloads them
+//
+// The page itself is fetched network first, so an online visit always gets
+// the latest deploy; everything else is served from the cache first. The API,
+// the terminal and the ops endpoints are never cached.
+
+'use strict';
+
+const SHELL_VERSION = 'dev';
+const SAMPLES_VERSION = 'dev';
+const SHELL_CACHE = 'fremorizer-shell-' + SHELL_VERSION;
+const SAMPLES_CACHE = 'fremorizer-samples-' + SAMPLES_VERSION;
+
+const SHELL = [
+  './',
+  'favicon.ico',
+  'manifest.webmanifest',
+  'icon-192.png',
+  'icon-512.png',
+  'assets/sounds/manifest.json',
+];
+// lib.js, styles.css and, in production builds, the compiled page script and
+// the vendored React and fonts.
+const ASSETS = [];
+
+// Paths below the worker's scope that always go to the network.
+const NETWORK_ONLY = /^(api\/|ws\/|terminal|healthz|readyz|metrics)/;
+
+self.addEventListener('install', event => {
+  event.waitUntil(
+    caches.open(SHELL_CACHE)
+      .then(cache => cache.addAll([...SHELL, ...ASSETS]))
+      .then(() => self.skipWaiting()),
+  );
+});
+
+// Old versions of both caches are dropped once the new worker takes over.
+self.addEventListener('activate', event => {
+  event.waitUntil(
+    caches.keys()
+      .then(keys => Promise.all(keys
+        .filter(k => k.startsWith('fremorizer-') && k !== SHELL_CACHE && k !== SAMPLES_CACHE)
+        .map(k => caches.delete(k))))
+      .then(() => self.clients.claim()),
+  );
+});
+
+self.addEventListener('fetch', event => {
+  const req = event.request;
+  if (req.method !== 'GET') return;
+  const url = new URL(req.url);
+
+  // Without a build the page loads React, Babel and the fonts from CDNs
+  // under immutable, versioned URLs; builds vendor them (see precompile-jsx).
+  if (url.origin !== self.location.origin) {
+    event.respondWith(cacheFirst(SHELL_CACHE, req));
+    return;
+  }
+
+  const scope = new URL(self.registration.scope).pathname;
+  if (!url.pathname.startsWith(scope)) return;
+  const rel = url.pathname.slice(scope.length);
+  if (NETWORK_ONLY.test(rel)) return;
+
+  if (req.mode === 'navigate') {
+    if (rel === '') event.respondWith(networkFirst(SHELL_CACHE, req, './'));
+    return;
+  }
+  if (rel.endsWith('manifest.json')) {
+    event.respondWith(networkFirst(SHELL_CACHE, req));
+    return;
+  }
+  if (rel.startsWith('assets/sounds/') && rel.endsWith('.wav')) {
+    // Range responses (206) cannot be cached; they are rare for samples.
+    if (req.headers.has('Range')) return;
+    event.respondWith(cacheFirst(SAMPLES_CACHE, req));
+    return;
+  }
+  event.respondWith(cacheFirst(SHELL_CACHE, req));
+});
+
+// cacheFirst answers from the cache and only stores what the network returns
+// on a miss, including opaque cross-origin responses.
+async function cacheFirst(cacheName, req) {
+  const cache = await caches.open(cacheName);
+  const hit = await cache.match(req);
+  if (hit) return hit;
+  const res = await fetch(req);
+  if (res.ok || res.type === 'opaque') cache.put(req, res.clone());
+  return res;
+}
+
+// networkFirst keeps the cached copy up to date and falls back to it (or to
+// the cached fallback URL) when offline.
+async function networkFirst(cacheName, req, fallback) {
+  const cache = await caches.open(cacheName);
+  try {
+    const res = await fetch(req);
+    if (res.ok) cache.put(fallback || req, res.clone());
+    return res;
+  } catch (err) {
+    const hit = await cache.match(fallback || req);
+    if (hit) return hit;
+    throw err;
+  }
+}
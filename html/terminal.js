@@ -0,0 +1,381 @@
+// Fremorizer — minimal terminal emulator for the browser terminal.
+//
+// Runs the TUI served over /ws/terminal. It implements the subset of VT100 /
+// xterm sequences that bubbletea and lipgloss emit: cursor movement, erase,
+// SGR colours (16, 256 and true colour), the alternate screen and cursor
+// visibility. Everything else is parsed and ignored.
+
+(function () {
+'use strict';
+
+const termEl = document.getElementById('term');
+const statusEl = document.getElementById('status');
+
+/* ═══════════════════════════════════════
+   COLOURS
+═══════════════════════════════════════ */
+const BASE16 = [
+  '#000000', '#cd3131', '#0dbc79', '#e5e510', '#2472c8', '#bc3fbc', '#11a8cd', '#e5e5e5',
+  '#666666', '#f14c4c', '#23d18b', '#f5f543', '#3b8eea', '#d670d6', '#29b8db', '#ffffff',
+];
+
+function color256(n) {
+  if (n < 16) return BASE16[n];
+  if (n < 232) {
+    n -= 16;
+    const v = i => (i === 0 ? 0 : 55 + i * 40);
+    return `rgb(${v(Math.floor(n / 36))},${v(Math.floor(n / 6) % 6)},${v(n % 6)})`;
+  }
+  const g = 8 + (n - 232) * 10;
+  return `rgb(${g},${g},${g})`;
+}
+
+/* ═══════════════════════════════════════
+   SCREEN
+═══════════════════════════════════════ */
+const DEFAULT_STYLE = { fg: null, bg: null, bold: false, faint: false, italic: false, underline: false, reverse: false };
+
+let cols = 80, rows = 24;
+let style = { ...DEFAULT_STYLE };
+let cx = 0, cy = 0, wrapPending = false, cursorVisible = true;
+let saved = { cx: 0, cy: 0 };
+let main = blankScreen(), alt = null;
+let screen = main;
+
+function blankCell() { return { ch: ' ', st: style }; }
+function blankLine() { return Array.from({ length: cols }, blankCell); }
+function blankScreen() { return Array.from({ length: rows }, blankLine); }
+
+function resizeScreen(c, r) {
+  const fit = s => {
+    const out = s.slice(-r);
+    while (out.length < r) out.push(null);
+    return out.map(line => {
+      const l = (line || []).slice(0, c);
+      while (l.length < c) l.push({ ch: ' ', st: DEFAULT_STYLE });
+      return l;
+    });
+  };
+  cols = c; rows = r;
+  main = fit(main);
+  if (alt) alt = fit(alt);
+  screen = alt || main;
+  cx = Math.min(cx, cols - 1);
+  cy = Math.min(cy, rows - 1);
+}
+
+function scrollUp() {
+  screen.shift();
+  screen.push(blankLine());
+}
+
+function lineFeed() {
+  if (cy === rows - 1) scrollUp();
+  else cy++;
+}
+
+// Emoji and CJK occupy two cells.
+function isWide(cp) {
+  return (cp >= 0x1100 && cp <= 0x115f) || (cp >= 0x2e80 && cp <= 0xa4cf) ||
+    (cp >= 0xac00 && cp <= 0xd7a3) || (cp >= 0xf900 && cp <= 0xfaff) ||
+    (cp >= 0xfe30 && cp <= 0xfe4f) || (cp >= 0xff00 && cp <= 0xff60) ||
+    (cp >= 0x1f300 && cp <= 0x1faff);
+}
+
+function putChar(ch) {
+  const width = isWide(ch.codePointAt(0)) ? 2 : 1;
+  if (wrapPending || cx + width > cols) {
+    cx = 0;
+    lineFeed();
+    wrapPending = false;
+  }
+  screen[cy][cx] = { ch, st: style };
+  if (width === 2 && cx + 1 < cols) screen[cy][cx + 1] = { ch: '', st: style };
+  cx += width;
+  if (cx >= cols) { cx = cols - 1; wrapPending = true; }
+}
+
+function eraseLine(mode) {
+  const [from, to] = mode === 1 ? [0, cx + 1] : mode === 2 ? [0, cols] : [cx, cols];
+  for (let x = from; x < to; x++) screen[cy][x] = blankCell();
+}
+
+function eraseDisplay(mode) {
+  if (mode === 2 || mode === 3) {
+    for (let y = 0; y < rows; y++) screen[y] = blankLine();
+    return;
+  }
+  eraseLine(mode);
+  const [from, to] = mode === 1 ? [0, cy] : [cy + 1, rows];
+  for (let y = from; y < to; y++) screen[y] = blankLine();
+}
+
+function setAltScreen(on) {
+  if (on && !alt) {
+    saved = { cx, cy };
+    alt = blankScreen();
+    screen = alt;
+  } else if (!on && alt) {
+    alt = null;
+    screen = main;
+    ({ cx, cy } = saved);
+  }
+}
+
+/* ═══════════════════════════════════════
+   ESCAPE SEQUENCES
+═══════════════════════════════════════ */
+function sgr(params) {
+  if (params.length === 0) params = [0];
+  const st = { ...style };
+  for (let i = 0; i < params.length; i++) {
+    const p = params[i] || 0;
+    if (p === 0) Object.assign(st, DEFAULT_STYLE);
+    else if (p === 1) st.bold = true;
+    else if (p === 2) st.faint = true;
+    else if (p === 3) st.italic = true;
+    else if (p === 4) st.underline = true;
+    else if (p === 7) st.reverse = true;
+    else if (p === 22) st.bold = st.faint = false;
+    else if (p === 23) st.italic = false;
+    else if (p === 24) st.underline = false;
+    else if (p === 27) st.reverse = false;
+    else if (p >= 30 && p <= 37) st.fg = BASE16[p - 30];
+    else if (p >= 90 && p <= 97) st.fg = BASE16[p - 90 + 8];
+    else if (p >= 40 && p <= 47) st.bg = BASE16[p - 40];
+    else if (p >= 100 && p <= 107) st.bg = BASE16[p - 100 + 8];
+    else if (p === 39) st.fg = null;
+    else if (p === 49) st.bg = null;
+    else if (p === 38 || p === 48) {
+      let c = null;
+      if (params[i + 1] === 5) { c = color256(params[i + 2] || 0); i += 2; }
+      else if (params[i + 1] === 2) { c = `rgb(${params[i + 2] || 0},${params[i + 3] || 0},${params[i + 4] || 0})`; i += 4; }
+      if (p === 38) st.fg = c; else st.bg = c;
+    }
+  }
+  style = st;
+}
+
+function csi(privateMode, params, final) {
+  const n = Math.max(1, params[0] || 0);
+  if (privateMode) {
+    const on = final === 'h';
+    if (final !== 'h' && final !== 'l') return;
+    for (const p of params) {
+      if (p === 25) cursorVisible = on;
+      else if (p === 1049 || p === 1047 || p === 47) setAltScreen(on);
+    }
+    return;
+  }
+  wrapPending = false;
+  switch (final) {
+    case 'A': cy = Math.max(0, cy - n); break;
+    case 'B': cy = Math.min(rows - 1, cy + n); break;
+    case 'C': cx = Math.min(cols - 1, cx + n); break;
+    case 'D': cx = Math.max(0, cx - n); break;
+    case 'E': cy = Math.min(rows - 1, cy + n); cx = 0; break;
+    case 'F': cy = Math.max(0, cy - n); cx = 0; break;
+    case 'G': cx = Math.min(cols - 1, n - 1); break;
+    case 'd': cy = Math.min(rows - 1, n - 1); break;
+    case 'H': case 'f':
+      cy = Math.min(rows - 1, Math.max(1, params[0] || 1) - 1);
+      cx = Math.min(cols - 1, Math.max(1, params[1] || 1) - 1);
+      break;
+    case 'J': eraseDisplay(params[0] || 0); break;
+    case 'K': eraseLine(params[0] || 0); break;
+    case 'X': for (let x = cx; x < Math.min(cols, cx + n); x++) screen[cy][x] = blankCell(); break;
+    case 'P': screen[cy].splice(cx, n); while (screen[cy].length < cols) screen[cy].push(blankCell()); break;
+    case '@': for (let i = 0; i < n; i++) screen[cy].splice(cx, 0, blankCell()); screen[cy].length = cols; break;
+    case 'L': for (let i = 0; i < n; i++) { screen.splice(cy, 0, blankLine()); screen.pop(); } break;
+    case 'M': for (let i = 0; i < n; i++) { screen.splice(cy, 1); screen.push(blankLine()); } break;
+    case 'S': for (let i = 0; i < n; i++) scrollUp(); break;
+    case 'm': sgr(params); break;
+    case 's': saved = { cx, cy }; break;
+    case 'u': ({ cx, cy } = saved); break;
+  }
+}
+
+// Parser state survives between chunks, so sequences may be split.
+let state = 'ground', seq = '';
+
+function write(text) {
+  for (const ch of text) {
+    switch (state) {
+      case 'ground':
+        if (ch === '\x1b') { state = 'esc'; seq = ''; }
+        else if (ch === '\r') { cx = 0; wrapPending = false; }
+        else if (ch === '\n') { lineFeed(); wrapPending = false; }
+        else if (ch === '\b') { cx = Math.max(0, cx - 1); wrapPending = false; }
+        else if (ch === '\t') { cx = Math.min(cols - 1, (Math.floor(cx / 8) + 1) * 8); }
+        else if (ch >= ' ') putChar(ch);
+        break;
+      case 'esc':
+        if (ch === '[') state = 'csi';
+        else if (ch === ']' || ch === 'P' || ch === '_') state = 'string';
+        else if (ch === '(' || ch === ')') state = 'charset';
+        else {
+          if (ch === '7') saved = { cx, cy };
+          else if (ch === '8') ({ cx, cy } = saved);
+          else if (ch === 'M') { if (cy === 0) { screen.pop(); screen.unshift(blankLine()); } else cy--; }
+          else if (ch === 'c') { style = { ...DEFAULT_STYLE }; eraseDisplay(2); cx = cy = 0; }
+          state = 'ground';
+        }
+        break;
+      case 'charset':
+        state = 'ground';
+        break;
+      case 'csi':
+        if (ch >= '@' && ch <= '~') {
+          const priv = seq.startsWith('?') || seq.startsWith('>') || seq.startsWith('=');
+          const params = seq.replace(/^[?>=]/, '').split(/[;:]/).map(p => (p === '' ? 0 : parseInt(p, 10) || 0));
+          csi(priv, seq === '' ? [] : params, ch);
+          state = 'ground';
+        } else {
+          seq += ch;
+        }
+        break;
+      case 'string': // OSC / DCS / APC: skip to BEL or ST
+        if (ch === '\x07') state = 'ground';
+        else if (ch === '\x1b') state = 'stringEsc';
+        break;
+      case 'stringEsc':
+        state = ch === '\\' ? 'ground' : 'string';
+        break;
+    }
+  }
+  scheduleRender();
+}
+
+/* ═══════════════════════════════════════
+   RENDERING
+═══════════════════════════════════════ */
+let renderQueued = false;
+
+function scheduleRender() {
+  if (renderQueued) return;
+  renderQueued = true;
+  requestAnimationFrame(render);
+}
+
+function applyStyle(span, st) {
+  let fg = st.fg, bg = st.bg;
+  if (st.reverse) [fg, bg] = [bg || '#1a0e05', fg || '#f0d8a0'];
+  if (fg) span.style.color = fg;
+  if (bg) span.style.backgroundColor = bg;
+  if (st.bold) span.style.fontWeight = 'bold';
+  if (st.faint) span.style.opacity = '0.6';
+  if (st.italic) span.style.fontStyle = 'italic';
+  if (st.underline) span.style.textDecoration = 'underline';
+}
+
+function render() {
+  renderQueued = false;
+  const frag = document.createDocumentFragment();
+  for (let y = 0; y < rows; y++) {
+    const line = screen[y];
+    let x = 0;
+    while (x < cols) {
+      // Group runs of equally styled cells into one span.
+      const st = line[x].st;
+      const isCursor = cursorVisible && y === cy && x === cx;
+      let text = '';
+      do {
+        text += line[x].ch;
+        x++;
+      } while (x < cols && line[x].st === st && !isCursor && !(cursorVisible && y === cy && x === cx));
+      const span = document.createElement('span');
+      span.textContent = text;
+      applyStyle(span, st);
+      if (isCursor) span.className = 'cursor';
+      frag.appendChild(span);
+    }
+    frag.appendChild(document.createTextNode('\n'));
+  }
+  termEl.replaceChildren(frag);
+}
+
+/* ═══════════════════════════════════════
+   SIZE
+═══════════════════════════════════════ */
+function measure() {
+  const probe = document.createElement('span');
+  probe.id = 'measure';
+  probe.textContent = 'M'.repeat(10);
+  termEl.appendChild(probe);
+  const rect = probe.getBoundingClientRect();
+  probe.remove();
+  const cs = getComputedStyle(termEl);
+  const w = termEl.clientWidth - parseFloat(cs.paddingLeft) - parseFloat(cs.paddingRight);
+  const h = termEl.clientHeight - parseFloat(cs.paddingTop) - parseFloat(cs.paddingBottom);
+  return {
+    cols: Math.max(40, Math.floor(w / (rect.width / 10))),
+    rows: Math.max(12, Math.floor(h / rect.height)),
+  };
+}
+
+/* ═══════════════════════════════════════
+   CONNECTION + KEYBOARD
+═══════════════════════════════════════ */
+const KEYS = {
+  Enter: '\r', Backspace: '\x7f', Tab: '\t', Escape: '\x1b', Delete: '\x1b[3~',
+  ArrowUp: '\x1b[A', ArrowDown: '\x1b[B', ArrowRight: '\x1b[C', ArrowLeft: '\x1b[D',
+  Home: '\x1b[H', End: '\x1b[F', PageUp: '\x1b[5~', PageDown: '\x1b[6~',
+};
+
+function keyInput(e) {
+  if (e.ctrlKey && !e.altKey && e.key.length === 1) {
+    const c = e.key.toUpperCase().charCodeAt(0);
+    if (c >= 64 && c <= 95) return String.fromCharCode(c - 64);
+    return null;
+  }
+  if (e.key === 'Tab' && e.shiftKey) return '\x1b[Z';
+  if (KEYS[e.key]) return KEYS[e.key];
+  if (e.key.length === 1 && !e.metaKey) return (e.altKey ? '\x1b' : '') + e.key;
+  return null;
+}
+
+function connect() {
+  const proto = location.protocol === 'https:' ? 'wss:' : 'ws:';
+  const ws = new WebSocket(`${proto}//${location.host}/ws/terminal`);
+  ws.binaryType = 'arraybuffer';
+  const decoder = new TextDecoder();
+  let size = measure();
+  resizeScreen(size.cols, size.rows);
+
+  ws.onopen = () => {
+    statusEl.textContent = 'connected';
+    let token = '';
+    try { token = localStorage.getItem('frem_token') || ''; } catch {}
+    const params = new URLSearchParams(location.search);
+    ws.send(JSON.stringify({ cols, rows, token, name: params.get('name') || '', watch: params.get('watch') || '' }));
+    termEl.focus();
+  };
+  ws.onmessage = e => write(typeof e.data === 'string' ? e.data : decoder.decode(e.data, { stream: true }));
+  ws.onclose = () => {
+    statusEl.textContent = 'disconnected — reload the page to start again';
+    cursorVisible = false;
+    scheduleRender();
+  };
+
+  termEl.addEventListener('keydown', e => {
+    const data = keyInput(e);
+    if (data === null || ws.readyState !== WebSocket.OPEN) return;
+    e.preventDefault();
+    ws.send('i' + data);
+  });
+  termEl.addEventListener('paste', e => {
+    e.preventDefault();
+    const text = e.clipboardData.getData('text');
+    if (text && ws.readyState === WebSocket.OPEN) ws.send('i' + text);
+  });
+  window.addEventListener('resize', () => {
+    const next = measure();
+    if (next.cols === cols && next.rows === rows) return;
+    resizeScreen(next.cols, next.rows);
+    scheduleRender();
+    if (ws.readyState === WebSocket.OPEN) ws.send('r' + JSON.stringify(next));
+  });
+}
+
+connect();
+})();
@@ -0,0 +1,513 @@
+package instrument
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/gobold"
+	"golang.org/x/image/font/gofont/goregular"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/math/fixed"
+	"golang.org/x/image/vector"
+)
+
+// DiagramOpts controls how RenderSVG and RenderPNG draw the fretboard.
+type DiagramOpts struct {
+	FromFret int    // first fret shown; 0 or 1 starts at the nut
+	ToFret   int    // last fret shown; 0 = the instrument's last fret
+	Title    string // caption above the fretboard, optional
+
+	HideIntervals bool // show unsolved interval positions without their label
+	LeftHanded    bool // mirror left to right: the nut on the right
+	FlipStrings   bool // lowest string on top
+
+	FretSetStart int  // first fret of a highlighted fret set; 0 = none
+	FretSetEnd   int  // last fret of the fret set (inclusive)
+	ShowCursor   bool // outline the position at CursorString, CursorFret
+	CursorString int  // index into Instrument.Strings
+	CursorFret   int  // 1-indexed
+}
+
+// window returns the first and last fret shown, clamped to the instrument.
+func (o DiagramOpts) window(frets int) (from, to int) {
+	from, to = max(o.FromFret, 1), o.ToFret
+	if to <= 0 || to > frets {
+		to = frets
+	}
+	return min(from, to), to
+}
+
+// Diagram colours, from the web page's default theme.
+var (
+	diagramBG      = color.RGBA{0x1a, 0x0e, 0x05, 0xff}
+	diagramBoard   = color.RGBA{0x2a, 0x16, 0x08, 0xff}
+	diagramFret    = color.RGBA{0x9a, 0x74, 0x28, 0xff}
+	diagramNut     = color.RGBA{0xd8, 0xc8, 0x88, 0xff}
+	diagramString  = color.RGBA{0xc8, 0xa0, 0x30, 0xff}
+	diagramFg      = color.RGBA{0xf0, 0xd8, 0xa0, 0xff}
+	diagramDim     = color.RGBA{0xa0, 0x88, 0x58, 0xff}
+	diagramGold    = color.RGBA{0xd8, 0x98, 0x38, 0xff}
+	diagramRoot    = color.RGBA{0xb0, 0x40, 0x20, 0xff}
+	diagramCorrect = color.RGBA{0x6a, 0x98, 0x40, 0xff}
+	diagramWrong   = color.RGBA{0xb0, 0x38, 0x28, 0xff}
+	diagramSet     = color.RGBA{0x3c, 0x23, 0x0b, 0xff} // the page's fret set tint on the board
+	diagramCursor  = color.RGBA{0xc8, 0x90, 0x30, 0xff}
+)
+
+// Diagram layout, in SVG user units (PNG pixels at scale 1).
+const (
+	diagramMargin    = 12.0
+	diagramLabelW    = 24.0 // string names
+	diagramOpenW     = 30.0 // open and muted strings, left of the nut
+	diagramFretW     = 48.0
+	diagramStringGap = 28.0
+	diagramTitleH    = 28.0
+	diagramNumbersH  = 20.0 // fret numbers above the board
+	diagramDotR      = 11.0
+)
+
+// RenderSVG draws the fretboard as an SVG image. Like Render, it shows what
+// the notes are marked with: note names (ShowName, Solved, Revealed),
+// interval labels, marked and pending positions and muted strings.
+func RenderSVG(inst *Instrument, opts DiagramOpts) []byte {
+	return buildDiagram(inst, opts).svg()
+}
+
+// RenderPNG draws the same image as RenderSVG as a PNG, at twice the size
+// for high-density screens.
+func RenderPNG(inst *Instrument, opts DiagramOpts) ([]byte, error) {
+	return buildDiagram(inst, opts).png(2)
+}
+
+// diagram is a list of shapes that is either written out as SVG or
+// rasterized to PNG, so both formats draw the same picture.
+type diagram struct {
+	width, height float64
+	title         string
+	hideIntervals bool
+	shapes        []diagramShape
+}
+
+type diagramShape interface {
+	svg(*bytes.Buffer)
+	raster(*diagramCanvas)
+	// mirrored returns the shape flipped left to right in a diagram of the
+	// given width; text stays readable.
+	mirrored(width float64) diagramShape
+}
+
+func buildDiagram(inst *Instrument, opts DiagramOpts) *diagram {
+	from, to := opts.window(inst.Frets)
+	frets := to - from + 1
+	d := &diagram{title: opts.Title, hideIntervals: opts.HideIntervals}
+
+	top := diagramMargin
+	if opts.Title != "" {
+		top += diagramTitleH
+	}
+	boardX := diagramMargin + diagramLabelW + diagramOpenW
+	firstY := top + diagramNumbersH + diagramDotR
+	lastY := firstY + float64(len(inst.Strings)-1)*diagramStringGap
+	d.width = boardX + float64(frets)*diagramFretW + diagramMargin
+	d.height = lastY + diagramDotR + diagramMargin
+
+	d.add(diagramRect{0, 0, d.width, d.height, diagramBG})
+	if opts.Title != "" {
+		d.add(diagramText{d.width / 2, diagramMargin + diagramTitleH/2, 15, true, diagramFg, opts.Title})
+	}
+	d.add(diagramRect{boardX, firstY - diagramStringGap/2, float64(frets) * diagramFretW,
+		lastY - firstY + diagramStringGap, diagramBoard})
+	if opts.FretSetStart > 0 {
+		setFrom, setTo := max(opts.FretSetStart, from), min(opts.FretSetEnd, to)
+		if setFrom <= setTo {
+			d.add(diagramRect{boardX + float64(setFrom-from)*diagramFretW, firstY - diagramStringGap/2,
+				float64(setTo-setFrom+1) * diagramFretW, lastY - firstY + diagramStringGap, diagramSet})
+		}
+	}
+
+	// Fret numbers: the marker frets, as on the TUI, and the first fret of
+	// a window away from the nut so the position is clear.
+	for f := from; f <= to; f++ {
+		if markerFrets[f] || (f == from && from > 1) {
+			x := boardX + (float64(f-from)+0.5)*diagramFretW
+			d.add(diagramText{x, top + diagramNumbersH/2, 11, false, diagramDim, strconv.Itoa(f)})
+		}
+	}
+	for k := 0; k <= frets; k++ {
+		x := boardX + float64(k)*diagramFretW
+		d.add(diagramLine{x, firstY, x, lastY, 2, diagramFret})
+	}
+	if from == 1 {
+		d.add(diagramLine{boardX, firstY - 1, boardX, lastY + 1, 6, diagramNut})
+	}
+
+	for i, s := range inst.Strings {
+		row := i
+		if opts.FlipStrings {
+			row = len(inst.Strings) - 1 - i
+		}
+		y := firstY + float64(row)*diagramStringGap
+		// Lower strings are drawn thicker.
+		width := 1 + 2*float64(i)/float64(max(len(inst.Strings)-1, 1))
+		d.add(diagramLine{boardX, y, d.width - diagramMargin, y, width, diagramString})
+		d.add(diagramText{diagramMargin + diagramLabelW/2, y, 12, false, diagramDim, sharpPart(s.Notes[0].Name)})
+
+		openX := boardX - diagramOpenW/2
+		if s.Notes[0].Muted {
+			d.add(diagramText{openX, y, 15, true, diagramDim, "×"})
+		} else {
+			d.addNote(s.Notes[0], openX, y)
+		}
+		for f := from; f <= to && f < len(s.Notes); f++ {
+			d.addNote(s.Notes[f], boardX+(float64(f-from)+0.5)*diagramFretW, y)
+		}
+		if opts.ShowCursor && i == opts.CursorString && opts.CursorFret >= from && opts.CursorFret <= to {
+			x := boardX + (float64(opts.CursorFret-from)+0.5)*diagramFretW
+			d.add(diagramCircle{x, y, diagramDotR + 3, diagramCursor, 2})
+		}
+	}
+	if opts.LeftHanded {
+		for i, s := range d.shapes {
+			d.shapes[i] = s.mirrored(d.width)
+		}
+	}
+	return d
+}
+
+// addNote draws the mark of one note, following renderCell.
+func (d *diagram) addNote(n Note, x, y float64) {
+	dot := func(fill color.RGBA, label string) {
+		d.add(diagramCircle{x, y, diagramDotR, fill, 0})
+		size := 12.0
+		switch runes := len([]rune(label)); {
+		case runes > 3:
+			size = 8
+		case runes > 2:
+			size = 9
+		}
+		d.add(diagramText{x, y, size, true, diagramBG, label})
+	}
+	ring := func(label string) {
+		d.add(diagramCircle{x, y, diagramDotR - 1, diagramGold, 2})
+		if label != "" {
+			d.add(diagramText{x, y, 12, true, diagramGold, label})
+		}
+	}
+	switch {
+	case n.ShowName, n.Interval != "" && n.Solved:
+		dot(diagramCorrect, sharpPart(n.Name))
+	case n.Interval != "" && d.hideIntervals && n.Marked:
+		dot(diagramGold, "")
+	case n.Interval != "" && d.hideIntervals:
+		ring("")
+	case n.Interval == "1":
+		dot(diagramRoot, n.Interval)
+	case n.Interval != "":
+		dot(diagramGold, n.Interval)
+	case n.Revealed && n.Correct:
+		dot(diagramCorrect, sharpPart(n.Name))
+	case n.Revealed:
+		dot(diagramWrong, sharpPart(n.Name))
+	case n.ToBeDetermined:
+		ring("?")
+	case n.Solved:
+		d.add(diagramCircle{x, y, diagramDotR, diagramCorrect, 0})
+	case n.Marked:
+		ring("")
+	}
+}
+
+func (d *diagram) add(s diagramShape) { d.shapes = append(d.shapes, s) }
+
+// sharpPart returns the first spelling of a note name ("C#/Db" → "C#").
+func sharpPart(name string) string {
+	sharp, _, _ := strings.Cut(name, "/")
+	return sharp
+}
+
+// ── SVG ───────────────────────────────────────────────────────────────────────
+
+func (d *diagram) svg() []byte {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%s" height="%s" viewBox="0 0 %s %s" role="img"`,
+		svgNum(d.width), svgNum(d.height), svgNum(d.width), svgNum(d.height))
+	b.WriteString(` font-family="Go, 'DejaVu Sans', Arial, sans-serif">`)
+	if d.title != "" {
+		b.WriteString("<title>")
+		xml.EscapeText(&b, []byte(d.title))
+		b.WriteString("</title>")
+	}
+	for _, s := range d.shapes {
+		s.svg(&b)
+	}
+	b.WriteString("</svg>\n")
+	return b.Bytes()
+}
+
+func svgNum(v float64) string {
+	return strconv.FormatFloat(math.Round(v*100)/100, 'f', -1, 64)
+}
+
+func svgColor(c color.RGBA) string {
+	return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+}
+
+type diagramRect struct {
+	x, y, w, h float64
+	fill       color.RGBA
+}
+
+func (r diagramRect) mirrored(width float64) diagramShape {
+	r.x = width - r.x - r.w
+	return r
+}
+
+func (r diagramRect) svg(b *bytes.Buffer) {
+	fmt.Fprintf(b, `<rect x="%s" y="%s" width="%s" height="%s" fill="%s"/>`,
+		svgNum(r.x), svgNum(r.y), svgNum(r.w), svgNum(r.h), svgColor(r.fill))
+}
+
+type diagramLine struct {
+	x1, y1, x2, y2 float64
+	width          float64
+	stroke         color.RGBA
+}
+
+func (l diagramLine) mirrored(width float64) diagramShape {
+	l.x1, l.x2 = width-l.x1, width-l.x2
+	return l
+}
+
+func (l diagramLine) svg(b *bytes.Buffer) {
+	fmt.Fprintf(b, `<line x1="%s" y1="%s" x2="%s" y2="%s" stroke="%s" stroke-width="%s"/>`,
+		svgNum(l.x1), svgNum(l.y1), svgNum(l.x2), svgNum(l.y2), svgColor(l.stroke), svgNum(l.width))
+}
+
+// diagramCircle is a filled disc, or a ring when stroke is non-zero.
+type diagramCircle struct {
+	x, y, r float64
+	color   color.RGBA
+	stroke  float64
+}
+
+func (c diagramCircle) mirrored(width float64) diagramShape {
+	c.x = width - c.x
+	return c
+}
+
+func (c diagramCircle) svg(b *bytes.Buffer) {
+	paint := `fill="` + svgColor(c.color) + `"`
+	if c.stroke > 0 {
+		paint = `fill="none" stroke="` + svgColor(c.color) + `" stroke-width="` + svgNum(c.stroke) + `"`
+	}
+	fmt.Fprintf(b, `<circle cx="%s" cy="%s" r="%s" %s/>`, svgNum(c.x), svgNum(c.y), svgNum(c.r), paint)
+}
+
+// diagramText is a line of text centred on (x, y).
+type diagramText struct {
+	x, y, size float64
+	bold       bool
+	color      color.RGBA
+	text       string
+}
+
+// baseline returns the y of the baseline that centres the text's capitals
+// on t.y.
+func (t diagramText) baseline() float64 { return t.y + 0.36*t.size }
+
+func (t diagramText) mirrored(width float64) diagramShape {
+	t.x = width - t.x
+	return t
+}
+
+func (t diagramText) svg(b *bytes.Buffer) {
+	weight := ""
+	if t.bold {
+		weight = ` font-weight="bold"`
+	}
+	fmt.Fprintf(b, `<text x="%s" y="%s" font-size="%s"%s fill="%s" text-anchor="middle">`,
+		svgNum(t.x), svgNum(t.baseline()), svgNum(t.size), weight, svgColor(t.color))
+	xml.EscapeText(b, []byte(t.text))
+	b.WriteString("</text>")
+}
+
+// ── PNG ───────────────────────────────────────────────────────────────────────
+
+// diagramCanvas rasterizes shapes at scale times their SVG size.
+type diagramCanvas struct {
+	img   *image.RGBA
+	scale float64
+	r     vector.Rasterizer
+	faces map[diagramFaceKey]font.Face
+}
+
+type diagramFaceKey struct {
+	size float64
+	bold bool
+}
+
+func (d *diagram) png(scale float64) ([]byte, error) {
+	if _, err := diagramFonts(); err != nil {
+		return nil, err
+	}
+	w, h := int(math.Ceil(d.width*scale)), int(math.Ceil(d.height*scale))
+	c := &diagramCanvas{
+		img:   image.NewRGBA(image.Rect(0, 0, w, h)),
+		scale: scale,
+		faces: map[diagramFaceKey]font.Face{},
+	}
+	for _, s := range d.shapes {
+		s.raster(c)
+	}
+	for _, f := range c.faces {
+		f.Close()
+	}
+	var b bytes.Buffer
+	if err := png.Encode(&b, c.img); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+// fill draws the path built by path, which gets coordinates relative to the
+// top-left corner of the given bounding box, in canvas pixels.
+func (c *diagramCanvas) fill(minX, minY, maxX, maxY float64, col color.RGBA, path func(r *vector.Rasterizer, ox, oy float64)) {
+	s := c.scale
+	bounds := image.Rect(int(math.Floor(minX*s)), int(math.Floor(minY*s)),
+		int(math.Ceil(maxX*s)), int(math.Ceil(maxY*s))).Intersect(c.img.Bounds())
+	if bounds.Empty() {
+		return
+	}
+	c.r.Reset(bounds.Dx(), bounds.Dy())
+	c.r.DrawOp = draw.Over
+	path(&c.r, float64(bounds.Min.X), float64(bounds.Min.Y))
+	c.r.Draw(c.img, bounds, image.NewUniform(col), image.Point{})
+}
+
+func (r diagramRect) raster(c *diagramCanvas) {
+	s := c.scale
+	c.fill(r.x, r.y, r.x+r.w, r.y+r.h, r.fill, func(ras *vector.Rasterizer, ox, oy float64) {
+		x0, y0 := float32(r.x*s-ox), float32(r.y*s-oy)
+		x1, y1 := float32((r.x+r.w)*s-ox), float32((r.y+r.h)*s-oy)
+		ras.MoveTo(x0, y0)
+		ras.LineTo(x1, y0)
+		ras.LineTo(x1, y1)
+		ras.LineTo(x0, y1)
+		ras.ClosePath()
+	})
+}
+
+func (l diagramLine) raster(c *diagramCanvas) {
+	s := c.scale
+	dx, dy := l.x2-l.x1, l.y2-l.y1
+	length := math.Hypot(dx, dy)
+	if length == 0 {
+		return
+	}
+	// The line is a rectangle around the segment, like an SVG butt cap.
+	nx, ny := -dy/length*l.width/2, dx/length*l.width/2
+	pad := l.width / 2
+	c.fill(min(l.x1, l.x2)-pad, min(l.y1, l.y2)-pad, max(l.x1, l.x2)+pad, max(l.y1, l.y2)+pad, l.stroke,
+		func(ras *vector.Rasterizer, ox, oy float64) {
+			pt := func(x, y float64) (float32, float32) { return float32(x*s - ox), float32(y*s - oy) }
+			ras.MoveTo(pt(l.x1+nx, l.y1+ny))
+			ras.LineTo(pt(l.x2+nx, l.y2+ny))
+			ras.LineTo(pt(l.x2-nx, l.y2-ny))
+			ras.LineTo(pt(l.x1-nx, l.y1-ny))
+			ras.ClosePath()
+		})
+}
+
+func (ci diagramCircle) raster(c *diagramCanvas) {
+	s := c.scale
+	outer := ci.r + ci.stroke/2
+	c.fill(ci.x-outer, ci.y-outer, ci.x+outer, ci.y+outer, ci.color, func(ras *vector.Rasterizer, ox, oy float64) {
+		cx, cy := ci.x*s-ox, ci.y*s-oy
+		if ci.stroke == 0 {
+			circlePath(ras, cx, cy, ci.r*s, false)
+			return
+		}
+		// A ring: the inner circle runs the other way and cancels out.
+		circlePath(ras, cx, cy, outer*s, false)
+		circlePath(ras, cx, cy, (ci.r-ci.stroke/2)*s, true)
+	})
+}
+
+// circlePath adds a circle made of four cubic Bézier arcs.
+func circlePath(ras *vector.Rasterizer, cx, cy, r float64, reverse bool) {
+	const k = 0.5522847498 // control point distance for a quarter circle
+	dir := 1.0
+	if reverse {
+		dir = -1
+	}
+	pt := func(x, y float64) (float32, float32) { return float32(cx + x*r), float32(cy + y*dir*r) }
+	ras.MoveTo(pt(1, 0))
+	for _, q := range [4][3][2]float64{
+		{{1, k}, {k, 1}, {0, 1}},
+		{{-k, 1}, {-1, k}, {-1, 0}},
+		{{-1, -k}, {-k, -1}, {0, -1}},
+		{{k, -1}, {1, -k}, {1, 0}},
+	} {
+		ax, ay := pt(q[0][0], q[0][1])
+		bx, by := pt(q[1][0], q[1][1])
+		x, y := pt(q[2][0], q[2][1])
+		ras.CubeTo(ax, ay, bx, by, x, y)
+	}
+	ras.ClosePath()
+}
+
+// diagramFonts are the Go fonts, parsed once.
+var diagramFonts = sync.OnceValues(func() (map[bool]*opentype.Font, error) {
+	regular, err := opentype.Parse(goregular.TTF)
+	if err != nil {
+		return nil, err
+	}
+	bold, err := opentype.Parse(gobold.TTF)
+	if err != nil {
+		return nil, err
+	}
+	return map[bool]*opentype.Font{false: regular, true: bold}, nil
+})
+
+func (c *diagramCanvas) face(size float64, bold bool) font.Face {
+	key := diagramFaceKey{size, bold}
+	if f, ok := c.faces[key]; ok {
+		return f
+	}
+	fonts, err := diagramFonts()
+	if err != nil {
+		return nil
+	}
+	f, err := opentype.NewFace(fonts[bold], &opentype.FaceOptions{
+		Size: size * c.scale, DPI: 72, Hinting: font.HintingNone,
+	})
+	if err != nil {
+		return nil
+	}
+	c.faces[key] = f
+	return f
+}
+
+func (t diagramText) raster(c *diagramCanvas) {
+	face := c.face(t.size, t.bold)
+	if face == nil {
+		return
+	}
+	dr := font.Drawer{Dst: c.img, Src: image.NewUniform(t.color), Face: face}
+	width := float64(dr.MeasureString(t.text)) / 64
+	dr.Dot = fixed.Point26_6{
+		X: fixed.Int26_6((t.x*c.scale - width/2) * 64),
+		Y: fixed.Int26_6(t.baseline() * c.scale * 64),
+	}
+	dr.DrawString(t.text)
+}
@@ -0,0 +1,154 @@
+package instrument
+
+import (
+	"bytes"
+	"encoding/xml"
+	"image/png"
+	"io"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// openC marks an open C major chord on a standard guitar.
+func openC(t *testing.T) *Instrument {
+	t.Helper()
+	g, err := NewGuitar(DefaultGuitarTuning(6), 12)
+	if err != nil {
+		t.Fatal(err)
+	}
+	g.Strings[0].Notes[0].Interval = "3"
+	g.Strings[1].Notes[1].Interval = "1"
+	g.Strings[2].Notes[0].Interval = "5"
+	g.Strings[3].Notes[2].Interval = "3"
+	g.Strings[4].Notes[3].Interval = "1"
+	g.Strings[5].Notes[0].Muted = true
+	return g
+}
+
+// svgTexts returns the contents of the <text> elements of an SVG image,
+// failing the test if it is not well-formed XML.
+func svgTexts(t *testing.T, svg []byte) []string {
+	t.Helper()
+	dec := xml.NewDecoder(bytes.NewReader(svg))
+	var texts []string
+	inText := false
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			return texts
+		}
+		if err != nil {
+			t.Fatalf("invalid SVG: %v\n%s", err, svg)
+		}
+		switch tok := tok.(type) {
+		case xml.StartElement:
+			inText = tok.Name.Local == "text"
+		case xml.EndElement:
+			inText = false
+		case xml.CharData:
+			if inText {
+				texts = append(texts, string(tok))
+			}
+		}
+	}
+}
+
+func TestRenderSVG(t *testing.T) {
+	g := openC(t)
+	g.Strings[2].Notes[2].ShowName = true // A
+	svg := RenderSVG(g, DiagramOpts{ToFret: 4, Title: "C <open> & co"})
+	texts := strings.Join(svgTexts(t, svg), " ")
+
+	// title, fret numbers 1 and 3, string names, ×, labels, the note name
+	want := "C <open> & co 1 3 E 3 B 1 G 5 A D 3 A 1 E ×"
+	if texts != want {
+		t.Errorf("SVG texts = %q, want %q", texts, want)
+	}
+	if !bytes.HasPrefix(svg, []byte(`<svg xmlns="http://www.w3.org/2000/svg" width="270"`)) {
+		t.Errorf("unexpected SVG header: %.80s", svg)
+	}
+}
+
+func TestRenderSVGWindow(t *testing.T) {
+	g := openC(t)
+	g.Strings[0].Notes[8].Interval = "b7"
+	svg := RenderSVG(g, DiagramOpts{FromFret: 6, ToFret: 9})
+	texts := svgTexts(t, svg)
+	// Fret 6 is numbered as the first fret of the window; positions
+	// outside it (fret 1, 2 and 3 here) are not drawn, open strings are.
+	for _, want := range []string{"6", "7", "9", "b7", "3", "5"} {
+		if !slices.Contains(texts, want) {
+			t.Errorf("SVG texts %q: missing %q", texts, want)
+		}
+	}
+	if slices.Contains(texts, "1") {
+		t.Errorf("SVG texts %q: positions outside the window drawn", texts)
+	}
+	if bytes.Contains(svg, []byte(`stroke-width="6"`)) {
+		t.Error("window away from the nut: nut drawn")
+	}
+	// A fret set or cursor outside the window is not drawn.
+	svg = RenderSVG(g, DiagramOpts{FromFret: 6, ToFret: 9, FretSetStart: 1, FretSetEnd: 3, ShowCursor: true, CursorFret: 2})
+	if bytes.Contains(svg, []byte(svgColor(diagramSet))) || bytes.Contains(svg, []byte(svgColor(diagramCursor))) {
+		t.Error("fret set or cursor outside the window drawn")
+	}
+}
+
+func TestDiagramWindow(t *testing.T) {
+	for _, tt := range []struct {
+		opts     DiagramOpts
+		from, to int
+	}{
+		{DiagramOpts{}, 1, 12},
+		{DiagramOpts{FromFret: 0, ToFret: 4}, 1, 4},
+		{DiagramOpts{FromFret: 5, ToFret: 30}, 5, 12},
+		{DiagramOpts{FromFret: 20}, 12, 12},
+	} {
+		if from, to := tt.opts.window(12); from != tt.from || to != tt.to {
+			t.Errorf("%+v: window = %d-%d, want %d-%d", tt.opts, from, to, tt.from, tt.to)
+		}
+	}
+}
+
+func TestRenderPNG(t *testing.T) {
+	g := openC(t)
+	opts := DiagramOpts{ToFret: 4, Title: "C"}
+	data, err := RenderPNG(g, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	img, err := png.Decode(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := buildDiagram(g, opts)
+	if b := img.Bounds(); b.Dx() != int(2*d.width) || b.Dy() != int(2*d.height) {
+		t.Errorf("PNG is %dx%d, want twice %gx%g", b.Dx(), b.Dy(), d.width, d.height)
+	}
+	// The root on string 2, fret 1 is a filled dot; sample it beside its label.
+	x := 2 * (diagramMargin + diagramLabelW + diagramOpenW + diagramFretW/2 - diagramDotR/2)
+	y := 2 * (diagramMargin + diagramTitleH + diagramNumbersH + diagramDotR + diagramStringGap)
+	r, gr, b, _ := img.At(int(x), int(y)).RGBA()
+	if got := [3]uint8{uint8(r >> 8), uint8(gr >> 8), uint8(b >> 8)}; got != [3]uint8{diagramRoot.R, diagramRoot.G, diagramRoot.B} {
+		t.Errorf("root dot pixel = %v, want %v", got, diagramRoot)
+	}
+}
+
+func TestRenderSVGLeftHanded(t *testing.T) {
+	g := openC(t)
+	d := buildDiagram(g, DiagramOpts{ToFret: 4})
+	m := buildDiagram(g, DiagramOpts{ToFret: 4, LeftHanded: true})
+	if len(d.shapes) != len(m.shapes) {
+		t.Fatalf("%d shapes, mirrored %d", len(d.shapes), len(m.shapes))
+	}
+	for i, s := range d.shapes {
+		if s.mirrored(d.width) != m.shapes[i] {
+			t.Errorf("shape %d: %+v is not the mirror of %+v", i, m.shapes[i], s)
+		}
+	}
+	// The labels keep reading left to right.
+	if got, want := svgTexts(t, m.svg()), svgTexts(t, d.svg()); !slices.Equal(got, want) {
+		t.Errorf("mirrored texts %q, want %q", got, want)
+	}
+}
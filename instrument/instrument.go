@@ -1,6 +1,11 @@
 package instrument
 
-import "fmt"
+import (
+	"fmt"
+	"slices"
+
+	"github.com/funkymcb/fremorizer/theory"
+)
 
 // InstrumentString holds the notes for a single string.
 type InstrumentString struct {
@@ -76,7 +81,7 @@ func NewUkulele(tuning []string, frets int) (*Instrument, error) {
 
 // DefaultGuitarTuning returns standard E tuning for guitar (6-8 strings).
 func DefaultGuitarTuning(numStrings int) []string {
-	base := []string{"E", "A", "D", "G", "B", "E"}
+	base := slices.Clone(theory.GuitarTuning)
 	switch numStrings {
 	case 7:
 		return append([]string{"B"}, base...)
@@ -89,7 +94,7 @@ func DefaultGuitarTuning(numStrings int) []string {
 
 // DefaultBassTuning returns standard tuning for bass (4-6 strings).
 func DefaultBassTuning(numStrings int) []string {
-	base := []string{"E", "A", "D", "G"}
+	base := slices.Clone(theory.BassTuning)
 	switch numStrings {
 	case 5:
 		return append([]string{"B"}, base...)
@@ -102,7 +107,7 @@ func DefaultBassTuning(numStrings int) []string {
 
 // DefaultUkuleleTuning returns standard tuning for ukulele.
 func DefaultUkuleleTuning() []string {
-	return []string{"G", "C", "E", "A"}
+	return slices.Clone(theory.UkuleleTuning)
 }
 
 // NoteNames returns all canonical note names in chromatic order.
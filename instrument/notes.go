@@ -3,6 +3,8 @@ package instrument
 import (
 	"fmt"
 	"strings"
+
+	"github.com/funkymcb/fremorizer/theory"
 )
 
 type Note struct {
@@ -18,17 +20,32 @@ type Note struct {
 	ShowName       bool   // mode 4: free learning — display note name in green
 }
 
-var noteOrder = []string{
-	"C", "C#/Db", "D", "D#/Eb", "E", "F", "F#/Gb", "G", "G#/Ab", "A", "A#/Bb", "B",
-}
+// noteOrder holds the canonical note names in chromatic order, sharps and
+// flats together ("C#/Db").
+var noteOrder = func() []string {
+	names := make([]string, 0, len(theory.Chromatic))
+	for _, n := range theory.Chromatic {
+		if flat, ok := theory.Flats[n]; ok {
+			n += "/" + flat
+		}
+		names = append(names, n)
+	}
+	return names
+}()
 
-var noteIndexMap = map[string]int{
-	"C": 0, "C#/Db": 1, "D": 2, "D#/Eb": 3, "E": 4, "F": 5,
-	"F#/Gb": 6, "G": 7, "G#/Ab": 8, "A": 9, "A#/Bb": 10, "B": 11,
-	// aliases for input
-	"C#": 1, "Db": 1, "D#": 3, "Eb": 3, "F#": 6, "Gb": 6,
-	"G#": 8, "Ab": 8, "A#": 10, "Bb": 10,
-}
+// noteIndexMap maps canonical names and their sharp and flat aliases (for
+// input) to pitch classes.
+var noteIndexMap = func() map[string]int {
+	m := map[string]int{}
+	for pc, n := range theory.Chromatic {
+		m[n] = pc
+		m[noteOrder[pc]] = pc
+		if flat, ok := theory.Flats[n]; ok {
+			m[flat] = pc
+		}
+	}
+	return m
+}()
 
 func calculateNoteName(openNote string, fret int) (string, error) {
 	openNote = strings.ToUpper(openNote)
@@ -53,11 +70,30 @@ func NoteToSemitone(name string) int {
 	return 0
 }
 
+// ScaleNotes returns the canonical names of the notes of a scale, given as
+// intervals in semitones, starting from root.
+func ScaleNotes(root string, intervals []int) []string {
+	r := NoteToSemitone(root)
+	names := make([]string, len(intervals))
+	for i, iv := range intervals {
+		names[i] = noteOrder[(r+iv)%12]
+	}
+	return names
+}
+
+// normalizeAnswer removes all whitespace from a typed note name and spells
+// the Unicode accidentals ♯ and ♭ as # and b, so "C #" and "D♭" are read
+// like "C#" and "Db". The web page's matchNote does the same.
+func normalizeAnswer(input string) string {
+	input = strings.Join(strings.Fields(input), "")
+	return strings.NewReplacer("♯", "#", "♭", "b").Replace(input)
+}
+
 // IsValidNote returns true if the input is a recognised note name.
 // Accepts natural notes ("C", "c"), sharps ("C#", "c#"), and flat aliases
 // ("Db", "db", "Bb", "bb") — matching is case-insensitive.
 func IsValidNote(input string) bool {
-	input = strings.TrimSpace(input)
+	input = normalizeAnswer(input)
 	if input == "" {
 		return false
 	}
@@ -80,7 +116,7 @@ func NoteMatches(noteName, answer string) bool {
 	if answer == "" {
 		return false
 	}
-	answer = strings.ToLower(strings.TrimSpace(answer))
+	answer = strings.ToLower(normalizeAnswer(answer))
 	// canonical name like "C#/Db" -> ["c#", "db"]
 	parts := strings.SplitSeq(strings.ToLower(noteName), "/")
 	for part := range parts {
@@ -144,6 +144,11 @@ func TestNoteMatches(t *testing.T) {
 		// Single-char note mismatch
 		{"G", "G#", false},
 		{"A", "B", false},
+		// Unicode accidentals and stray spaces, as the web page accepts them
+		{"C#/Db", "D♭", true},
+		{"C#/Db", "C♯", true},
+		{"C#/Db", "c #", true},
+		{"C#/Db", " ", false},
 	}
 	for _, tt := range tests {
 		got := NoteMatches(tt.noteName, tt.answer)
@@ -152,3 +157,19 @@ func TestNoteMatches(t *testing.T) {
 		}
 	}
 }
+
+func TestScaleNotes(t *testing.T) {
+	got := ScaleNotes("A", []int{0, 2, 3, 5, 7, 8, 10})
+	want := []string{"A", "B", "C", "D", "E", "F", "G"}
+	if len(got) != len(want) {
+		t.Fatalf("ScaleNotes(A minor) = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("ScaleNotes(A minor)[%d] = %q, want %q", i, got[i], want[i])
+		}
+	}
+	if got := ScaleNotes("C#/Db", []int{0, 4}); got[0] != "C#/Db" || got[1] != "F" {
+		t.Errorf("ScaleNotes(C#/Db, 0 4) = %v, want [C#/Db F]", got)
+	}
+}
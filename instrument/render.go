@@ -2,9 +2,12 @@ package instrument
 
 import (
 	"fmt"
+	"io"
+	"sort"
 	"strings"
 
 	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
 )
 
 type renderStyles struct {
@@ -30,7 +33,8 @@ func newRenderStyles(r *lipgloss.Renderer) renderStyles {
 
 // RenderOpts controls how the fretboard is rendered.
 type RenderOpts struct {
-	Renderer      *lipgloss.Renderer // nil = use default renderer
+	Output        FretboardRenderer  // nil = ASCIIRenderer
+	Renderer      *lipgloss.Renderer // lipgloss renderer for the colours; nil = use default renderer
 	Blink         int                // 0 or 1 for blinking animation
 	FretSetMode   bool               // mode 2: highlight a fret set
 	FretSetStart  int                // first fret of the highlighted set (1-indexed)
@@ -40,154 +44,510 @@ type RenderOpts struct {
 	ChordMode     bool               // mode 3: show chord interval labels; widens left label to 3 chars
 	HideIntervals bool               // mode 3 medium: replace unsolved interval labels with "x"
 	ShowCursor    bool               // show cursor independent of FretSetMode
+	Vertical      bool               // text renderers: strings as columns, frets as rows, like a chord chart
+	LeftHanded    bool               // mirror left to right: the nut on the right
+	FlipStrings   bool               // lowest string on top, as the player sees it, instead of the tab view
+	Compact       bool               // text renderers: narrower cells, and no fret wire rows when vertical
+	FromFret      int                // text renderers: first fret shown; 0 = 1
+	ToFret        int                // text renderers: last fret shown; 0 = the last fret
 }
 
-// Render returns an ASCII art representation of the fretboard.
+// window returns the first and last fret shown, clamped to the instrument.
+func (o RenderOpts) window(frets int) (from, to int) {
+	from, to = max(o.FromFret, 1), o.ToFret
+	if to <= 0 || to > frets {
+		to = frets
+	}
+	return min(from, to), to
+}
+
+// cellWidth returns the width of a horizontal fret cell, fret wire included.
+func (o RenderOpts) cellWidth() int {
+	if o.Compact {
+		return 4
+	}
+	return 6
+}
+
+// A FretboardRenderer draws a fretboard. RenderOpts.Output picks one, so the
+// same fretboard state can go to a terminal, a web page, a file or a test.
+type FretboardRenderer interface {
+	Render(inst *Instrument, opts RenderOpts) string
+}
+
+// The built-in renderers.
+var (
+	// ASCIIRenderer draws "|-----" fret cells coloured with lipgloss. It is
+	// the default.
+	ASCIIRenderer FretboardRenderer = textRenderer{}
+	// UnicodeRenderer draws the same layout with box-drawing characters.
+	UnicodeRenderer FretboardRenderer = textRenderer{unicode: true}
+	// PlainRenderer draws the ASCII layout without ANSI escape codes.
+	PlainRenderer FretboardRenderer = textRenderer{plain: true}
+	// SVGRenderer returns an SVG document, as RenderSVG does.
+	SVGRenderer FretboardRenderer = svgRenderer{}
+)
+
+// Render draws the fretboard with opts.Output, by default as ASCII art.
 func Render(inst *Instrument, opts RenderOpts) string {
-	var sb strings.Builder
-	st := newRenderStyles(opts.Renderer)
+	if opts.Output == nil {
+		return ASCIIRenderer.Render(inst, opts)
+	}
+	return opts.Output.Render(inst, opts)
+}
 
-	header := fmt.Sprintf("%s | tuning: %s | frets: %d",
+// renderHeader returns the line above the fretboard.
+func renderHeader(inst *Instrument) string {
+	return fmt.Sprintf("%s | tuning: %s | frets: %d",
 		inst.Type, strings.Join(inst.Tuning, "-"), inst.Frets)
+}
+
+// textRenderer draws the fretboard as rows of 6-character fret cells, or
+// 4-character ones when compact.
+type textRenderer struct {
+	unicode bool // box-drawing characters instead of | and -
+	plain   bool // no colours or other ANSI escape codes
+}
+
+// plainRenderer is a lipgloss renderer that writes no escape codes.
+var plainRenderer = func() *lipgloss.Renderer {
+	r := lipgloss.NewRenderer(io.Discard)
+	r.SetColorProfile(termenv.Ascii)
+	return r
+}()
+
+func (tr textRenderer) Render(inst *Instrument, opts RenderOpts) string {
+	var sb strings.Builder
+	r := opts.Renderer
+	if tr.plain {
+		r = plainRenderer
+	}
+	st := newRenderStyles(r)
+
+	header := renderHeader(inst)
+	if from, to := opts.window(inst.Frets); from > 1 || to < inst.Frets {
+		header += fmt.Sprintf(" | showing: %d-%d", from, to)
+	}
 	sb.WriteString(header + "\n")
+	if opts.Vertical {
+		board := renderVertical(inst.Strings, inst.Frets, opts, st)
+		if tr.unicode {
+			board = unicodeVertical.Replace(board)
+		}
+		sb.WriteString(board)
+		return sb.String()
+	}
 	sb.WriteString(renderMarkers(inst.Frets, opts, st) + "\n")
-	sb.WriteString(renderStrings(inst.Strings, opts, st))
+	sb.WriteString(renderStrings(inst.Strings, opts, tr.unicode, st))
 
 	return sb.String()
 }
 
-func renderMarkers(frets int, opts RenderOpts, st renderStyles) string {
+// Width returns the number of terminal columns the text renderers need to
+// draw inst with opts.
+func Width(inst *Instrument, opts RenderOpts) int {
+	return lipgloss.Width(PlainRenderer.Render(inst, opts))
+}
+
+// Height returns the number of terminal lines the text renderers need to
+// draw inst with opts.
+func Height(inst *Instrument, opts RenderOpts) int {
+	return strings.Count(PlainRenderer.Render(inst, opts), "\n")
+}
+
+// Fit sets the fret window of opts to the most frets that fit in width
+// columns and height lines, 0 meaning no limit, and reports whether the
+// whole fretboard fits. The window is centred on what the player is looking
+// for: the cursor, the fret set, the notes to name and the chord. The header
+// line is left out of the width, as it wraps without breaking the board.
+func Fit(inst *Instrument, opts RenderOpts, width, height int) (RenderOpts, bool) {
+	fits := func(n int) bool {
+		o := opts
+		o.FromFret, o.ToFret = Window(inst, opts, n)
+		out := PlainRenderer.Render(inst, o)
+		_, board, _ := strings.Cut(out, "\n")
+		return (width <= 0 || lipgloss.Width(board) <= width) &&
+			(height <= 0 || strings.Count(out, "\n") <= height)
+	}
+	// Fewer frets never take more room, so search for the first that does not fit.
+	n := sort.Search(inst.Frets, func(n int) bool { return !fits(n + 1) })
+	opts.FromFret, opts.ToFret = Window(inst, opts, max(n, 1))
+	return opts, n == inst.Frets
+}
+
+// Window returns a window of n frets around the focus of opts, as Fit
+// places it. Callers that keep the number of frets Fit found can move the
+// window with the cursor without fitting again.
+func Window(inst *Instrument, opts RenderOpts, n int) (from, to int) {
+	if n >= inst.Frets {
+		return 1, inst.Frets
+	}
+	lo, hi := 0, 0
+	focus := func(fret int) {
+		if lo == 0 || fret < lo {
+			lo = fret
+		}
+		hi = max(hi, fret)
+	}
+	if opts.FretSetMode {
+		focus(opts.FretSetStart)
+		focus(opts.FretSetEnd)
+	}
+	for _, s := range inst.Strings {
+		for fret := 1; fret < len(s.Notes); fret++ {
+			if note := s.Notes[fret]; note.ToBeDetermined || note.Interval != "" {
+				focus(fret)
+			}
+		}
+	}
+	cursor := (opts.FretSetMode || opts.ShowCursor) && opts.CursorFret > 0
+	if cursor {
+		focus(opts.CursorFret)
+	}
+	if lo == 0 {
+		return 1, n
+	}
+	from = lo - (n-(hi-lo+1))/2
+	if cursor && hi-lo+1 > n {
+		// too much to show: follow the cursor
+		from = opts.CursorFret - n/2
+	}
+	from = max(1, min(from, inst.Frets-n+1))
+	return from, from + n - 1
+}
+
+var (
+	unicodeCells    = strings.NewReplacer("|", "│", "-", "─")
+	unicodeVertical = strings.NewReplacer("|", "│", "-", "─", "+", "┼", "=", "═")
+)
+
+// unicode redraws the fret cells of a string row, every piece but the
+// label, with box-drawing characters, so a label is never redrawn even if it
+// contains | or -. If the nut is shown, it is the first | of the cells, or
+// the last one of that piece when mirrored, as mirrored rows are drawn in
+// reverse. Colours are kept, as escape codes contain neither | nor -.
+func (r *row) unicode(nut bool) {
+	for i := 1; i < len(r.fwd); i++ {
+		r.fwd[i] = unicodeCells.Replace(r.fwd[i])
+		r.mirrored[i] = unicodeCells.Replace(r.mirrored[i])
+	}
+	if !nut {
+		return
+	}
+	for i := 1; i < len(r.fwd); i++ {
+		if pos := strings.Index(r.fwd[i], "│"); pos >= 0 {
+			r.fwd[i] = r.fwd[i][:pos] + "║" + r.fwd[i][pos+len("│"):]
+			if pos := strings.LastIndex(r.mirrored[i], "│"); pos >= 0 {
+				r.mirrored[i] = r.mirrored[i][:pos] + "║" + r.mirrored[i][pos+len("│"):]
+			}
+			return
+		}
+	}
+}
+
+// svgRenderer draws the fretboard with RenderSVG: the fret window, the fret
+// set and the cursor carry over. ChordMode needs no option, as the diagram
+// always labels intervals and muted strings; Blink, Compact and Vertical
+// only apply to the text renderers.
+type svgRenderer struct{}
+
+func (svgRenderer) Render(inst *Instrument, opts RenderOpts) string {
+	d := DiagramOpts{
+		Title:         renderHeader(inst),
+		HideIntervals: opts.HideIntervals,
+		LeftHanded:    opts.LeftHanded,
+		FlipStrings:   opts.FlipStrings,
+	}
+	d.FromFret, d.ToFret = opts.window(inst.Frets)
+	if opts.FretSetMode {
+		d.FretSetStart, d.FretSetEnd = opts.FretSetStart, opts.FretSetEnd
+	}
+	if opts.FretSetMode || opts.ShowCursor {
+		d.ShowCursor, d.CursorString, d.CursorFret = true, opts.CursorString, opts.CursorFret
+	}
+	return string(RenderSVG(inst, d))
+}
+
+// markerFrets are the frets numbered above the fretboard.
+var markerFrets = map[int]bool{
+	1: true, 3: true, 5: true, 7: true, 9: true,
+	12: true, 15: true, 17: true, 19: true, 21: true, 24: true,
+}
+
+// row collects the pieces of one horizontal row. Each piece has its
+// right-handed text and its left-handed mirror image; a left-handed row
+// draws the mirror images in reverse order.
+type row struct {
+	fwd, mirrored []string
+}
+
+func (r *row) add(fwd, mirrored string) {
+	r.fwd = append(r.fwd, fwd)
+	r.mirrored = append(r.mirrored, mirrored)
+}
+
+func (r *row) String(leftHanded bool) string {
+	if !leftHanded {
+		return strings.Join(r.fwd, "")
+	}
 	var sb strings.Builder
-	if opts.ChordMode {
-		sb.WriteString("   ") // align with 3-char chord-mode label
-	} else {
-		sb.WriteString("  ") // align with string name prefix (2 chars, same as open-note label)
+	for i := len(r.mirrored) - 1; i >= 0; i-- {
+		sb.WriteString(r.mirrored[i])
 	}
+	return sb.String()
+}
 
-	markerFrets := map[int]bool{
-		1: true, 3: true, 5: true, 7: true, 9: true,
-		12: true, 15: true, 17: true, 19: true, 21: true, 24: true,
+func renderMarkers(frets int, opts RenderOpts, st renderStyles) string {
+	var r row
+	if opts.ChordMode {
+		r.add("   ", "   ") // align with 3-char chord-mode label
+	} else {
+		r.add("  ", "  ") // align with string name prefix (2 chars, same as open-note label)
 	}
 
-	for i := 1; i <= frets; i++ {
-		var cell string
+	from, to := opts.window(frets)
+	cw := opts.cellWidth()
+	for i := from; i <= to; i++ {
+		var cell, mirrored string
 		if markerFrets[i] {
-			// center the number within the 5-dash content area of a 6-char cell (|-----)
+			// center the number within the dash content area of the cell (|-----)
 			s := fmt.Sprintf("%d", i)
-			left := 1 + (5-len(s))/2
-			right := 6 - len(s) - left
+			left := 1 + (cw-1-len(s))/2
+			right := cw - len(s) - left
 			cell = strings.Repeat(" ", left) + s + strings.Repeat(" ", right)
+			mirrored = strings.Repeat(" ", right) + s + strings.Repeat(" ", left)
 		} else {
-			cell = "      " // 6 spaces — one per fret cell char
+			cell = strings.Repeat(" ", cw) // one space per fret cell char
+			mirrored = cell
 		}
 
 		// gap before fret set
 		if opts.FretSetMode && i == opts.FretSetStart {
-			sb.WriteString("  ")
+			r.add("  ", "  ")
 		}
 
 		if opts.FretSetMode && i >= opts.FretSetStart && i <= opts.FretSetEnd {
-			sb.WriteString(st.blue.Render(cell))
+			r.add(st.blue.Render(cell), st.blue.Render(mirrored))
 		} else {
-			sb.WriteString(cell)
+			r.add(cell, mirrored)
 		}
 
 		// gap after fret set — 3 spaces to align with the closing | added in string rows
 		if opts.FretSetMode && i == opts.FretSetEnd {
-			sb.WriteString("   ")
+			r.add("   ", "   ")
 		}
 	}
-	return sb.String()
+	// the string rows' closing |, which comes first when mirrored
+	r.add("", " ")
+	return r.String(opts.LeftHanded)
 }
 
-func renderStrings(strs []InstrumentString, opts RenderOpts, st renderStyles) string {
+// renderStrings draws one row per string, with box-drawing characters if
+// unicode is set.
+func renderStrings(strs []InstrumentString, opts RenderOpts, unicode bool, st renderStyles) string {
 	var sb strings.Builder
 
-	for strIdx, s := range strs {
-		// open string note name (left label)
+	for i := range strs {
+		strIdx := i
+		if opts.FlipStrings {
+			strIdx = len(strs) - 1 - i // lowest string on top
+		}
+		s := strs[strIdx]
+		var r row
+
+		// open string note name (left label; right-aligned on the right when mirrored)
+		var label string
 		openName := s.Notes[0].Name
 		if opts.ChordMode {
-			sb.WriteString(chordStringLabel(s.Notes[0], opts.HideIntervals, st))
+			label = chordStringLabel(s.Notes[0], opts.HideIntervals, st)
 		} else if len(openName) == 1 {
-			sb.WriteString(fmt.Sprintf("%s ", openName))
+			label = fmt.Sprintf("%s ", openName)
 		} else {
 			// for sharps/flats just show the first part
-			sb.WriteString(fmt.Sprintf("%s", strings.Split(openName, "/")[0]))
+			label = strings.Split(openName, "/")[0]
 		}
+		trimmed := strings.TrimRight(label, " ")
+		r.add(label, strings.Repeat(" ", len(label)-len(trimmed))+trimmed)
 
-		for fretIdx, note := range s.Notes {
-			if fretIdx == 0 {
-				continue // open string already rendered as label
-			}
+		from, to := opts.window(len(s.Notes) - 1)
+		for fretIdx := from; fretIdx <= to; fretIdx++ {
+			note := s.Notes[fretIdx] // the open string is already rendered as label
 
 			isCursor := (opts.FretSetMode || opts.ShowCursor) &&
 				strIdx == opts.CursorString &&
 				fretIdx == opts.CursorFret
 
-			cell := renderCell(note, opts.Blink, isCursor, opts.HideIntervals, st)
+			cell := renderCell(note, opts.Blink, isCursor, opts.HideIntervals, opts.Compact, false, st)
+			mirrored := renderCell(note, opts.Blink, isCursor, opts.HideIntervals, opts.Compact, true, st)
 
 			if opts.FretSetMode && fretIdx >= opts.FretSetStart && fretIdx <= opts.FretSetEnd {
 				// fret set frets: render in blue unless overridden by cursor/mark
 				if !isCursor && !note.Marked {
 					cell = st.blue.Render(cell)
+					mirrored = st.blue.Render(mirrored)
 				}
 			}
 
 			// visual gap before fret set
 			if opts.FretSetMode && fretIdx == opts.FretSetStart {
-				sb.WriteString("  ")
+				r.add("  ", "  ")
 			}
 
 			// fret immediately after the set: drop its opening | (the set already has a closing |)
 			if opts.FretSetMode && fretIdx == opts.FretSetEnd+1 {
 				cell = strings.TrimPrefix(cell, "|")
+				mirrored = strings.TrimSuffix(mirrored, "|")
 			}
 
-			sb.WriteString(cell)
+			r.add(cell, mirrored)
 
 			// closing | for fret set + gap
 			if opts.FretSetMode && fretIdx == opts.FretSetEnd {
-				sb.WriteString(st.blue.Render("|") + "  ")
+				r.add(st.blue.Render("|")+"  ", "  "+st.blue.Render("|"))
 			}
 		}
-		sb.WriteString("|\n")
+		r.add("|", "|")
+		if unicode {
+			r.unicode(from == 1)
+		}
+		sb.WriteString(r.String(opts.LeftHanded) + "\n")
 	}
 
 	return sb.String()
 }
 
-func renderCell(note Note, blink int, isCursor bool, hideIntervals bool, st renderStyles) string {
-	// Mode 4: free learning — show note name in green (cursor takes priority for color).
-	if note.ShowName {
+// vertCellWidth returns the width of a string column in the vertical layout.
+func (o RenderOpts) vertCellWidth() int {
+	if o.Compact {
+		return 3
+	}
+	return 5
+}
+
+// renderVertical draws the fretboard as a chord chart: one column per string,
+// the lowest on the left, and one row per fret below the nut, each followed
+// by its fret wire unless compact. LeftHanded and FlipStrings both reverse
+// the columns.
+func renderVertical(strs []InstrumentString, frets int, opts RenderOpts, st renderStyles) string {
+	var sb strings.Builder
+	cw := opts.vertCellWidth()
+	cols := make([]int, len(strs))
+	for i := range cols {
+		cols[i] = len(strs) - 1 - i
+		if opts.LeftHanded != opts.FlipStrings {
+			cols[i] = i
+		}
+	}
+
+	// open strings above the nut, as the left label of the horizontal layout
+	sb.WriteString("   ")
+	for _, si := range cols {
+		open := strs[si].Notes[0]
+		label := strings.Split(open.Name, "/")[0]
+		if opts.ChordMode {
+			label = strings.TrimRight(chordStringLabel(open, opts.HideIntervals, st), " ")
+		}
+		sb.WriteString(vertCell(label, cw))
+	}
+	sb.WriteString("\n")
+
+	half := strings.Repeat("-", cw/2)
+	wire := "   " + strings.Repeat(half+"+"+half, len(cols)) + "\n"
+	from, to := opts.window(frets)
+	if from == 1 {
+		sb.WriteString("   " + strings.Repeat("=", cw*len(cols)) + "\n")
+	} else {
+		sb.WriteString(wire)
+	}
+	for fret := from; fret <= to; fret++ {
+		inSet := opts.FretSetMode && fret >= opts.FretSetStart && fret <= opts.FretSetEnd
+		num := "   "
+		if markerFrets[fret] {
+			num = fmt.Sprintf("%2d ", fret)
+		}
+		if inSet {
+			num = st.blue.Render(num)
+		}
+		sb.WriteString(num)
+
+		for _, si := range cols {
+			note := strs[si].Notes[fret]
+			isCursor := (opts.FretSetMode || opts.ShowCursor) &&
+				si == opts.CursorString &&
+				fret == opts.CursorFret
+
+			m := noteMark(note, opts.Blink, isCursor, opts.HideIntervals, st)
+			cell := vertCell(strings.Split(strings.Trim(m.inner, "-"), "/")[0], cw)
+			if m.style != nil {
+				cell = m.style.Render(cell)
+			}
+			if inSet && !isCursor && !note.Marked {
+				cell = st.blue.Render(cell)
+			}
+			sb.WriteString(cell)
+		}
+		sb.WriteString("\n")
+		if !opts.Compact {
+			sb.WriteString(wire)
+		}
+	}
+	return sb.String()
+}
+
+// vertCell centres label on a string column of width w in the vertical
+// layout; an empty label shows the bare string.
+func vertCell(label string, w int) string {
+	if label == "" {
+		label = "|"
+	}
+	lw := lipgloss.Width(label)
+	if lw >= w {
+		return label
+	}
+	left := (w - lw) / 2
+	return strings.Repeat(" ", left) + label + strings.Repeat(" ", w-lw-left)
+}
+
+// cellMark is what a fret cell shows for a note: its 5-character content
+// ("-----" when empty) and the style it is drawn in.
+type cellMark struct {
+	inner string
+	style *lipgloss.Style // nil = unstyled
+	fret  bool            // the style covers the fret wire too
+}
+
+func noteMark(note Note, blink int, isCursor bool, hideIntervals bool, st renderStyles) cellMark {
+	styled := func(inner string, s lipgloss.Style) cellMark {
+		return cellMark{inner: inner, style: &s}
+	}
+	// cursorOr draws inner with the cursor style on the cursor, else with s.
+	cursorOr := func(inner string, s lipgloss.Style) cellMark {
 		if isCursor {
-			return "|" + st.cursor.Render(noteCellLabel(note.Name))
+			return styled(inner, st.cursor)
 		}
-		return "|" + st.green.Render(noteCellLabel(note.Name))
+		return styled(inner, s)
+	}
+
+	// Mode 4: free learning — show note name in green (cursor takes priority for color).
+	if note.ShowName {
+		return cursorOr(noteCellLabel(note.Name), st.green)
 	}
 
 	// Chord mode: interval-marked fret position
 	if note.Interval != "" {
 		if note.Solved {
-			if isCursor {
-				return "|" + st.cursor.Render(noteCellLabel(note.Name))
-			}
-			return "|" + st.green.Render(noteCellLabel(note.Name))
+			return cursorOr(noteCellLabel(note.Name), st.green)
 		}
 		if hideIntervals {
 			if note.Marked {
-				marked := "--●--"
-				if isCursor {
-					return "|" + st.cursor.Render(marked)
-				}
-				return "|" + st.marked.Render(marked)
+				return cursorOr("--●--", st.marked)
 			}
-			hidden := "--x--"
 			if isCursor {
-				return "|" + st.cursor.Render(hidden)
+				return styled("--x--", st.cursor)
 			}
-			return "|" + hidden
+			return cellMark{inner: "--x--"}
 		}
-		return "|" + intervalCellLabel(note.Interval)
+		return cellMark{inner: intervalCellLabel(note.Interval)}
 	}
 
 	if note.ToBeDetermined {
@@ -195,51 +555,87 @@ func renderCell(note Note, blink int, isCursor bool, hideIntervals bool, st rend
 			// just-revealed: show note name in correct color before advancing
 			label := noteCellLabel(note.Name)
 			if note.Correct {
-				return "|" + st.green.Render(label)
+				return styled(label, st.green)
 			}
-			return "|" + st.red.Render(label)
+			return styled(label, st.red)
 		}
 		// unanswered: blink — red if this note was previously missed
-		var inner string
-		if blink == 0 {
-			inner = "|-(?)-"
-		} else {
-			inner = "|-(_)-"
+		inner := "-(?)-"
+		if blink != 0 {
+			inner = "-(_)-"
 		}
 		if note.WasMissed {
-			return st.red.Render(inner)
+			m := styled(inner, st.red)
+			m.fret = true
+			return m
 		}
-		return inner
+		return cellMark{inner: inner}
 	}
 
 	if note.Revealed {
 		// answered and moved on: colored fill, name hidden
 		if note.Correct {
-			return "|" + st.green.Render("-----")
+			return styled("-----", st.green)
 		}
-		return "|" + st.red.Render("-----")
+		return styled("-----", st.red)
 	}
 
 	if note.Solved {
-		if isCursor {
-			return "|" + st.cursor.Render("-----")
-		}
-		return "|" + st.green.Render("-----")
+		return cursorOr("-----", st.green)
 	}
 
 	if note.Marked {
-		inner := "--x--"
-		if isCursor {
-			return "|" + st.cursor.Render(inner)
-		}
-		return "|" + st.marked.Render(inner)
+		return cursorOr("--x--", st.marked)
 	}
 
 	if isCursor {
-		return "|" + st.cursor.Render("-----")
+		return styled("-----", st.cursor)
 	}
 
-	return "|-----"
+	return cellMark{inner: "-----"}
+}
+
+// renderCell draws a fret cell: the fret wire and the content, or when
+// mirrored the content and then the wire.
+func renderCell(note Note, blink int, isCursor bool, hideIntervals bool, compact, mirrored bool, st renderStyles) string {
+	m := noteMark(note, blink, isCursor, hideIntervals, st)
+	inner, wire := m.inner, func(s string) string { return "|" + s }
+	if compact {
+		inner = compactCell(inner)
+	}
+	if mirrored {
+		inner, wire = mirrorCell(inner), func(s string) string { return s + "|" }
+	}
+	switch {
+	case m.style == nil:
+		return wire(inner)
+	case m.fret:
+		return m.style.Render(wire(inner))
+	}
+	return wire(m.style.Render(inner))
+}
+
+// compactCell shortens a cell's content to 3 characters: "--C--" → "-C-",
+// "C#/Db" → "C#-".
+func compactCell(inner string) string {
+	label := strings.Split(strings.Trim(inner, "-"), "/")[0]
+	if len([]rune(label)) >= 3 {
+		return label
+	}
+	left := (3 - len([]rune(label))) / 2
+	return strings.Repeat("-", left) + label + strings.Repeat("-", 3-len([]rune(label))-left)
+}
+
+// mirrorCell swaps the dashes around the label of a cell's content, so
+// "-b3--" reads "--b3-"; the label itself stays readable.
+func mirrorCell(inner string) string {
+	label := strings.Trim(inner, "-")
+	if label == "" {
+		return inner
+	}
+	left := strings.Index(inner, label)
+	right := len(inner) - left - len(label)
+	return strings.Repeat("-", right) + label + strings.Repeat("-", left)
 }
 
 func noteCellLabel(name string) string {
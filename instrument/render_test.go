@@ -0,0 +1,295 @@
+package instrument
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+)
+
+// colourOpts renders with colours even when the tests have no terminal.
+func colourOpts(output FretboardRenderer) RenderOpts {
+	r := lipgloss.NewRenderer(io.Discard)
+	r.SetColorProfile(termenv.TrueColor)
+	return RenderOpts{Output: output, Renderer: r}
+}
+
+// solvedC marks the C on string 2, fret 1 as found.
+func solvedC(t *testing.T) *Instrument {
+	t.Helper()
+	g, err := NewGuitar(DefaultGuitarTuning(6), 12)
+	if err != nil {
+		t.Fatal(err)
+	}
+	g.Strings[1].Notes[1].ShowName = true
+	g.Strings[3].Notes[2].Interval = "b3"
+	return g
+}
+
+func TestRenderDefaultsToASCII(t *testing.T) {
+	g := solvedC(t)
+	got := Render(g, colourOpts(nil))
+	if want := ASCIIRenderer.Render(g, colourOpts(nil)); got != want {
+		t.Errorf("Render without Output differs from ASCIIRenderer:\n%s\n%s", got, want)
+	}
+	if !strings.Contains(got, "\x1b[") {
+		t.Error("ASCII: no colours")
+	}
+	if !strings.Contains(got, "\nD |-----|-b3--|") {
+		t.Errorf("ASCII: unexpected D string:\n%s", got)
+	}
+}
+
+func TestPlainRenderer(t *testing.T) {
+	got := Render(solvedC(t), colourOpts(PlainRenderer))
+	if strings.Contains(got, "\x1b") {
+		t.Errorf("plain: escape codes in output: %q", got)
+	}
+	if !strings.Contains(got, "\nB |--C--|-----|") {
+		t.Errorf("plain: unexpected B string:\n%s", got)
+	}
+}
+
+func TestUnicodeRenderer(t *testing.T) {
+	got := Render(solvedC(t), colourOpts(UnicodeRenderer))
+	if !strings.Contains(got, "\nD ║─────│─b3──│─────│") {
+		t.Errorf("unicode: unexpected D string:\n%s", got)
+	}
+	if !strings.Contains(got, "tuning: E-A-D-G-B-E") {
+		t.Errorf("unicode: header changed:\n%s", got)
+	}
+	if strings.ContainsAny(got[strings.Index(got, "\n"):], "|-") {
+		t.Errorf("unicode: ASCII cells left:\n%s", got)
+	}
+
+	// Labels are kept as they are, and a fret set at the nut keeps it.
+	g := solvedC(t)
+	g.Strings[0].Notes[0].Name = "-|"
+	got = Render(g, RenderOpts{Output: UnicodeRenderer, FretSetMode: true, FretSetStart: 1, FretSetEnd: 2})
+	if !strings.Contains(got, "\n-|  ║─────│─────│  ─────│") {
+		t.Errorf("unicode fret set: unexpected first string:\n%s", got)
+	}
+	got = Render(g, RenderOpts{Output: UnicodeRenderer, LeftHanded: true, FretSetMode: true, FretSetStart: 1, FretSetEnd: 2})
+	if !strings.Contains(got, "─────  │─────│─────║  -|\n") {
+		t.Errorf("unicode left-handed fret set: unexpected first string:\n%s", got)
+	}
+}
+
+func TestSVGRenderer(t *testing.T) {
+	got := Render(solvedC(t), RenderOpts{Output: SVGRenderer})
+	if !strings.HasPrefix(got, "<svg ") {
+		t.Fatalf("SVG: got %.40q", got)
+	}
+	texts := svgTexts(t, []byte(got))
+	if texts[0] != "guitar | tuning: E-A-D-G-B-E | frets: 12" {
+		t.Errorf("SVG: title = %q", texts[0])
+	}
+
+	hidden := Render(solvedC(t), RenderOpts{Output: SVGRenderer, HideIntervals: true})
+	if strings.Contains(hidden, ">b3<") {
+		t.Error("SVG with HideIntervals: interval label shown")
+	}
+
+	// The fret window, the fret set and the cursor carry over.
+	opts := RenderOpts{Output: SVGRenderer, FromFret: 1, ToFret: 4}
+	if got, want := Render(solvedC(t), opts), string(RenderSVG(solvedC(t), DiagramOpts{
+		Title: renderHeader(solvedC(t)), ToFret: 4,
+	})); got != want {
+		t.Errorf("SVG window: got\n%s\nwant\n%s", got, want)
+	}
+	opts.FretSetMode, opts.FretSetStart, opts.FretSetEnd = true, 2, 3
+	opts.CursorString, opts.CursorFret = 1, 2
+	got = Render(solvedC(t), opts)
+	if !strings.Contains(got, svgColor(diagramSet)) {
+		t.Error("SVG: fret set not drawn")
+	}
+	if !strings.Contains(got, svgColor(diagramCursor)) {
+		t.Error("SVG: cursor not drawn")
+	}
+	opts.FretSetMode = false
+	if got := Render(solvedC(t), opts); strings.Contains(got, svgColor(diagramSet)) || strings.Contains(got, svgColor(diagramCursor)) {
+		t.Error("SVG without FretSetMode: fret set or cursor drawn")
+	}
+}
+
+func TestVerticalRenderer(t *testing.T) {
+	g := solvedC(t)
+	g.Strings[5].Notes[0].Muted = true
+	opts := RenderOpts{Output: PlainRenderer, Vertical: true, ChordMode: true}
+	got := Render(g, opts)
+	lines := strings.Split(got, "\n")
+	want := []string{
+		"guitar | tuning: E-A-D-G-B-E | frets: 12",
+		"     x    A    D    G    B    E  ", // lowest string on the left
+		"   ==============================",
+		" 1   |    |    |    |    C    |  ",
+		"   --+----+----+----+----+----+--",
+		"     |    |   b3    |    |    |  ",
+	}
+	for i, w := range want {
+		if lines[i] != w {
+			t.Errorf("line %d = %q, want %q", i, lines[i], w)
+		}
+	}
+	// one row and one fret wire per fret
+	if n := len(lines) - 1; n != 3+2*12 {
+		t.Errorf("%d lines, want %d", n, 3+2*12)
+	}
+
+	opts.Output = UnicodeRenderer
+	if got := Render(g, opts); !strings.Contains(got, "\n   ──┼────┼──") || strings.Contains(got, "=") {
+		t.Errorf("unicode vertical:\n%s", got)
+	}
+}
+
+func TestWidth(t *testing.T) {
+	g, err := NewGuitar(DefaultGuitarTuning(6), 24)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := Width(g, RenderOpts{}), 2+24*6+1; got != want {
+		t.Errorf("horizontal width = %d, want %d", got, want)
+	}
+	// The vertical board is narrower than its header line.
+	if got, want := Width(g, RenderOpts{Vertical: true}), len(renderHeader(g)); got != want {
+		t.Errorf("vertical width = %d, want %d", got, want)
+	}
+}
+
+func TestLeftHandedRenderer(t *testing.T) {
+	got := Render(solvedC(t), RenderOpts{Output: PlainRenderer, LeftHanded: true})
+	lines := strings.Split(got, "\n")
+	// The nut and the open strings are on the right, fret 1 next to them.
+	if !strings.HasSuffix(lines[3], "|-----|--C--| B") {
+		t.Errorf("B string = %q", lines[3])
+	}
+	if !strings.HasSuffix(lines[5], "|--b3-|-----| D") {
+		t.Errorf("D string = %q", lines[5])
+	}
+	if !strings.HasPrefix(lines[1], "   12  ") || !strings.HasSuffix(lines[1], "  1     ") {
+		t.Errorf("markers = %q", lines[1])
+	}
+	// Every row still lines up with the markers.
+	if w, wm := len(lines[2]), len(lines[1]); w != wm {
+		t.Errorf("row width %d, markers %d", w, wm)
+	}
+
+	uni := Render(solvedC(t), RenderOpts{Output: UnicodeRenderer, LeftHanded: true})
+	if !strings.Contains(uni, "│─────│──C──║ B\n") {
+		t.Errorf("unicode left-handed: nut not on the right:\n%s", uni)
+	}
+}
+
+func TestFlipStrings(t *testing.T) {
+	g := solvedC(t)
+	got := Render(g, RenderOpts{Output: PlainRenderer, FlipStrings: true})
+	var labels []string
+	for _, line := range strings.Split(got, "\n")[2:8] {
+		labels = append(labels, line[:1])
+	}
+	if got, want := strings.Join(labels, ""), "EADGBE"; got != want {
+		t.Errorf("string order top to bottom = %s, want %s", got, want)
+	}
+
+	// Vertical: both options reverse the columns, so together they cancel.
+	v := func(left, flip bool) string {
+		out := Render(g, RenderOpts{Output: PlainRenderer, Vertical: true, LeftHanded: left, FlipStrings: flip})
+		return strings.Fields(strings.Split(out, "\n")[1])[1]
+	}
+	if v(false, false) != "A" || v(true, false) != "B" || v(false, true) != "B" || v(true, true) != "A" {
+		t.Errorf("vertical column 2: %s %s %s %s", v(false, false), v(true, false), v(false, true), v(true, true))
+	}
+}
+
+func TestCompactRenderer(t *testing.T) {
+	g := solvedC(t)
+	g.Strings[2].Notes[6].ShowName = true // C#/Db
+	lines := strings.Split(Render(g, RenderOpts{Output: PlainRenderer, Compact: true}), "\n")
+	if !strings.HasPrefix(lines[3], "B |-C-|---|") {
+		t.Errorf("B string = %q", lines[3])
+	}
+	if !strings.HasPrefix(lines[4], "G |---|---|---|---|---|C#-|") {
+		t.Errorf("G string = %q", lines[4])
+	}
+	if !strings.HasPrefix(lines[5], "D |---|b3-|") {
+		t.Errorf("D string = %q", lines[5])
+	}
+	if w := len(lines[2]); w != 2+12*4+1 {
+		t.Errorf("row width %d, want %d", w, 2+12*4+1)
+	}
+	if got, want := strings.TrimRight(lines[1], " "), "    1       3       5       7       9          12"; got != want {
+		t.Errorf("markers = %q, want %q", got, want)
+	}
+
+	// Vertical: 3-character columns and no fret wires.
+	v := RenderOpts{Output: PlainRenderer, Vertical: true, Compact: true}
+	if got, want := Height(g, v), 3+12; got != want {
+		t.Errorf("compact vertical height = %d, want %d", got, want)
+	}
+	if got := strings.Split(Render(g, v), "\n")[3]; got != " 1  |  |  |  |  C  | " {
+		t.Errorf("compact vertical fret 1 = %q", got)
+	}
+}
+
+func TestFretWindow(t *testing.T) {
+	g := solvedC(t)
+	g.Strings[0].Notes[7].ShowName = true // B
+	lines := strings.Split(Render(g, RenderOpts{Output: PlainRenderer, FromFret: 5, ToFret: 8}), "\n")
+	want := []string{
+		"guitar | tuning: E-A-D-G-B-E | frets: 12 | showing: 5-8",
+		"     5           7        ",
+		"E |-----|-----|--B--|-----|",
+	}
+	for i, w := range want {
+		if lines[i] != w {
+			t.Errorf("line %d = %q, want %q", i, lines[i], w)
+		}
+	}
+
+	// Away from the nut, the Unicode board draws no nut and the vertical
+	// one starts with a fret wire.
+	if uni := Render(g, RenderOpts{Output: UnicodeRenderer, FromFret: 5, ToFret: 8}); strings.Contains(uni, "║") {
+		t.Errorf("unicode window: nut drawn:\n%s", uni)
+	}
+	v := strings.Split(Render(g, RenderOpts{Output: PlainRenderer, Vertical: true, FromFret: 5, ToFret: 8}), "\n")
+	if v[2] != "   --+----+----+----+----+----+--" || v[3][:3] != " 5 " {
+		t.Errorf("vertical window:\n%s", strings.Join(v, "\n"))
+	}
+	if n := len(v) - 1; n != 3+2*4 {
+		t.Errorf("vertical window: %d lines, want %d", n, 3+2*4)
+	}
+}
+
+func TestFit(t *testing.T) {
+	g, err := NewGuitar(DefaultGuitarTuning(6), 24)
+	if err != nil {
+		t.Fatal(err)
+	}
+	g.Strings[2].Notes[20].ToBeDetermined = true
+	for _, tt := range []struct {
+		name          string
+		opts          RenderOpts
+		width, height int
+		from, to      int
+		whole         bool
+	}{
+		{"no limit", RenderOpts{}, 0, 0, 1, 24, true},
+		{"wide enough", RenderOpts{}, 2 + 24*6 + 1, 0, 1, 24, true},
+		// 10 frets of 6 columns; the window ends at the last fret
+		{"narrow, around the question", RenderOpts{}, 2 + 10*6 + 1, 0, 15, 24, false},
+		// the cursor and the question do not fit together: follow the cursor
+		{"around the cursor", RenderOpts{ShowCursor: true, CursorFret: 3, Compact: true}, 2 + 5*4 + 1, 0, 1, 5, false},
+		// 5 more columns for the gaps around the fret set
+		{"fret set", RenderOpts{FretSetMode: true, FretSetStart: 9, FretSetEnd: 11, CursorFret: 10}, 2 + 5*6 + 1 + 5, 0, 8, 12, false},
+		// header, open strings, nut and 2 lines per fret
+		{"short", RenderOpts{Vertical: true}, 0, 3 + 2*4, 19, 22, false},
+		{"too small", RenderOpts{}, 5, 0, 20, 20, false},
+	} {
+		got, whole := Fit(g, tt.opts, tt.width, tt.height)
+		if got.FromFret != tt.from || got.ToFret != tt.to || whole != tt.whole {
+			t.Errorf("%s: window %d-%d (whole %v), want %d-%d (%v)", tt.name, got.FromFret, got.ToFret, whole, tt.from, tt.to, tt.whole)
+		}
+	}
+}
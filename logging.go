@@ -0,0 +1,204 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/charmbracelet/ssh"
+	"github.com/charmbracelet/wish"
+	gossh "golang.org/x/crypto/ssh"
+)
+
+// ── logging ───────────────────────────────────────────────────────────────────
+//
+// The servers log through slog: one line per HTTP request and per SSH
+// session, in JSON by default. Plain log.Printf calls end up in the same
+// handler. Successful asset and sample requests are logged at debug level,
+// so the busiest routes cost a single level check unless --log-level debug
+// asks for them.
+
+// setupLogging installs the default slog logger for the server modes.
+func setupLogging(format, level string) error {
+	var lvl slog.Level
+	if err := lvl.UnmarshalText([]byte(level)); err != nil {
+		return fmt.Errorf("invalid log level %q (use debug, info, warn or error)", level)
+	}
+	opts := &slog.HandlerOptions{Level: lvl}
+	var h slog.Handler
+	switch format {
+	case "json":
+		h = slog.NewJSONHandler(os.Stderr, opts)
+	case "text":
+		h = slog.NewTextHandler(os.Stderr, opts)
+	default:
+		return fmt.Errorf("invalid log format %q (use json or text)", format)
+	}
+	slog.SetDefault(slog.New(h))
+	return nil
+}
+
+// requestIDHeader carries the request ID in both directions. Behind a proxy
+// an incoming ID is kept, so proxy and server logs can be joined.
+const requestIDHeader = "X-Request-ID"
+
+var requestIDPattern = regexp.MustCompile(`^[A-Za-z0-9._-]{1,64}$`)
+
+// Request IDs are a random per-process prefix plus a counter: unique enough
+// to find a request in the logs, and cheaper than reading crypto/rand for
+// every sample file.
+var (
+	requestIDPrefix = func() string {
+		b := make([]byte, 4)
+		_, _ = rand.Read(b)
+		return hex.EncodeToString(b)
+	}()
+	requestIDCounter atomic.Uint64
+)
+
+func newRequestID() string {
+	return requestIDPrefix + "-" + strconv.FormatUint(requestIDCounter.Add(1), 36)
+}
+
+// quietRoutes are the static asset patterns whose successful requests are
+// only logged at debug level.
+var quietRoutes = map[string]bool{
+	"/assets/sounds/strat/":   true,
+	"/assets/sounds/bass/":    true,
+	"/assets/sounds/ukulele/": true,
+	"/styles.css":             true,
+	"/lib.js":                 true,
+	"/terminal.js":            true,
+	"/favicon.ico":            true,
+	"/static/":                true,
+	"/sw.js":                  true,
+	"/manifest.webmanifest":   true,
+	"/icon-192.png":           true,
+	"/icon-512.png":           true,
+}
+
+// accessLog assigns every request an ID and logs it once it is served.
+// proxied trusts the X-Forwarded-For and X-Request-ID headers, which only a
+// reverse proxy in front of the server can be relied on to set.
+func accessLog(next http.Handler, proxied bool) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		id := r.Header.Get(requestIDHeader)
+		if !proxied || !requestIDPattern.MatchString(id) {
+			id = newRequestID()
+		}
+		w.Header().Set(requestIDHeader, id)
+		client := clientIP(r, proxied)
+		r = r.WithContext(context.WithValue(r.Context(), clientKey{}, client))
+
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(rec, r)
+
+		level := slog.LevelInfo
+		switch {
+		case rec.status >= 500:
+			level = slog.LevelError
+		case rec.status < 400 && quietRoutes[r.Pattern]:
+			level = slog.LevelDebug
+		}
+		ctx := r.Context()
+		logger := slog.Default()
+		if !logger.Enabled(ctx, level) {
+			return
+		}
+		logger.LogAttrs(ctx, level, "http request",
+			slog.String("id", id),
+			slog.String("method", r.Method),
+			slog.String("path", r.URL.Path),
+			slog.Int("status", rec.status),
+			slog.Int64("bytes", rec.bytes),
+			slog.Duration("duration", time.Since(start)),
+			slog.String("client", client),
+			slog.String("user_agent", r.UserAgent()),
+		)
+	})
+}
+
+// clientKey is the request context key of the client address accessLog
+// determined.
+type clientKey struct{}
+
+// requestClient returns the client address of r as accessLog determined it,
+// or the connection's address for requests that did not pass through it.
+func requestClient(r *http.Request) string {
+	if client, ok := r.Context().Value(clientKey{}).(string); ok {
+		return client
+	}
+	return clientIP(r, false)
+}
+
+// clientIP returns the address of the client that sent r. Behind a proxy
+// that is the last X-Forwarded-For entry: the one the proxy appended itself,
+// whereas earlier entries come from the client and can be forged.
+func clientIP(r *http.Request, proxied bool) string {
+	if proxied {
+		xff := r.Header.Values("X-Forwarded-For")
+		if len(xff) > 0 {
+			last := xff[len(xff)-1]
+			if i := strings.LastIndexByte(last, ','); i >= 0 {
+				last = last[i+1:]
+			}
+			if ip := strings.TrimSpace(last); ip != "" {
+				return ip
+			}
+		}
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// sessionLogMiddleware logs the start and end of every SSH session with the
+// client's key fingerprint, the identity stats are stored under.
+func sessionLogMiddleware() wish.Middleware {
+	return func(next ssh.Handler) ssh.Handler {
+		return func(sess ssh.Session) {
+			start := time.Now()
+			fingerprint := "none"
+			if key := sess.PublicKey(); key != nil {
+				fingerprint = gossh.FingerprintSHA256(key)
+			}
+			logger := slog.Default().With(
+				slog.String("session", shortSessionID(sess.Context().SessionID())),
+				slog.String("user", sess.User()),
+				slog.String("fingerprint", fingerprint),
+			)
+			host, _, _ := net.SplitHostPort(sess.RemoteAddr().String())
+			logger.LogAttrs(context.Background(), slog.LevelInfo, "ssh connect",
+				slog.String("client", host),
+				slog.String("command", strings.Join(sess.Command(), " ")),
+			)
+			defer func() {
+				logger.LogAttrs(context.Background(), slog.LevelInfo, "ssh disconnect",
+					slog.Duration("duration", time.Since(start)),
+				)
+			}()
+			next(sess)
+		}
+	}
+}
+
+// shortSessionID shortens the hex SSH session ID for log lines.
+func shortSessionID(id string) string {
+	if len(id) > 12 {
+		return id[:12]
+	}
+	return id
+}
@@ -0,0 +1,184 @@
+package main
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/json"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/ssh"
+	gossh "golang.org/x/crypto/ssh"
+)
+
+// captureLogs sends the default logger's output to a buffer until the test
+// ends and returns a function that decodes the lines logged so far.
+func captureLogs(t *testing.T) func() []map[string]any {
+	t.Helper()
+	var buf bytes.Buffer
+	prev := slog.Default()
+	slog.SetDefault(slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})))
+	t.Cleanup(func() { slog.SetDefault(prev) })
+	return func() []map[string]any {
+		var lines []map[string]any
+		for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+			var entry map[string]any
+			if err := json.Unmarshal([]byte(line), &entry); err != nil {
+				t.Fatalf("log line %q: %v", line, err)
+			}
+			lines = append(lines, entry)
+		}
+		return lines
+	}
+}
+
+func TestClientIP(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		remote  string
+		xff     []string
+		proxied bool
+		want    string
+	}{
+		{"direct", "192.0.2.1:5000", nil, false, "192.0.2.1"},
+		{"direct IPv6", "[2001:db8::1]:5000", nil, false, "2001:db8::1"},
+		{"no port", "192.0.2.1", nil, false, "192.0.2.1"},
+		{"forwarded header not trusted", "192.0.2.1:5000", []string{"203.0.113.9"}, false, "192.0.2.1"},
+		{"proxied", "10.0.0.1:5000", []string{"203.0.113.9"}, true, "203.0.113.9"},
+		{"proxied keeps the proxy's entry", "10.0.0.1:5000", []string{"6.6.6.6, 203.0.113.9"}, true, "203.0.113.9"},
+		{"proxied, last of several headers", "10.0.0.1:5000", []string{"6.6.6.6", " 203.0.113.9 "}, true, "203.0.113.9"},
+		{"proxied without the header", "10.0.0.1:5000", nil, true, "10.0.0.1"},
+		{"proxied, empty entry", "10.0.0.1:5000", []string{"6.6.6.6, "}, true, "10.0.0.1"},
+	} {
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r.RemoteAddr = tc.remote
+		for _, v := range tc.xff {
+			r.Header.Add("X-Forwarded-For", v)
+		}
+		if got := clientIP(r, tc.proxied); got != tc.want {
+			t.Errorf("%s: clientIP = %q, want %q", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestRequestID(t *testing.T) {
+	logs := captureLogs(t)
+	var client string
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { client = requestClient(r) })
+	for _, tc := range []struct {
+		name    string
+		id      string
+		proxied bool
+		keep    bool
+	}{
+		{"proxied, valid", "req-42.a_B", true, true},
+		{"proxied, longest", strings.Repeat("a", 64), true, true},
+		{"proxied, too long", strings.Repeat("a", 65), true, false},
+		{"proxied, bad characters", "id with\nnewline", true, false},
+		{"proxied, missing", "", true, false},
+		{"direct, valid", "req-42", false, false},
+	} {
+		r := httptest.NewRequest(http.MethodGet, "/api/x", nil)
+		r.RemoteAddr = "10.0.0.1:5000"
+		r.Header.Set("X-Forwarded-For", "203.0.113.9")
+		if tc.id != "" {
+			r.Header.Set(requestIDHeader, tc.id)
+		}
+		w := httptest.NewRecorder()
+		accessLog(h, tc.proxied).ServeHTTP(w, r)
+
+		got := w.Header().Get(requestIDHeader)
+		if tc.keep && got != tc.id {
+			t.Errorf("%s: ID = %q, want %q passed through", tc.name, got, tc.id)
+		}
+		if !tc.keep && (got == tc.id || !strings.HasPrefix(got, requestIDPrefix+"-")) {
+			t.Errorf("%s: ID = %q, want a generated one", tc.name, got)
+		}
+		line := logs()[len(logs())-1]
+		if line["msg"] != "http request" || line["id"] != got || line["client"] != client {
+			t.Errorf("%s: log line %v, want ID %q and client %q", tc.name, line, got, client)
+		}
+		if want := map[bool]string{true: "203.0.113.9", false: "10.0.0.1"}[tc.proxied]; client != want {
+			t.Errorf("%s: client = %q, want %q", tc.name, client, want)
+		}
+	}
+	if a, b := newRequestID(), newRequestID(); a == b {
+		t.Errorf("generated IDs repeat: %q", a)
+	}
+}
+
+// fakeSession is just enough of an SSH session for sessionLogMiddleware.
+type fakeSession struct {
+	ssh.Session
+	ctx     fakeContext
+	user    string
+	key     ssh.PublicKey
+	remote  net.Addr
+	command []string
+}
+
+func (s fakeSession) Context() ssh.Context     { return s.ctx }
+func (s fakeSession) User() string             { return s.user }
+func (s fakeSession) PublicKey() ssh.PublicKey { return s.key }
+func (s fakeSession) RemoteAddr() net.Addr     { return s.remote }
+func (s fakeSession) Command() []string        { return s.command }
+
+type fakeContext struct {
+	ssh.Context
+	id string
+}
+
+func (c fakeContext) SessionID() string { return c.id }
+
+func TestSessionLogLines(t *testing.T) {
+	logs := captureLogs(t)
+	pub, _, _ := ed25519.GenerateKey(nil)
+	key, err := gossh.NewPublicKey(pub)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sess := fakeSession{
+		ctx:     fakeContext{id: "0123456789abcdef0123"},
+		user:    "alice",
+		key:     key,
+		remote:  &net.TCPAddr{IP: net.ParseIP("192.0.2.7"), Port: 40000},
+		command: []string{"stats", "--json"},
+	}
+	ran := false
+	sessionLogMiddleware()(func(ssh.Session) { ran = true })(sess)
+	if !ran {
+		t.Fatal("the next handler did not run")
+	}
+
+	lines := logs()
+	if len(lines) != 2 {
+		t.Fatalf("got %d log lines, want connect and disconnect: %v", len(lines), lines)
+	}
+	connect, disconnect := lines[0], lines[1]
+	for field, want := range map[string]any{
+		"msg":         "ssh connect",
+		"session":     "0123456789ab",
+		"user":        "alice",
+		"fingerprint": gossh.FingerprintSHA256(key),
+		"client":      "192.0.2.7",
+		"command":     "stats --json",
+	} {
+		if connect[field] != want {
+			t.Errorf("connect %s = %v, want %v", field, connect[field], want)
+		}
+	}
+	if disconnect["msg"] != "ssh disconnect" || disconnect["session"] != "0123456789ab" || disconnect["duration"] == nil {
+		t.Errorf("disconnect line: %v", disconnect)
+	}
+
+	// Sessions that did not authenticate with a key log "none".
+	sess.key = nil
+	sessionLogMiddleware()(func(ssh.Session) {})(sess)
+	if got := logs()[2]["fingerprint"]; got != "none" {
+		t.Errorf("fingerprint without a key = %v, want none", got)
+	}
+}
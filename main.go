@@ -2,7 +2,9 @@ package main
 
 import (
 	"flag"
+	"io"
 	"log"
+	"os"
 
 	tea "github.com/charmbracelet/bubbletea"
 )
@@ -12,17 +14,54 @@ func main() {
 	flagServeHTTP := flag.Bool("serve-http", false, "run as HTTP/HTTPS game server")
 	flagDomain := flag.String("domain", "fremorizer.com", "domain for TLS certificate (used with --serve-http standalone)")
 	flagAddr := flag.String("addr", "", "listen address for HTTP when behind a reverse proxy, e.g. 127.0.0.1:3000 (disables built-in TLS)")
+	flagMetricsAddr := flag.String("metrics-addr", "", "extra listen address for /healthz, /readyz and /metrics, e.g. 127.0.0.1:9100 (required to scrape --serve-ssh)")
+	flagSamples := sampleDirs{}
+	flag.Var(flagSamples, "samples", "`[INSTRUMENT=]DIR` with <layer>-<midi><L|R>.wav samples to serve instead of the embedded bank; repeatable, INSTRUMENT defaults to guitar (used with --serve-http)")
+	flagLogFormat := flag.String("log-format", "json", "server log format: json or text")
+	flagLogLevel := flag.String("log-level", "info", "server log level: debug (includes asset requests), info, warn or error")
+	flagServer := flag.String("server", "", "fremorizer server `URL` to sync the local TUI's results with, e.g. https://fremorizer.com")
+	flagToken := flag.String("token", os.Getenv("FREMORIZER_TOKEN"), "token from `ssh -p 2222 <host> token` for --server (default $FREMORIZER_TOKEN)")
 	flag.Parse()
 
+	if *flagServeSSH || *flagServeHTTP {
+		if err := setupLogging(*flagLogFormat, *flagLogLevel); err != nil {
+			log.Fatal(err)
+		}
+	}
+
 	switch {
 	case *flagServeSSH:
-		serveSSH()
+		serveSSH(*flagMetricsAddr)
 	case *flagServeHTTP:
-		serveHTTP(*flagDomain, *flagAddr)
+		serveHTTP(*flagDomain, *flagAddr, *flagMetricsAddr, flagSamples)
 	default:
-		p := tea.NewProgram(initialModel(nil), tea.WithAltScreen())
-		if _, err := p.Run(); err != nil {
+		m := initialModel(nil)
+		m.stats, m.player, m.playerName = openLocalStats(), localPlayer, localPlayerName()
+		var remote *remoteStats
+		if *flagServer != "" {
+			var err error
+			if remote, err = newRemoteStats(*flagServer, *flagToken); err != nil {
+				log.Fatal(err)
+			}
+			if m.stats == nil {
+				log.Fatal("--server: no config directory for the local stats")
+			}
+			if err := remote.sync(m.stats, m.player); err != nil {
+				log.Printf("sync with %s: %v", *flagServer, err)
+			}
+		}
+		p := tea.NewProgram(m, tea.WithAltScreen())
+		log.SetOutput(io.Discard) // stderr would be drawn over the game's alt screen
+		_, err := p.Run()
+		log.SetOutput(os.Stderr)
+		if err != nil {
 			log.Fatal(err)
 		}
+		// Upload the results of this run; a failure is caught up next time.
+		if remote != nil {
+			if err := remote.sync(m.stats, m.player); err != nil {
+				log.Printf("sync with %s: %v", *flagServer, err)
+			}
+		}
 	}
 }
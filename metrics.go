@@ -0,0 +1,299 @@
+package main
+
+import (
+	"bufio"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/charmbracelet/ssh"
+	"github.com/charmbracelet/wish"
+	"github.com/funkymcb/fremorizer/stats"
+)
+
+// ── observability ─────────────────────────────────────────────────────────────
+//
+// /healthz answers as long as the process serves requests, /readyz only while
+// it accepts traffic (and the stats directory is writable), and /metrics
+// exposes the counters below in the Prometheus text format. All three are
+// only served on the --metrics-addr listener, never on the public port.
+
+// metrics holds the process-wide counters. The zero value is ready to use.
+var metrics = &serverMetrics{}
+
+// ready reports whether the server is accepting traffic. It is set once the
+// listeners are up and cleared when shutdown begins.
+var ready atomic.Bool
+
+type serverMetrics struct {
+	mu           sync.Mutex
+	httpRequests map[[2]string]uint64 // {route, status}
+	gamesStarted map[string]uint64    // mode
+	gamesDone    map[string]uint64    // mode
+	certExpiry   map[string]time.Time // domain
+
+	sampleBytes atomic.Uint64
+	sshActive   atomic.Int64
+	sshTotal    atomic.Uint64
+}
+
+func (m *serverMetrics) httpRequest(route string, status int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.httpRequests == nil {
+		m.httpRequests = map[[2]string]uint64{}
+	}
+	m.httpRequests[[2]string{route, strconv.Itoa(status)}]++
+}
+
+func (m *serverMetrics) gameStarted(mode string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.gamesStarted == nil {
+		m.gamesStarted = map[string]uint64{}
+	}
+	m.gamesStarted[mode]++
+}
+
+func (m *serverMetrics) gameCompleted(mode string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.gamesDone == nil {
+		m.gamesDone = map[string]uint64{}
+	}
+	m.gamesDone[mode]++
+}
+
+func (m *serverMetrics) setCertExpiry(domain string, notAfter time.Time) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.certExpiry == nil {
+		m.certExpiry = map[string]time.Time{}
+	}
+	m.certExpiry[domain] = notAfter
+}
+
+// writeTo writes all metrics in the Prometheus text exposition format.
+func (m *serverMetrics) writeTo(w *bufio.Writer) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	header := func(name, typ, help string) {
+		fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, typ)
+	}
+
+	header("fremorizer_http_requests_total", "counter", "HTTP requests by route pattern and status code.")
+	keys := make([][2]string, 0, len(m.httpRequests))
+	for k := range m.httpRequests {
+		keys = append(keys, k)
+	}
+	slices.SortFunc(keys, func(a, b [2]string) int {
+		return strings.Compare(a[0]+" "+a[1], b[0]+" "+b[1])
+	})
+	for _, k := range keys {
+		fmt.Fprintf(w, "fremorizer_http_requests_total{route=%s,status=%s} %d\n",
+			labelValue(k[0]), labelValue(k[1]), m.httpRequests[k])
+	}
+
+	header("fremorizer_sample_bytes_total", "counter", "Bytes of audio samples served.")
+	fmt.Fprintf(w, "fremorizer_sample_bytes_total %d\n", m.sampleBytes.Load())
+
+	header("fremorizer_ssh_sessions_active", "gauge", "Open SSH sessions.")
+	fmt.Fprintf(w, "fremorizer_ssh_sessions_active %d\n", m.sshActive.Load())
+	header("fremorizer_ssh_sessions_total", "counter", "SSH sessions opened.")
+	fmt.Fprintf(w, "fremorizer_ssh_sessions_total %d\n", m.sshTotal.Load())
+
+	writeByMode := func(name, help string, counts map[string]uint64) {
+		header(name, "counter", help)
+		for _, mode := range sortedKeys(counts) {
+			fmt.Fprintf(w, "%s{mode=%s} %d\n", name, labelValue(mode), counts[mode])
+		}
+	}
+	writeByMode("fremorizer_games_started_total", "Games started by mode.", m.gamesStarted)
+	writeByMode("fremorizer_games_completed_total", "Games completed by mode.", m.gamesDone)
+
+	header("fremorizer_tls_cert_expiry_timestamp_seconds", "gauge", "Expiry of the served TLS certificate as a Unix timestamp.")
+	for _, domain := range sortedKeys(m.certExpiry) {
+		fmt.Fprintf(w, "fremorizer_tls_cert_expiry_timestamp_seconds{domain=%s} %d\n",
+			labelValue(domain), m.certExpiry[domain].Unix())
+	}
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+// labelValue quotes s as a Prometheus label value.
+func labelValue(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+	return `"` + r.Replace(s) + `"`
+}
+
+// registerOps adds /healthz, /readyz and /metrics to mux. A non-nil store must
+// be writable for the server to report ready.
+func registerOps(mux *http.ServeMux, store *stats.Store) {
+	mux.HandleFunc("GET /healthz", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Cache-Control", "no-store")
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		fmt.Fprintln(w, "ok")
+	})
+	mux.HandleFunc("GET /readyz", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Cache-Control", "no-store")
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		if !ready.Load() {
+			http.Error(w, "not ready: not accepting traffic", http.StatusServiceUnavailable)
+			return
+		}
+		if store != nil {
+			if err := store.Check(); err != nil {
+				log.Printf("readiness: %v", err)
+				http.Error(w, "not ready: stats storage unavailable", http.StatusServiceUnavailable)
+				return
+			}
+		}
+		fmt.Fprintln(w, "ok")
+	})
+	mux.HandleFunc("GET /metrics", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Cache-Control", "no-store")
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		bw := bufio.NewWriter(w)
+		metrics.writeTo(bw)
+		_ = bw.Flush()
+	})
+}
+
+// serveOps runs a plain HTTP listener with only the ops endpoints on addr and
+// returns its server for shutdown. An empty addr disables it and returns nil.
+func serveOps(addr string, store *stats.Store) *http.Server {
+	if addr == "" {
+		return nil
+	}
+	mux := http.NewServeMux()
+	registerOps(mux, store)
+	srv := &http.Server{
+		Handler:           mux,
+		ReadHeaderTimeout: 5 * time.Second,
+		ReadTimeout:       15 * time.Second,
+		WriteTimeout:      15 * time.Second,
+	}
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		log.Fatal("metrics listener:", err)
+	}
+	log.Printf("Metrics listening on %s", addr)
+	go func() {
+		if err := srv.Serve(ln); err != http.ErrServerClosed {
+			log.Printf("metrics server error: %v", err)
+		}
+	}()
+	return srv
+}
+
+// instrumentHTTP counts every request by the mux pattern that served it, so
+// the route label stays bounded whatever paths clients request.
+func instrumentHTTP(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(rec, r)
+		route := r.Pattern
+		if route == "" {
+			route = "unmatched"
+		}
+		metrics.httpRequest(route, rec.status)
+	})
+}
+
+// statusRecorder remembers the status code and body size written through it.
+type statusRecorder struct {
+	http.ResponseWriter
+	status      int
+	bytes       int64
+	wroteHeader bool
+}
+
+func (s *statusRecorder) WriteHeader(code int) {
+	if !s.wroteHeader {
+		s.status, s.wroteHeader = code, true
+	}
+	s.ResponseWriter.WriteHeader(code)
+}
+
+func (s *statusRecorder) Write(b []byte) (int, error) {
+	s.wroteHeader = true
+	n, err := s.ResponseWriter.Write(b)
+	s.bytes += int64(n)
+	return n, err
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer.
+func (s *statusRecorder) Unwrap() http.ResponseWriter { return s.ResponseWriter }
+
+// Hijack supports the WebSocket upgrade of the browser terminal, which
+// type-asserts http.Hijacker directly. Hijacked connections count as 101.
+func (s *statusRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	h, ok := s.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, fmt.Errorf("response writer does not support hijacking")
+	}
+	s.status, s.wroteHeader = http.StatusSwitchingProtocols, true
+	return h.Hijack()
+}
+
+// trackCertExpiry wraps cfg's certificate lookup to record when the served
+// certificate expires.
+func trackCertExpiry(cfg *tls.Config, domain string) {
+	get := cfg.GetCertificate
+	cfg.GetCertificate = func(hello *tls.ClientHelloInfo) (*tls.Certificate, error) {
+		cert, err := get(hello)
+		if err != nil || cert == nil {
+			return cert, err
+		}
+		leaf := cert.Leaf
+		if leaf == nil && len(cert.Certificate) > 0 {
+			leaf, _ = x509.ParseCertificate(cert.Certificate[0])
+		}
+		if leaf != nil {
+			metrics.setCertExpiry(domain, leaf.NotAfter)
+		}
+		return cert, nil
+	}
+}
+
+// loadCertExpiry looks up the certificate once, so the expiry gauge is set
+// before the first client connects. The hello asks for the ECDSA certificate
+// that current clients get; autocert loads it from its cache or obtains it.
+func loadCertExpiry(cfg *tls.Config, domain string) {
+	hello := &tls.ClientHelloInfo{
+		ServerName:   domain,
+		CipherSuites: []uint16{tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256},
+	}
+	if _, err := cfg.GetCertificate(hello); err != nil {
+		log.Printf("load certificate: %v", err)
+	}
+}
+
+// sessionMetricsMiddleware counts SSH sessions, interactive or not.
+func sessionMetricsMiddleware() wish.Middleware {
+	return func(next ssh.Handler) ssh.Handler {
+		return func(sess ssh.Session) {
+			metrics.sshTotal.Add(1)
+			metrics.sshActive.Add(1)
+			defer metrics.sshActive.Add(-1)
+			next(sess)
+		}
+	}
+}
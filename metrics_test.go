@@ -0,0 +1,80 @@
+package main
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestOpsOnlyOnOpsListener(t *testing.T) {
+	samples, err := openSampleBanks(sampleFiles, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ready.Store(true)
+	defer ready.Store(false)
+
+	public := pageHandler(nil, samples)
+	ops := http.NewServeMux()
+	registerOps(ops, nil)
+	for _, path := range []string{"/metrics", "/healthz", "/readyz"} {
+		rec := httptest.NewRecorder()
+		public.ServeHTTP(rec, httptest.NewRequest("GET", path, nil))
+		if ct := rec.Header().Get("Content-Type"); strings.HasPrefix(ct, "text/plain") {
+			t.Errorf("public %s served by the ops handler (%s)", path, ct)
+		}
+		rec = httptest.NewRecorder()
+		ops.ServeHTTP(rec, httptest.NewRequest("GET", path, nil))
+		if rec.Code != http.StatusOK {
+			t.Errorf("ops %s: status %d", path, rec.Code)
+		}
+	}
+}
+
+func TestQuizMetrics(t *testing.T) {
+	count := func(m map[string]uint64) uint64 {
+		metrics.mu.Lock()
+		defer metrics.mu.Unlock()
+		return m["quiz"]
+	}
+	started, done := count(metrics.gamesStarted), count(metrics.gamesDone)
+
+	in := strings.NewReader("C\nD\n")
+	if code := cmdQuiz([]string{"--count", "2"}, in, io.Discard, io.Discard, nil, ""); code != 0 {
+		t.Fatalf("quiz exited with %d", code)
+	}
+	in = strings.NewReader("C\n") // aborted after one of two questions
+	cmdQuiz([]string{"--count", "2"}, in, io.Discard, io.Discard, nil, "")
+
+	if got := count(metrics.gamesStarted) - started; got != 2 {
+		t.Errorf("quiz games started = %d, want 2", got)
+	}
+	if got := count(metrics.gamesDone) - done; got != 1 {
+		t.Errorf("quiz games completed = %d, want 1", got)
+	}
+}
+
+func TestLoadCertExpiry(t *testing.T) {
+	notAfter := time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC)
+	leaf := &x509.Certificate{NotAfter: notAfter}
+	cfg := &tls.Config{GetCertificate: func(hello *tls.ClientHelloInfo) (*tls.Certificate, error) {
+		if hello.ServerName != "example.test" {
+			t.Errorf("certificate for %q", hello.ServerName)
+		}
+		return &tls.Certificate{Leaf: leaf}, nil
+	}}
+	trackCertExpiry(cfg, "example.test")
+	loadCertExpiry(cfg, "example.test")
+
+	metrics.mu.Lock()
+	got := metrics.certExpiry["example.test"]
+	metrics.mu.Unlock()
+	if !got.Equal(notAfter) {
+		t.Errorf("cert expiry = %v, want %v before any handshake", got, notAfter)
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/charmbracelet/lipgloss"
 	"github.com/funkymcb/fremorizer/game"
 	"github.com/funkymcb/fremorizer/instrument"
+	"github.com/funkymcb/fremorizer/stats"
 )
 
 // ── styles ────────────────────────────────────────────────────────────────────
@@ -51,6 +52,8 @@ const (
 	stateOptions
 	stateOptionsTuning
 	statePlaying
+	stateRaceLobby
+	stateLeaderboard
 )
 
 type optItem int
@@ -64,6 +67,9 @@ const (
 	optItemChordDifficulty
 	optItemChordCount
 	optItemNoteListAccidentals
+	optItemFretboardLayout
+	optItemHandedness
+	optItemStringOrder
 	optItemBack
 	optItemCount
 )
@@ -102,6 +108,14 @@ type model struct {
 	chordDifficulty     string // "easy", "medium", "hard"
 	chordCount          int    // number of chords to find per session
 	noteListAccidentals string // "both", "sharps", "flats"
+	fretboardLayout     string // "auto", "horizontal", "vertical"
+	leftHanded          bool   // mirror the fretboard: the nut on the right
+	lowStringOnTop      bool   // player's view instead of tab view
+
+	// terminal size, from tea.WindowSizeMsg; 0 = unknown
+	width, height int
+	// the active game's fretboard layout, fitted to the terminal by fitLayout
+	layout fretboardFit
 
 	// active game
 	selectedMode  int
@@ -110,9 +124,87 @@ type model struct {
 	feedback      string
 	feedbackOK    bool
 	wrongGuesses  int
+	mistakes      int // wrong answers in the whole game, for accuracy
 	textInput     textinput.Model
 	revealed      bool
 	gameStartTime time.Time
+	gameOver      bool      // set when the active game was finished (not aborted)
+	daily         bool      // the active game is the daily challenge
+	dailyDate     time.Time // the day, in UTC, whose challenge is played
+	dailySlips    map[[2]int]bool
+
+	// persistence — nil stats or empty player disables recording
+	stats      *stats.Store
+	player     string
+	playerName string // shown on leaderboards
+
+	// leaderboard view, loaded by loadLeaderboard
+	lbMode     int  // index into stats.LeaderboardModes
+	lbTeamOnly bool // only show the player's team
+	lbTeam     string
+	lbEntries  []stats.Entry
+	lbErr      error
+
+	// the player's own instrument options while a race or the daily
+	// challenge plays on its own; restored when the game ends
+	savedInstrument *instrumentConfig
+
+	// multiplayer race — nil race means the mode is unavailable (local TUI)
+	race      *raceClient
+	raceBoard raceBoard
+	racing    bool // the active game is part of a race
+
+	// spectators — nil shares means the session cannot be shared (local TUI),
+	// nil share that the student has not chosen to
+	shares     *sessionRegistry
+	shareOwner string
+	share      *sharedSession
+}
+
+// instrumentConfig is the instrument part of the options.
+type instrumentConfig struct {
+	instrType         string
+	numStrings        int
+	tuning            []string
+	frets             int
+	fretSetSequential bool
+}
+
+// instrumentConfig returns the current instrument options.
+func (m model) instrumentConfig() instrumentConfig {
+	return instrumentConfig{
+		instrType:         m.instrType,
+		numStrings:        m.numStrings,
+		tuning:            append([]string{}, m.tuning...),
+		frets:             m.frets,
+		fretSetSequential: m.fretSetSequential,
+	}
+}
+
+// useInstrument plays the next game on c, keeping the player's own options
+// to be restored when it ends.
+func (m *model) useInstrument(c instrumentConfig) {
+	if m.savedInstrument == nil {
+		saved := m.instrumentConfig()
+		m.savedInstrument = &saved
+	}
+	m.setInstrument(c)
+}
+
+// restoreInstrument puts back the options saved by useInstrument, if any.
+func (m *model) restoreInstrument() {
+	if m.savedInstrument != nil {
+		m.setInstrument(*m.savedInstrument)
+		m.savedInstrument = nil
+	}
+}
+
+func (m *model) setInstrument(c instrumentConfig) {
+	m.instrType = c.instrType
+	m.numStrings = c.numStrings
+	m.tuning = append([]string{}, c.tuning...)
+	m.frets = c.frets
+	m.fretSetSequential = c.fretSetSequential
 }
 
 func initialModel(renderer *lipgloss.Renderer) model {
@@ -139,6 +231,7 @@ func initialModel(renderer *lipgloss.Renderer) model {
 		chordDifficulty:     "easy",
 		chordCount:          20,
 		noteListAccidentals: "both",
+		fretboardLayout:     "auto",
 		textInput:           ti,
 		tuneInput:           tuneInput,
 	}
@@ -151,5 +244,12 @@ func tick() tea.Cmd {
 }
 
 func (m model) Init() tea.Cmd {
-	return tea.Batch(tick(), textinput.Blink)
+	cmds := []tea.Cmd{tick(), textinput.Blink}
+	if m.race != nil {
+		cmds = append(cmds, waitForRace(m.race))
+	}
+	if m.share != nil {
+		cmds = append(cmds, waitForHint(m.share))
+	}
+	return tea.Batch(cmds...)
 }
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"image"
+	"image/color"
+	"image/png"
+)
+
+// ── installable web app ───────────────────────────────────────────────────────
+//
+// The page links a web app manifest and registers a service worker
+// (html/sw.js), so phones can install it and play offline. The worker caches
+// the page, its assets and every sample it plays, in caches named after
+// content hashes: a deploy replaces exactly the caches whose content changed.
+
+//go:embed html/manifest.webmanifest
+var webManifest []byte
+
+//go:embed html/sw.js
+var serviceWorkerJS []byte
+
+// appIconSizes are the PNG icons listed in the manifest, scaled from the
+// favicon.
+var appIconSizes = []int{192, 512}
+
+// serviceWorker returns the embedded worker with its cache versions and the
+// versioned asset URLs to precache filled in. The shell version covers the
+// page (whose asset URLs carry content hashes), the manifest and the icons;
+// the samples version covers every sample file.
+func serviceWorker(page *asset, samples *sampleBanks, assets []string) []byte {
+	shell := assetHash([]byte(page.hash + assetHash(webManifest) + assetHash(faviconBytes)))
+	list, _ := json.Marshal(assets)
+	js := serviceWorkerJS
+	js = bytes.Replace(js,
+		[]byte(`SHELL_VERSION = 'dev'`),
+		[]byte(`SHELL_VERSION = '`+shell+`'`), 1)
+	js = bytes.Replace(js,
+		[]byte(`SAMPLES_VERSION = 'dev'`),
+		[]byte(`SAMPLES_VERSION = '`+samples.version+`'`), 1)
+	js = bytes.Replace(js,
+		[]byte(`const ASSETS = [];`),
+		[]byte(`const ASSETS = `+string(list)+`;`), 1)
+	return js
+}
+
+// precacheURLs lists the page's scripts, stylesheets and fonts under the
+// URLs the page loads them from: every file of a build, or the versioned
+// sources.
+func precacheURLs(build *siteBuild, urls map[string]string) []string {
+	if build != nil {
+		return sortedKeys(build.files)
+	}
+	list := make([]string, 0, len(urls))
+	for _, name := range sortedKeys(urls) {
+		list = append(list, urls[name])
+	}
+	return list
+}
+
+// appIcon scales the largest image of an ICO file to a size×size PNG. The
+// favicon is pixel art, so it is scaled nearest-neighbour to stay crisp.
+func appIcon(ico []byte, size int) ([]byte, error) {
+	src, err := decodeICO(ico)
+	if err != nil {
+		return nil, err
+	}
+	b := src.Bounds()
+	dst := image.NewNRGBA(image.Rect(0, 0, size, size))
+	for y := range size {
+		for x := range size {
+			dst.Set(x, y, src.At(b.Min.X+x*b.Dx()/size, b.Min.Y+y*b.Dy()/size))
+		}
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, dst); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// decodeICO returns the largest image of an ICO file. Entries must be PNG or
+// 32-bit BMP, which is what current icon editors write.
+func decodeICO(data []byte) (image.Image, error) {
+	le := binary.LittleEndian
+	if len(data) < 6 || le.Uint16(data[2:]) != 1 {
+		return nil, errors.New("not an ICO file")
+	}
+	n := int(le.Uint16(data[4:]))
+	if len(data) < 6+16*n {
+		return nil, errors.New("truncated ICO directory")
+	}
+	best, bestWidth := -1, 0
+	for i := range n {
+		w := int(data[6+16*i])
+		if w == 0 {
+			w = 256
+		}
+		if w > bestWidth {
+			best, bestWidth = i, w
+		}
+	}
+	if best < 0 {
+		return nil, errors.New("ICO file has no images")
+	}
+	entry := data[6+16*best:]
+	size, off := le.Uint32(entry[8:]), le.Uint32(entry[12:])
+	if uint64(off)+uint64(size) > uint64(len(data)) {
+		return nil, errors.New("truncated ICO image")
+	}
+	img := data[off : off+size]
+	if bytes.HasPrefix(img, []byte("\x89PNG")) {
+		return png.Decode(bytes.NewReader(img))
+	}
+
+	// A BITMAPINFOHEADER whose height counts the colour rows and the AND
+	// mask below them. Rows are stored bottom-up as BGRA.
+	if len(img) < 40 {
+		return nil, errors.New("truncated ICO bitmap header")
+	}
+	hdrLen := le.Uint32(img)
+	w, h := int(int32(le.Uint32(img[4:]))), int(int32(le.Uint32(img[8:])))/2
+	if bpp := le.Uint16(img[14:]); bpp != 32 || w <= 0 || h <= 0 {
+		return nil, errors.New("unsupported ICO bitmap (need 32 bits per pixel)")
+	}
+	if uint64(len(img)) < uint64(hdrLen)+uint64(w*h*4) {
+		return nil, errors.New("truncated ICO bitmap")
+	}
+	px := img[hdrLen:]
+	out := image.NewNRGBA(image.Rect(0, 0, w, h))
+	for y := range h {
+		row := px[(h-1-y)*w*4:]
+		for x := range w {
+			p := row[x*4:]
+			out.SetNRGBA(x, y, color.NRGBA{R: p[2], G: p[1], B: p[0], A: p[3]})
+		}
+	}
+	return out, nil
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"image/png"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestServiceWorkerConstants(t *testing.T) {
+	// serviceWorker replaces these lines; renaming one in sw.js would leave
+	// the worker on its development caches.
+	for _, placeholder := range []string{`SHELL_VERSION = 'dev'`, `SAMPLES_VERSION = 'dev'`, `const ASSETS = [];`} {
+		if !bytes.Contains(serviceWorkerJS, []byte(placeholder)) {
+			t.Errorf("sw.js lacks %q", placeholder)
+		}
+	}
+
+	samples, err := openSampleBanks(sampleFiles, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rec := httptest.NewRecorder()
+	pageHandler(nil, samples).ServeHTTP(rec, httptest.NewRequest("GET", "/sw.js", nil))
+	sw := rec.Body.String()
+
+	if strings.Contains(sw, "'dev'") || strings.Contains(sw, "const ASSETS = [];") {
+		t.Error("served sw.js still has a placeholder")
+	}
+	if !strings.Contains(sw, `SAMPLES_VERSION = '`+samples.version+`'`) {
+		t.Error("served sw.js lacks the samples version")
+	}
+	if !regexp.MustCompile(`SHELL_VERSION = '[0-9a-f]{8}'`).MatchString(sw) {
+		t.Error("served sw.js lacks the shell version")
+	}
+	assets := regexp.MustCompile(`const ASSETS = (\[.*\]);`).FindStringSubmatch(sw)
+	if assets == nil || !strings.Contains(assets[1], `"lib.js?v=`) && !strings.Contains(assets[1], `"static/`) {
+		t.Errorf("served sw.js precaches %v, want the versioned page assets", assets)
+	}
+}
+
+func TestDecodeICO(t *testing.T) {
+	img, err := decodeICO(faviconBytes)
+	if err != nil {
+		t.Fatalf("favicon: %v", err)
+	}
+	if b := img.Bounds(); b.Dx() != 48 || b.Dy() != 48 {
+		t.Errorf("favicon decodes to %v, want its largest image, 48×48", b)
+	}
+	opaque := 0
+	for y := range 48 {
+		for x := range 48 {
+			if _, _, _, a := img.At(x, y).RGBA(); a > 0 {
+				opaque++
+			}
+		}
+	}
+	if opaque == 0 {
+		t.Error("favicon decodes to a transparent image")
+	}
+
+	for _, size := range appIconSizes {
+		data, err := appIcon(faviconBytes, size)
+		if err != nil {
+			t.Fatalf("appIcon(%d): %v", size, err)
+		}
+		icon, err := png.Decode(bytes.NewReader(data))
+		if err != nil || icon.Bounds().Dx() != size || icon.Bounds().Dy() != size {
+			t.Errorf("appIcon(%d) = %v, %v", size, icon.Bounds(), err)
+		}
+	}
+}
+
+// icoWithPNG builds an ICO file with one PNG image.
+func icoWithPNG(t *testing.T, size int) []byte {
+	src := image.NewNRGBA(image.Rect(0, 0, size, size))
+	src.SetNRGBA(1, 2, color.NRGBA{R: 200, A: 255})
+	var img bytes.Buffer
+	if err := png.Encode(&img, src); err != nil {
+		t.Fatal(err)
+	}
+	le := binary.LittleEndian
+	ico := le.AppendUint16(nil, 0)
+	ico = le.AppendUint16(ico, 1)
+	ico = le.AppendUint16(ico, 1)
+	ico = append(ico, byte(size), byte(size), 0, 0, 1, 0, 32, 0)
+	ico = le.AppendUint32(ico, uint32(img.Len()))
+	ico = le.AppendUint32(ico, 22)
+	return append(ico, img.Bytes()...)
+}
+
+func TestDecodeICOPNGAndErrors(t *testing.T) {
+	ico := icoWithPNG(t, 4)
+	img, err := decodeICO(ico)
+	if err != nil {
+		t.Fatalf("PNG entry: %v", err)
+	}
+	if r, _, _, _ := img.At(1, 2).RGBA(); r>>8 != 200 || img.Bounds().Dx() != 4 {
+		t.Errorf("PNG entry decodes to %v with %v at (1,2)", img.Bounds(), img.At(1, 2))
+	}
+
+	shortBitmap := append(bytes.Clone(ico[:22]), make([]byte, 10)...)
+	binary.LittleEndian.PutUint32(shortBitmap[14:], 10)
+
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{"empty", nil},
+		{"not an icon", []byte("GIF89a....")},
+		{"cursor", []byte{0, 0, 2, 0, 0, 0}},
+		{"truncated directory", ico[:10]},
+		{"no images", []byte{0, 0, 1, 0, 0, 0}},
+		{"truncated image", ico[:len(ico)-5]},
+		{"truncated bitmap header", shortBitmap},
+	}
+	for _, tt := range tests {
+		if _, err := decodeICO(tt.data); err == nil {
+			t.Errorf("%s: expected error", tt.name)
+		}
+	}
+}
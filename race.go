@@ -0,0 +1,261 @@
+package main
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// ── race hub ──────────────────────────────────────────────────────────────────
+//
+// The race hub connects the otherwise isolated SSH sessions. Every session
+// gets a raceClient; clients that enter the lobby take part in the next race.
+// All state changes are pushed to the clients as tea messages over a
+// per-client channel, which each model drains with waitForRace.
+
+// raceModes are the game modes that can be raced, in lobby toggle order.
+var raceModes = []string{"single", "fretset"}
+
+// raceConfig is the instrument setup of a race, taken from the player who
+// starts it so that every racer gets the same board.
+type raceConfig struct {
+	instrType  string
+	tuning     []string
+	frets      int
+	sequential bool
+}
+
+// raceEntry is one row of the scoreboard.
+type raceEntry struct {
+	id       int
+	name     string
+	correct  int
+	total    int
+	finished time.Duration // 0 while still racing
+	racing   bool          // takes part in the current race
+}
+
+// raceBoard is a snapshot of the lobby sent to every client on each change.
+type raceBoard struct {
+	mode    string
+	running bool
+	entries []raceEntry
+}
+
+type (
+	raceBoardMsg struct{ board raceBoard }
+	raceStartMsg struct {
+		seed int64
+		mode string
+		cfg  raceConfig
+	}
+)
+
+type raceHub struct {
+	mu      sync.Mutex
+	nextID  int
+	clients map[int]*raceClient
+	mode    string
+	running bool
+	start   time.Time
+}
+
+func newRaceHub() *raceHub {
+	return &raceHub{clients: map[int]*raceClient{}, mode: raceModes[0]}
+}
+
+// raceClient is one session's handle on the hub. Its fields are guarded by
+// the hub's mutex.
+type raceClient struct {
+	hub      *raceHub
+	id       int
+	name     string
+	ch       chan tea.Msg
+	done     chan struct{} // closed when the session ends
+	inLobby  bool
+	racing   bool
+	correct  int
+	total    int
+	finished time.Duration
+}
+
+// connect registers a session. The client stays idle until it joins the lobby.
+func (h *raceHub) connect(name string) *raceClient {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.nextID++
+	c := &raceClient{hub: h, id: h.nextID, name: name, ch: make(chan tea.Msg, 32), done: make(chan struct{})}
+	h.clients[c.id] = c
+	return c
+}
+
+// close removes the client for good, e.g. when its SSH session ends.
+func (c *raceClient) close() {
+	h := c.hub
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	delete(h.clients, c.id)
+	close(c.done)
+	h.checkRaceOver()
+	h.broadcast()
+}
+
+func (c *raceClient) join() {
+	h := c.hub
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	c.inLobby = true
+	h.broadcast()
+}
+
+func (c *raceClient) leave() {
+	h := c.hub
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	c.inLobby = false
+	c.racing = false
+	h.checkRaceOver()
+	h.broadcast()
+}
+
+// cycleMode switches the lobby to the next race mode. Ignored mid-race.
+func (c *raceClient) cycleMode() {
+	h := c.hub
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.running {
+		return
+	}
+	for i, m := range raceModes {
+		if m == h.mode {
+			h.mode = raceModes[(i+1)%len(raceModes)]
+			break
+		}
+	}
+	h.broadcast()
+}
+
+// start begins a race for everyone in the lobby with a shared seed. It
+// reports false if a race is already running.
+func (c *raceClient) start(cfg raceConfig) bool {
+	h := c.hub
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.running {
+		return false
+	}
+	h.running = true
+	h.start = time.Now()
+	seed := h.start.UnixNano()
+	for _, rc := range h.clients {
+		if !rc.inLobby {
+			continue
+		}
+		rc.racing = true
+		rc.correct, rc.total, rc.finished = 0, 0, 0
+		rc.send(raceStartMsg{seed: seed, mode: h.mode, cfg: cfg}, true)
+	}
+	h.broadcast()
+	return true
+}
+
+// report updates the client's progress; done marks it as finished.
+func (c *raceClient) report(correct, total int, done bool) {
+	h := c.hub
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if !c.racing || c.finished > 0 {
+		return
+	}
+	c.correct, c.total = correct, total
+	if done {
+		c.correct = total
+		c.finished = time.Since(h.start)
+		h.checkRaceOver()
+	}
+	h.broadcast()
+}
+
+// checkRaceOver ends the race once no racer is still playing.
+func (h *raceHub) checkRaceOver() {
+	if !h.running {
+		return
+	}
+	for _, c := range h.clients {
+		if c.racing && c.finished == 0 {
+			return
+		}
+	}
+	h.running = false
+}
+
+// broadcast sends the current scoreboard to every client in the lobby.
+func (h *raceHub) broadcast() {
+	board := raceBoard{mode: h.mode, running: h.running}
+	for _, c := range h.clients {
+		if c.inLobby {
+			board.entries = append(board.entries, raceEntry{
+				id: c.id, name: c.name, correct: c.correct, total: c.total,
+				finished: c.finished, racing: c.racing,
+			})
+		}
+	}
+	sortRaceEntries(board.entries)
+	for _, c := range h.clients {
+		if c.inLobby {
+			c.send(raceBoardMsg{board: board}, false)
+		}
+	}
+}
+
+// send queues msg for the client. Scoreboards are dropped when the client
+// lags behind (a newer one follows anyway); start messages are not.
+func (c *raceClient) send(msg tea.Msg, mustDeliver bool) {
+	select {
+	case c.ch <- msg:
+	default:
+		if mustDeliver {
+			go func() {
+				select {
+				case c.ch <- msg:
+				case <-c.done:
+				}
+			}()
+		}
+	}
+}
+
+// sortRaceEntries ranks finished racers by time, then everyone else by progress.
+func sortRaceEntries(entries []raceEntry) {
+	sort.SliceStable(entries, func(i, j int) bool {
+		a, b := entries[i], entries[j]
+		if (a.finished > 0) != (b.finished > 0) {
+			return a.finished > 0
+		}
+		if a.finished > 0 {
+			return a.finished < b.finished
+		}
+		if a.racing != b.racing {
+			return a.racing
+		}
+		if a.correct*max(b.total, 1) != b.correct*max(a.total, 1) {
+			return a.correct*max(b.total, 1) > b.correct*max(a.total, 1)
+		}
+		return a.id < b.id
+	})
+}
+
+// waitForRace delivers the next hub message to the model. It yields nil
+// (ignored by bubbletea) once the client is closed.
+func waitForRace(c *raceClient) tea.Cmd {
+	return func() tea.Msg {
+		select {
+		case msg := <-c.ch:
+			return msg
+		case <-c.done:
+			return nil
+		}
+	}
+}
@@ -0,0 +1,242 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// nextRaceMsg returns the client's next hub message, failing the test if
+// none arrives.
+func nextRaceMsg(t *testing.T, c *raceClient) tea.Msg {
+	t.Helper()
+	select {
+	case msg := <-c.ch:
+		return msg
+	case <-time.After(time.Second):
+		t.Fatalf("client %d (%s): no message from the hub", c.id, c.name)
+		return nil
+	}
+}
+
+// nextStart returns the client's next start message, skipping scoreboards.
+func nextStart(t *testing.T, c *raceClient) raceStartMsg {
+	t.Helper()
+	for {
+		if start, ok := nextRaceMsg(t, c).(raceStartMsg); ok {
+			return start
+		}
+	}
+}
+
+// lastBoard drains the client's queued messages and returns the latest
+// scoreboard, failing the test if a start message is among them.
+func lastBoard(t *testing.T, c *raceClient) raceBoard {
+	t.Helper()
+	var board raceBoard
+	for {
+		select {
+		case msg := <-c.ch:
+			b, ok := msg.(raceBoardMsg)
+			if !ok {
+				t.Fatalf("client %d: unexpected %T", c.id, msg)
+			}
+			board = b.board
+		default:
+			return board
+		}
+	}
+}
+
+func entryNames(board raceBoard) string {
+	var names []string
+	for _, e := range board.entries {
+		names = append(names, e.name)
+	}
+	return strings.Join(names, ",")
+}
+
+func TestRaceHubFlow(t *testing.T) {
+	h := newRaceHub()
+	alice, bob, carol := h.connect("alice"), h.connect("bob"), h.connect("carol")
+	alice.join()
+	bob.join()
+	if board := lastBoard(t, bob); entryNames(board) != "alice,bob" || board.running {
+		t.Errorf("lobby = %q (running %v), want alice,bob waiting", entryNames(board), board.running)
+	}
+	if len(carol.ch) != 0 {
+		t.Error("a client outside the lobby got a scoreboard")
+	}
+
+	alice.cycleMode()
+	cfg := raceConfig{instrType: "bass", tuning: []string{"E", "A", "D", "G"}, frets: 12}
+	if !alice.start(cfg) {
+		t.Fatal("start: refused with no race running")
+	}
+	if bob.start(cfg) {
+		t.Error("second start while running: accepted")
+	}
+	alice.cycleMode() // ignored mid-race
+	var seeds []int64
+	for _, c := range []*raceClient{alice, bob} {
+		start := nextStart(t, c)
+		if start.mode != "fretset" || start.cfg.instrType != "bass" {
+			t.Fatalf("%s: start message %+v", c.name, start)
+		}
+		seeds = append(seeds, start.seed)
+	}
+	if seeds[0] != seeds[1] {
+		t.Errorf("racers got different seeds %v", seeds)
+	}
+
+	bob.report(5, 10, false)
+	alice.report(2, 10, false)
+	if board := lastBoard(t, alice); entryNames(board) != "bob,alice" || board.mode != "fretset" {
+		t.Errorf("mid-race ranking = %q in %s, want bob ahead in fretset", entryNames(board), board.mode)
+	}
+	alice.report(0, 10, true)
+	alice.report(3, 10, false) // ignored after finishing
+	board := lastBoard(t, bob)
+	if entryNames(board) != "alice,bob" || board.entries[0].correct != 10 || board.entries[0].finished == 0 {
+		t.Errorf("after alice finished: %+v", board.entries)
+	}
+	if !board.running {
+		t.Error("race over while bob still plays")
+	}
+	bob.leave()
+	if board := lastBoard(t, alice); board.running || entryNames(board) != "alice" {
+		t.Errorf("after bob gave up: %q (running %v), want alice alone and the race over", entryNames(board), board.running)
+	}
+	if len(carol.ch) != 0 {
+		t.Error("a client outside the lobby got race messages")
+	}
+
+	// Closing a client ends its wait and removes it from the board.
+	bob.join()
+	lastBoard(t, bob)
+	bob.close()
+	if msg := waitForRace(bob)(); msg != nil {
+		t.Errorf("waitForRace on a closed client = %T, want nil", msg)
+	}
+	if board := lastBoard(t, alice); entryNames(board) != "alice" {
+		t.Errorf("after bob closed: %q", entryNames(board))
+	}
+}
+
+func TestSortRaceEntries(t *testing.T) {
+	entries := []raceEntry{
+		{id: 1, name: "idle"},
+		{id: 2, name: "half", racing: true, correct: 5, total: 10},
+		{id: 3, name: "slow", racing: true, finished: 3 * time.Minute},
+		{id: 4, name: "fast", racing: true, finished: time.Minute},
+		{id: 5, name: "most", racing: true, correct: 9, total: 10},
+		{id: 6, name: "tie", racing: true, correct: 1, total: 2},
+	}
+	sortRaceEntries(entries)
+	if got := entryNames(raceBoard{entries: entries}); got != "fast,slow,most,half,tie,idle" {
+		t.Errorf("ranking = %s", got)
+	}
+}
+
+// racer is a model in the race lobby of h with its own instrument options.
+func racer(t *testing.T, h *raceHub, name, instrType string, numStrings int) model {
+	t.Helper()
+	m := initialModel(lipgloss.DefaultRenderer())
+	m.instrType, m.numStrings, m.tuning = instrType, numStrings, defaultTuning(instrType, numStrings)
+	m.race = h.connect(name)
+	next, _ := m.enterRaceLobby()
+	return next.(model)
+}
+
+// deliver feeds the racer's queued hub messages to its model, as
+// waitForRace does in a running program.
+func deliver(t *testing.T, m model) model {
+	t.Helper()
+	for {
+		select {
+		case msg := <-m.race.ch:
+			next, _ := m.Update(msg)
+			m = next.(model)
+		default:
+			return m
+		}
+	}
+}
+
+func TestRaceRestoresInstrument(t *testing.T) {
+	h := newRaceHub()
+	host := racer(t, h, "host", "bass", 4)
+	guest := racer(t, h, "guest", "guitar", 6)
+
+	next, _ := host.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	host = deliver(t, next.(model))
+	guest = deliver(t, guest)
+	for _, m := range []model{host, guest} {
+		if m.state != statePlaying || !m.racing || m.instrType != "bass" || len(m.tuning) != 4 {
+			t.Fatalf("%s: state %v racing %v on %s %v, want racing on the host's bass", m.race.name, m.state, m.racing, m.instrType, m.tuning)
+		}
+	}
+
+	// Giving up returns to the menu with the guest's own guitar.
+	next, _ = guest.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	guest = next.(model)
+	if guest.state == statePlaying || guest.racing {
+		t.Fatalf("guest still racing after Esc")
+	}
+	if guest.instrType != "guitar" || guest.numStrings != 6 || !slices.Equal(guest.tuning, defaultTuning("guitar", 6)) {
+		t.Errorf("guest's options after the race: %s %d %v", guest.instrType, guest.numStrings, guest.tuning)
+	}
+	host = deliver(t, host)
+	if entryNames(host.raceBoard) != "host" {
+		t.Errorf("host's board after the guest left: %q", entryNames(host.raceBoard))
+	}
+}
+
+func TestRaceBoardSanitizesNames(t *testing.T) {
+	h := newRaceHub()
+	m := racer(t, h, "evil\x1b[2J\u202ename"+strings.Repeat("x", 40), "guitar", 6)
+	m = deliver(t, m)
+	view := m.viewRaceBoard()
+	if strings.Contains(view, "\x1b[2J") || strings.Contains(view, "\u202e") {
+		t.Errorf("control characters reach other terminals: %q", view)
+	}
+	if !strings.Contains(view, "evil[2Jname") || strings.Contains(view, strings.Repeat("x", 40)) {
+		t.Errorf("name not cut to %d characters: %q", maxNameLen, view)
+	}
+}
+
+// TestRaceHubConcurrent has many sessions race at once; run it with -race.
+func TestRaceHubConcurrent(t *testing.T) {
+	h := newRaceHub()
+	var wg sync.WaitGroup
+	for i := range 20 {
+		c := h.connect(fmt.Sprintf("p%d", i))
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for waitForRace(c)() != nil {
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			defer c.close()
+			c.join()
+			c.start(raceConfig{instrType: "guitar", tuning: defaultTuning("guitar", 6), frets: 12})
+			for n := range 10 {
+				c.report(n, 10, false)
+			}
+			c.report(10, 10, true)
+			c.leave()
+		}()
+	}
+	wg.Wait()
+	if len(h.clients) != 0 || h.running {
+		t.Errorf("after every session closed: %d clients, running %v", len(h.clients), h.running)
+	}
+}
@@ -0,0 +1,217 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"math"
+	"net/http"
+	"os"
+	"path"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// ── sample banks ──────────────────────────────────────────────────────────────
+//
+// The web page plays samples: one WAV per channel, note and velocity layer,
+// named <layer>-<midi><L|R>.wav (e.g. gt2-40L.wav). Each instrument has its
+// own bank under /assets/sounds/<bank>/ with a manifest.json listing its
+// files, and /assets/sounds/manifest.json lists every bank with its pitch
+// range, so the page can pick banks and layers without a new build.
+//
+// Guitar uses samples rendered from a Strat SoundFont; bass and ukulele use
+// synthetic banks from tools/synth-samples until real recordings exist.
+
+// sampleBankNames maps instruments to their embedded bank directories.
+var sampleBankNames = map[string]string{
+	"guitar":  "strat",
+	"bass":    "bass",
+	"ukulele": "ukulele",
+}
+
+var sampleFilePattern = regexp.MustCompile(`^([a-z]+[0-9]*)-([0-9]{1,3})([LR])\.wav$`)
+
+// sampleBank is a set of samples and the manifest describing them.
+type sampleBank struct {
+	path     string            // URL path, with trailing slash
+	files    map[string]*asset // by file name
+	info     sampleManifest
+	manifest *asset
+}
+
+// sampleManifest describes one bank. It is served as the bank's
+// manifest.json and as its entry in the list of all banks.
+type sampleManifest struct {
+	Path   string        `json:"path"`
+	Low    int           `json:"low"`    // lowest sampled MIDI note
+	High   int           `json:"high"`   // highest sampled MIDI note
+	Layers []sampleLayer `json:"layers"` // softest first
+}
+
+// sampleLayer is one velocity layer. Notes lists the MIDI numbers that have
+// both channels.
+type sampleLayer struct {
+	Name  string `json:"name"`
+	Notes []int  `json:"notes"`
+}
+
+// loadSampleBank loads every sample in the directory dir of fsys, to be
+// served below urlPath. Files that do not follow the naming scheme are
+// ignored. Layers sort by name, which for gt1/gt2/gt3 is softest to loudest.
+func loadSampleBank(fsys fs.FS, dir, urlPath string) (*sampleBank, error) {
+	files, err := loadSampleAssets(fsys, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	channels := map[string]map[int]int{} // layer → midi → channels found
+	for name := range files {
+		m := sampleFilePattern.FindStringSubmatch(name)
+		if m == nil {
+			delete(files, name)
+			continue
+		}
+		midi, _ := strconv.Atoi(m[2])
+		if channels[m[1]] == nil {
+			channels[m[1]] = map[int]int{}
+		}
+		channels[m[1]][midi]++
+	}
+	if len(channels) == 0 {
+		return nil, fmt.Errorf("no samples named <layer>-<midi><L|R>.wav in %s", dir)
+	}
+
+	manifest := sampleManifest{Path: urlPath, Low: math.MaxInt, High: math.MinInt}
+	for _, layer := range sortedKeys(channels) {
+		l := sampleLayer{Name: layer, Notes: []int{}}
+		for midi, n := range channels[layer] {
+			if n == 2 {
+				l.Notes = append(l.Notes, midi)
+				manifest.Low, manifest.High = min(manifest.Low, midi), max(manifest.High, midi)
+			}
+		}
+		slices.Sort(l.Notes)
+		manifest.Layers = append(manifest.Layers, l)
+	}
+	if manifest.Low > manifest.High {
+		return nil, fmt.Errorf("no sample in %s has both channels", dir)
+	}
+	data, err := json.Marshal(manifest)
+	if err != nil {
+		return nil, err
+	}
+	return &sampleBank{
+		path:     urlPath,
+		files:    files,
+		info:     manifest,
+		manifest: newAsset(data, "application/json", "no-cache", textCompression),
+	}, nil
+}
+
+// ServeHTTP serves manifest.json and the sample files below the bank's path.
+func (b *sampleBank) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	name := strings.TrimPrefix(r.URL.Path, b.path)
+	if name == "manifest.json" {
+		b.manifest.serve(w, r)
+		return
+	}
+	// Only the bank's own files are served; anything else under this
+	// prefix is a 404.
+	a, ok := b.files[name]
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+	a.serve(rec, r)
+	metrics.sampleBytes.Add(uint64(rec.bytes))
+}
+
+// sampleBanks is the registry of sample banks by instrument.
+type sampleBanks struct {
+	byInstrument map[string]*sampleBank
+	manifest     *asset
+	version      string // hash of every sample file, for the service worker
+}
+
+// openSampleBanks loads the embedded bank of every instrument, or the
+// directory given in dirs (instrument → directory) instead.
+func openSampleBanks(embedded fs.FS, dirs map[string]string) (*sampleBanks, error) {
+	for inst := range dirs {
+		if _, ok := sampleBankNames[inst]; !ok {
+			return nil, fmt.Errorf("no sample bank for instrument %q", inst)
+		}
+	}
+	banks := &sampleBanks{byInstrument: map[string]*sampleBank{}}
+	all := struct {
+		Banks map[string]sampleManifest `json:"banks"`
+	}{Banks: map[string]sampleManifest{}}
+	var files []byte // one "path hash" line per sample
+	for _, inst := range sortedKeys(sampleBankNames) {
+		name := sampleBankNames[inst]
+		urlPath := "/assets/sounds/" + name + "/"
+		var (
+			bank *sampleBank
+			err  error
+		)
+		if dir, ok := dirs[inst]; ok {
+			bank, err = loadSampleBank(os.DirFS(dir), ".", urlPath)
+		} else {
+			bank, err = loadSampleBank(embedded, path.Join("html/assets/sounds", name), urlPath)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s samples: %w", inst, err)
+		}
+		banks.byInstrument[inst] = bank
+		all.Banks[inst] = bank.info
+		for _, name := range sortedKeys(bank.files) {
+			files = append(files, bank.path+name+" "+bank.files[name].hash+"\n"...)
+		}
+	}
+	banks.version = assetHash(files)
+	data, err := json.Marshal(all)
+	if err != nil {
+		return nil, err
+	}
+	banks.manifest = newAsset(data, "application/json", "no-cache", textCompression)
+	return banks, nil
+}
+
+// register adds the banks and the list of all banks to mux.
+func (s *sampleBanks) register(mux *http.ServeMux) {
+	mux.HandleFunc("/assets/sounds/manifest.json", s.manifest.serve)
+	for _, bank := range s.byInstrument {
+		mux.Handle(bank.path, bank)
+	}
+}
+
+// sampleDirs is the repeatable --samples flag: DIR replaces the guitar bank,
+// INSTRUMENT=DIR the bank of that instrument.
+type sampleDirs map[string]string
+
+func (d sampleDirs) String() string {
+	parts := make([]string, 0, len(d))
+	for _, inst := range sortedKeys(d) {
+		parts = append(parts, inst+"="+d[inst])
+	}
+	return strings.Join(parts, ",")
+}
+
+func (d sampleDirs) Set(v string) error {
+	inst, dir, ok := strings.Cut(v, "=")
+	if !ok {
+		inst, dir = "guitar", v
+	}
+	if _, known := sampleBankNames[inst]; !known {
+		return fmt.Errorf("unknown instrument %q", inst)
+	}
+	if dir == "" {
+		return errors.New("empty directory")
+	}
+	d[inst] = dir
+	return nil
+}
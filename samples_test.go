@@ -0,0 +1,157 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+// wavs is a file system with an empty file for each name below dir.
+func wavs(dir string, names ...string) fstest.MapFS {
+	fsys := fstest.MapFS{}
+	for _, name := range names {
+		fsys[dir+"/"+name] = &fstest.MapFile{Data: []byte("RIFF" + name)}
+	}
+	return fsys
+}
+
+func TestLoadSampleBank(t *testing.T) {
+	fsys := wavs("bank",
+		"gt3-52L.wav", "gt3-52R.wav",
+		"gt1-40L.wav", "gt1-40R.wav", "gt1-52L.wav", "gt1-52R.wav",
+		"gt2-40L.wav", "gt2-40R.wav", "gt2-45L.wav", "gt2-45R.wav",
+		"gt2-47L.wav", // one channel only: not listed
+		"notes.wav", "GT2-50L.wav",
+	)
+	fsys["bank/readme.txt"] = &fstest.MapFile{Data: []byte("not a sample")}
+	bank, err := loadSampleBank(fsys, "bank", "/assets/sounds/test/")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Layers sort softest first and list only the notes with both
+	// channels, so a layer with gaps leaves the page to fall back.
+	want := sampleManifest{Path: "/assets/sounds/test/", Low: 40, High: 52, Layers: []sampleLayer{
+		{Name: "gt1", Notes: []int{40, 52}},
+		{Name: "gt2", Notes: []int{40, 45}},
+		{Name: "gt3", Notes: []int{52}},
+	}}
+	if !reflect.DeepEqual(bank.info, want) {
+		t.Errorf("manifest = %+v, want %+v", bank.info, want)
+	}
+
+	rec := httptest.NewRecorder()
+	bank.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/assets/sounds/test/manifest.json", nil))
+	var served sampleManifest
+	if err := json.Unmarshal(rec.Body.Bytes(), &served); err != nil || !reflect.DeepEqual(served, want) {
+		t.Errorf("served manifest.json = %s (%v)", rec.Body, err)
+	}
+	for name, status := range map[string]int{
+		"gt2-45L.wav": http.StatusOK,
+		"gt2-47L.wav": http.StatusOK, // named correctly, just not in the manifest
+		"notes.wav":   http.StatusNotFound,
+		"GT2-50L.wav": http.StatusNotFound,
+		"readme.txt":  http.StatusNotFound,
+		"../x.wav":    http.StatusNotFound,
+	} {
+		rec := httptest.NewRecorder()
+		bank.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/assets/sounds/test/"+name, nil))
+		if rec.Code != status {
+			t.Errorf("%s: status %d, want %d", name, rec.Code, status)
+		}
+	}
+}
+
+func TestLoadSampleBankErrors(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		fsys fstest.MapFS
+		want string
+	}{
+		{"missing directory", wavs("elsewhere", "gt1-40L.wav", "gt1-40R.wav"), "no samples named"},
+		{"no sample names", wavs("bank", "kick.wav", "gt1-40.wav"), "no samples named"},
+		{"single channels", wavs("bank", "gt1-40L.wav", "gt1-41R.wav"), "has both channels"},
+	} {
+		if _, err := loadSampleBank(tc.fsys, "bank", "/x/"); err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("%s: err = %v, want %q", tc.name, err, tc.want)
+		}
+	}
+}
+
+func TestSampleDirsSet(t *testing.T) {
+	for _, tc := range []struct {
+		args    []string
+		want    sampleDirs
+		wantErr string
+	}{
+		{args: []string{"/srv/strat"}, want: sampleDirs{"guitar": "/srv/strat"}},
+		{args: []string{"bass=/srv/bass", "ukulele=uke"}, want: sampleDirs{"bass": "/srv/bass", "ukulele": "uke"}},
+		{args: []string{"/a", "guitar=/b"}, want: sampleDirs{"guitar": "/b"}}, // the last one wins
+		{args: []string{"bass=/srv/x=y"}, want: sampleDirs{"bass": "/srv/x=y"}},
+		{args: []string{"banjo=/srv/banjo"}, wantErr: `unknown instrument "banjo"`},
+		{args: []string{"=/srv/strat"}, wantErr: `unknown instrument ""`},
+		{args: []string{"Bass=/srv/bass"}, wantErr: `unknown instrument "Bass"`},
+		{args: []string{"bass="}, wantErr: "empty directory"},
+		{args: []string{""}, wantErr: "empty directory"},
+	} {
+		d := sampleDirs{}
+		var err error
+		for _, arg := range tc.args {
+			if err = d.Set(arg); err != nil {
+				break
+			}
+		}
+		switch {
+		case tc.wantErr != "":
+			if err == nil || err.Error() != tc.wantErr {
+				t.Errorf("%q: err = %v, want %q", tc.args, err, tc.wantErr)
+			}
+		case err != nil:
+			t.Errorf("%q: %v", tc.args, err)
+		case !reflect.DeepEqual(d, tc.want):
+			t.Errorf("%q: dirs = %v, want %v", tc.args, d, tc.want)
+		}
+	}
+	if s := (sampleDirs{"ukulele": "u", "bass": "b"}).String(); s != "bass=b,ukulele=u" {
+		t.Errorf("String() = %q", s)
+	}
+}
+
+func TestOpenSampleBanksOverride(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"bs1-28L.wav", "bs1-28R.wav"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("RIFF"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	banks, err := openSampleBanks(sampleFiles, sampleDirs{"bass": dir})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := banks.byInstrument["bass"].info; got.Path != "/assets/sounds/bass/" || got.Low != 28 || got.High != 28 {
+		t.Errorf("bass bank = %+v, want the directory's one sample", got)
+	}
+	if len(banks.byInstrument["guitar"].info.Layers) == 0 || len(banks.byInstrument["ukulele"].info.Layers) == 0 {
+		t.Error("other instruments lost their embedded banks")
+	}
+	embedded, err := openSampleBanks(sampleFiles, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if banks.version == embedded.version {
+		t.Error("replacing a bank left the samples version unchanged")
+	}
+
+	if _, err := openSampleBanks(sampleFiles, sampleDirs{"banjo": dir}); err == nil {
+		t.Error("bank for an unknown instrument accepted")
+	}
+	if _, err := openSampleBanks(sampleFiles, sampleDirs{"ukulele": t.TempDir()}); err == nil || !strings.HasPrefix(err.Error(), "ukulele samples: ") {
+		t.Errorf("empty directory: err = %v", err)
+	}
+}
@@ -0,0 +1,901 @@
+package main
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	mrand "math/rand"
+	"net"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/funkymcb/fremorizer/game"
+	"github.com/funkymcb/fremorizer/instrument"
+	"github.com/funkymcb/fremorizer/stats"
+)
+
+// ── JSON API ──────────────────────────────────────────────────────────────────
+//
+// The /api/ endpoints expose the Go game engine to the web page and other
+// clients, so they can use the same note and chord logic as the TUI:
+//
+//	GET    /api/fretboard?instrument=&tuning=&frets=   note names per string/fret
+//	GET    /api/diagram?positions=&muted=&from=&to=&...  fretboard image (SVG or PNG)
+//	POST   /api/sessions                               start a game
+//	GET    /api/sessions/{id}                          current question and progress
+//	POST   /api/sessions/{id}/answers                  answer the current question
+//	DELETE /api/sessions/{id}                          end a game
+//	GET    /api/leaderboard?mode=&instrument=&...      ranked results
+//	POST   /api/players                                new anonymous player from its first results
+//	GET    /api/me/results                             the token's player's results
+//	POST   /api/me/results                             upload results (JSON array)
+//	GET    /api/me/stats                               per-mode summary
+//
+// The /api/me/ endpoints need "Authorization: Bearer <token>". Tokens come
+// from POST /api/players, or from `ssh host token` to share the SSH player's
+// stats with the browser or the local TUI.
+//
+// Strings are numbered from 1 = highest string, as on the TUI fretboard.
+// Tunings are written low to high, e.g. E-A-D-G-B-E.
+
+// apiModes are the game modes playable over the API: those answered by
+// typing a note or chord name.
+var apiModes = []string{"single", "chords"}
+
+const (
+	apiSessionTTL     = 30 * time.Minute // idle sessions are dropped after this
+	apiMaxSessions    = 1000
+	apiMaxBodyBytes   = 4 << 10
+	apiMaxUploadBytes = 1 << 20 // a browser's whole result history
+
+	apiPlayersPerClient = 10        // new players one client may create per apiPlayersWindow
+	apiPlayersWindow    = time.Hour // see apiPlayersPerClient
+)
+
+// registerAPI adds the /api/ routes to mux. A nil store disables the
+// leaderboard.
+func registerAPI(mux *http.ServeMux, store *stats.Store) {
+	mux.HandleFunc("GET /api/fretboard", handleFretboard)
+	mux.HandleFunc("GET /api/diagram", handleDiagram)
+	newAPISessions().register(mux)
+	if store != nil {
+		mux.Handle("GET /api/leaderboard", leaderboardHandler(store))
+		p := playerAPI{store: store, created: newRateLimiter(apiPlayersPerClient, apiPlayersWindow)}
+		mux.HandleFunc("POST /api/players", p.handleCreate)
+		mux.HandleFunc("GET /api/me/results", p.handleResults)
+		mux.HandleFunc("POST /api/me/results", p.handleUpload)
+		mux.HandleFunc("GET /api/me/stats", p.handleStats)
+	}
+	// Keep unknown API paths from falling through to the HTML page.
+	mux.HandleFunc("/api/", func(w http.ResponseWriter, r *http.Request) {
+		writeAPIError(w, http.StatusNotFound, "not found")
+	})
+}
+
+// writeJSON writes v with the given status code.
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	h := w.Header()
+	h.Set("Content-Type", "application/json")
+	h.Set("X-Content-Type-Options", "nosniff")
+	h.Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// writeAPIError writes {"error": msg} with the given status code.
+func writeAPIError(w http.ResponseWriter, status int, msg string) {
+	writeJSON(w, status, struct {
+		Error string `json:"error"`
+	}{msg})
+}
+
+// instrumentSetup is the instrument part of a request. Empty fields fall
+// back to a standard-tuned guitar with 12 frets.
+type instrumentSetup struct {
+	Instrument string `json:"instrument"`
+	Tuning     string `json:"tuning"` // low to high, e.g. "E-A-D-G-B-E"
+	Frets      int    `json:"frets"`
+}
+
+func setupFromQuery(q url.Values) (instrumentSetup, error) {
+	s := instrumentSetup{Instrument: q.Get("instrument"), Tuning: q.Get("tuning")}
+	if f := q.Get("frets"); f != "" {
+		frets, err := strconv.Atoi(f)
+		if err != nil {
+			return s, errors.New("invalid frets")
+		}
+		s.Frets = frets
+	}
+	return s, nil
+}
+
+// build creates the instrument described by s.
+func (s instrumentSetup) build() (*instrument.Instrument, error) {
+	instrType := s.Instrument
+	if instrType == "" {
+		instrType = "guitar"
+	}
+	tuning := defaultTuning(instrType, defaultStringCount(instrType))
+	if s.Tuning != "" {
+		tuning = strings.Split(s.Tuning, "-")
+	}
+	frets := s.Frets
+	if frets == 0 {
+		frets = 12
+	}
+	return buildInstrument(instrType, tuning, frets)
+}
+
+// ── fretboard ─────────────────────────────────────────────────────────────────
+
+type apiString struct {
+	Number int      `json:"number"` // 1 = highest string
+	Open   string   `json:"open"`
+	Notes  []string `json:"notes"` // index = fret, 0 = open string
+}
+
+type apiFretboard struct {
+	Instrument string      `json:"instrument"`
+	Tuning     []string    `json:"tuning"`
+	Frets      int         `json:"frets"`
+	Strings    []apiString `json:"strings"`
+}
+
+func newAPIFretboard(inst *instrument.Instrument) apiFretboard {
+	fb := apiFretboard{Instrument: inst.Type, Tuning: inst.Tuning, Frets: inst.Frets}
+	for i, s := range inst.Strings {
+		as := apiString{Number: i + 1, Open: s.Notes[0].Name}
+		for _, n := range s.Notes {
+			as.Notes = append(as.Notes, n.Name)
+		}
+		fb.Strings = append(fb.Strings, as)
+	}
+	return fb
+}
+
+func handleFretboard(w http.ResponseWriter, r *http.Request) {
+	setup, err := setupFromQuery(r.URL.Query())
+	if err != nil {
+		writeAPIError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	inst, err := setup.build()
+	if err != nil {
+		writeAPIError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, newAPIFretboard(inst))
+}
+
+// ── diagrams ──────────────────────────────────────────────────────────────────
+
+const (
+	diagramMaxLabel = 4  // runes per position label
+	diagramMaxTitle = 80 // runes
+)
+
+// handleDiagram draws the fretboard with highlighted positions, for chord
+// and scale diagrams in wikis, chats and link previews. Besides the
+// instrument it takes:
+//
+//	positions  string:fret[:label],… — string 1 = highest, fret 0 = open;
+//	           without a label the position shows its note name
+//	muted      string numbers marked as not played, e.g. 5,6
+//	from, to   the frets shown (default: the whole neck)
+//	title      caption above the fretboard
+//	lefty      true to mirror the board, the nut on the right
+//	flip       true to draw the lowest string on top
+//	format     svg (default) or png
+//
+// For example, an open C major chord:
+//
+//	/api/diagram?positions=2:1:1,3:0:5,4:2:3,5:3:1,1:0:3&muted=6&to=4&title=C
+func handleDiagram(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	setup, err := setupFromQuery(q)
+	if err != nil {
+		writeAPIError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	inst, err := setup.build()
+	if err != nil {
+		writeAPIError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	opts, err := applyDiagramQuery(inst, q)
+	if err != nil {
+		writeAPIError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var body []byte
+	var contentType string
+	switch q.Get("format") {
+	case "", "svg":
+		body, contentType = instrument.RenderSVG(inst, opts), "image/svg+xml"
+	case "png":
+		body, err = instrument.RenderPNG(inst, opts)
+		if err != nil {
+			writeAPIError(w, http.StatusInternalServerError, "rendering failed")
+			return
+		}
+		contentType = "image/png"
+	default:
+		writeAPIError(w, http.StatusBadRequest, "format must be svg or png")
+		return
+	}
+	h := w.Header()
+	h.Set("Content-Type", contentType)
+	h.Set("X-Content-Type-Options", "nosniff")
+	// The image only depends on the URL.
+	h.Set("Cache-Control", "public, max-age=86400")
+	// An SVG opened on its own is a document: keep it from running anything.
+	h.Set("Content-Security-Policy", "default-src 'none'; sandbox")
+	_, _ = w.Write(body)
+}
+
+// applyDiagramQuery marks the positions and muted strings of a diagram
+// request on inst and returns the remaining drawing options.
+func applyDiagramQuery(inst *instrument.Instrument, q url.Values) (instrument.DiagramOpts, error) {
+	var opts instrument.DiagramOpts
+	stringNumber := func(s string) (int, error) {
+		n, err := strconv.Atoi(s)
+		if err != nil || n < 1 || n > len(inst.Strings) {
+			return 0, fmt.Errorf("string must be between 1 and %d", len(inst.Strings))
+		}
+		return n, nil
+	}
+	fret := func(name, s string) (int, error) {
+		f, err := strconv.Atoi(s)
+		if err != nil || f < 0 || f > inst.Frets {
+			return 0, fmt.Errorf("%s must be between 0 and %d", name, inst.Frets)
+		}
+		return f, nil
+	}
+
+	if p := q.Get("positions"); p != "" {
+		for _, pos := range strings.Split(p, ",") {
+			parts := strings.SplitN(pos, ":", 3)
+			if len(parts) < 2 {
+				return opts, fmt.Errorf("invalid position %q, want string:fret[:label]", pos)
+			}
+			n, err := stringNumber(parts[0])
+			if err != nil {
+				return opts, err
+			}
+			f, err := fret("fret", parts[1])
+			if err != nil {
+				return opts, err
+			}
+			note := &inst.Strings[n-1].Notes[f]
+			if len(parts) == 2 {
+				note.ShowName = true
+				continue
+			}
+			label := strings.TrimSpace(parts[2])
+			if label == "" || utf8.RuneCountInString(label) > diagramMaxLabel {
+				return opts, fmt.Errorf("labels must be 1 to %d characters", diagramMaxLabel)
+			}
+			note.Interval = label
+		}
+	}
+	if m := q.Get("muted"); m != "" {
+		for _, s := range strings.Split(m, ",") {
+			n, err := stringNumber(s)
+			if err != nil {
+				return opts, err
+			}
+			inst.Strings[n-1].Notes[0].Muted = true
+		}
+	}
+	if s := q.Get("from"); s != "" {
+		f, err := fret("from", s)
+		if err != nil {
+			return opts, err
+		}
+		opts.FromFret = f
+	}
+	if s := q.Get("to"); s != "" {
+		f, err := fret("to", s)
+		if err != nil {
+			return opts, err
+		}
+		if f < max(opts.FromFret, 1) {
+			return opts, errors.New("to must not be below from")
+		}
+		opts.ToFret = f
+	}
+	opts.Title = q.Get("title")
+	if utf8.RuneCountInString(opts.Title) > diagramMaxTitle {
+		return opts, fmt.Errorf("title must be at most %d characters", diagramMaxTitle)
+	}
+	for name, flag := range map[string]*bool{"lefty": &opts.LeftHanded, "flip": &opts.FlipStrings} {
+		if s := q.Get(name); s != "" {
+			b, err := strconv.ParseBool(s)
+			if err != nil {
+				return opts, fmt.Errorf("%s must be true or false", name)
+			}
+			*flag = b
+		}
+	}
+	return opts, nil
+}
+
+// ── sessions ──────────────────────────────────────────────────────────────────
+
+type apiSessions struct {
+	mu       sync.Mutex
+	sessions map[string]*apiSession
+}
+
+func newAPISessions() *apiSessions {
+	return &apiSessions{sessions: map[string]*apiSession{}}
+}
+
+// register adds the /api/sessions routes to mux.
+func (s *apiSessions) register(mux *http.ServeMux) {
+	mux.HandleFunc("POST /api/sessions", s.handleCreate)
+	mux.HandleFunc("GET /api/sessions/{id}", s.handleGet)
+	mux.HandleFunc("POST /api/sessions/{id}/answers", s.handleAnswer)
+	mux.HandleFunc("DELETE /api/sessions/{id}", s.handleDelete)
+}
+
+// apiSession is one game played over the API. lastUsed is guarded by the
+// apiSessions mutex, the game state by mu.
+type apiSession struct {
+	mu           sync.Mutex
+	id           string
+	mode         string
+	game         game.Game
+	started      time.Time
+	lastUsed     time.Time
+	wrongGuesses int // wrong answers to the current single-mode question
+	mistakes     int
+}
+
+// get returns the live session with the given ID and marks it as used.
+func (s *apiSessions) get(id string) (*apiSession, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sess, ok := s.sessions[id]
+	if !ok || time.Since(sess.lastUsed) > apiSessionTTL {
+		delete(s.sessions, id)
+		return nil, false
+	}
+	sess.lastUsed = time.Now()
+	return sess, true
+}
+
+// add stores sess, dropping expired sessions first. It reports false if the
+// server is at its session limit.
+func (s *apiSessions) add(sess *apiSession) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for id, old := range s.sessions {
+		if time.Since(old.lastUsed) > apiSessionTTL {
+			delete(s.sessions, id)
+		}
+	}
+	if len(s.sessions) >= apiMaxSessions {
+		return false
+	}
+	s.sessions[sess.id] = sess
+	return true
+}
+
+func newSessionID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+type createSessionRequest struct {
+	Mode string `json:"mode"` // "single" or "chords"
+	instrumentSetup
+	ChordCount int    `json:"chordCount,omitempty"`
+	Seed       *int64 `json:"seed,omitempty"` // same seed, same question sequence
+}
+
+// apiQuestion describes what the player has to answer next.
+type apiQuestion struct {
+	// single
+	String int    `json:"string,omitempty"` // 1 = highest string
+	Fret   int    `json:"fret,omitempty"`
+	Open   string `json:"open,omitempty"` // open note of the string
+
+	// chords
+	Phase     string          `json:"phase,omitempty"` // "naming" or "intervals"
+	Chord     string          `json:"chord,omitempty"` // known after naming
+	Prompt    string          `json:"prompt,omitempty"`
+	Positions []apiChordPoint `json:"positions,omitempty"`
+	Muted     []int           `json:"muted,omitempty"` // muted string numbers
+}
+
+// apiChordPoint is a fretted position of the chord shape.
+type apiChordPoint struct {
+	String int `json:"string"`
+	Fret   int `json:"fret"`
+}
+
+type apiProgress struct {
+	Correct int `json:"correct"`
+	Total   int `json:"total"`
+}
+
+type apiSessionState struct {
+	ID          string       `json:"id"`
+	Mode        string       `json:"mode"`
+	Instrument  string       `json:"instrument"`
+	Tuning      []string     `json:"tuning"`
+	Frets       int          `json:"frets"`
+	Progress    apiProgress  `json:"progress"`
+	Mistakes    int          `json:"mistakes"`
+	GameOver    bool         `json:"gameOver"`
+	TimeSeconds float64      `json:"timeSeconds"`
+	Question    *apiQuestion `json:"question,omitempty"` // nil once the game is over
+}
+
+type answerRequest struct {
+	Answer string `json:"answer"`
+}
+
+type answerResponse struct {
+	Correct bool `json:"correct"`
+	// Solution is set when a single-mode note is revealed after three wrong
+	// answers, or for the note just named correctly.
+	Solution string `json:"solution,omitempty"`
+	// AttemptsLeft counts the wrong answers left for the current note.
+	AttemptsLeft int             `json:"attemptsLeft,omitempty"`
+	Session      apiSessionState `json:"session"`
+}
+
+func (s *apiSessions) handleCreate(w http.ResponseWriter, r *http.Request) {
+	var req createSessionRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, apiMaxBodyBytes)).Decode(&req); err != nil {
+		writeAPIError(w, http.StatusBadRequest, "invalid JSON body")
+		return
+	}
+	if !slices.Contains(apiModes, req.Mode) {
+		writeAPIError(w, http.StatusBadRequest, fmt.Sprintf("mode must be one of: %s", strings.Join(apiModes, ", ")))
+		return
+	}
+	inst, err := req.build()
+	if err != nil {
+		writeAPIError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	var rng *mrand.Rand
+	if req.Seed != nil {
+		rng = mrand.New(mrand.NewSource(*req.Seed))
+	}
+	g, err := game.New(req.Mode, inst, map[string]any{
+		// Chord marking (medium) needs a cursor, so the API plays easy mode.
+		"difficulty": "easy",
+		"chordCount": req.ChordCount,
+		"rand":       rng,
+	})
+	if err != nil {
+		writeAPIError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	now := time.Now()
+	sess := &apiSession{id: newSessionID(), mode: req.Mode, game: g, started: now, lastUsed: now}
+	if !s.add(sess) {
+		writeAPIError(w, http.StatusServiceUnavailable, "too many active sessions, try again later")
+		return
+	}
+	metrics.gameStarted(req.Mode)
+	writeJSON(w, http.StatusCreated, sess.state())
+}
+
+func (s *apiSessions) handleGet(w http.ResponseWriter, r *http.Request) {
+	sess, ok := s.get(r.PathValue("id"))
+	if !ok {
+		writeAPIError(w, http.StatusNotFound, "unknown or expired session")
+		return
+	}
+	sess.mu.Lock()
+	defer sess.mu.Unlock()
+	writeJSON(w, http.StatusOK, sess.state())
+}
+
+func (s *apiSessions) handleDelete(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	delete(s.sessions, r.PathValue("id"))
+	s.mu.Unlock()
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *apiSessions) handleAnswer(w http.ResponseWriter, r *http.Request) {
+	sess, ok := s.get(r.PathValue("id"))
+	if !ok {
+		writeAPIError(w, http.StatusNotFound, "unknown or expired session")
+		return
+	}
+	var req answerRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, apiMaxBodyBytes)).Decode(&req); err != nil {
+		writeAPIError(w, http.StatusBadRequest, "invalid JSON body")
+		return
+	}
+
+	sess.mu.Lock()
+	defer sess.mu.Unlock()
+	if sess.gameOver() {
+		writeAPIError(w, http.StatusConflict, "the game is over")
+		return
+	}
+	resp, err := sess.answer(strings.TrimSpace(req.Answer))
+	if err != nil {
+		writeAPIError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if resp.Session.GameOver {
+		metrics.gameCompleted(sess.mode)
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// answer applies one answer with the same rules as the TUI: a single-mode
+// note is revealed after three wrong answers and retried later; chord
+// answers can be repeated until right.
+func (sess *apiSession) answer(input string) (answerResponse, error) {
+	var resp answerResponse
+	switch g := sess.game.(type) {
+	case *game.SingleNoteGame:
+		if !instrument.IsValidNote(input) {
+			return resp, fmt.Errorf("%q is not a valid note", input)
+		}
+		note := g.CurrentNoteName()
+		if g.CheckAnswer(input) {
+			resp.Correct, resp.Solution = true, note
+			g.RevealNote(true)
+			_ = g.Next()
+			sess.wrongGuesses = 0
+			break
+		}
+		sess.mistakes++
+		sess.wrongGuesses++
+		if sess.wrongGuesses >= 3 {
+			resp.Solution = note
+			g.RevealNote(false)
+			_ = g.Next()
+			sess.wrongGuesses = 0
+			break
+		}
+		resp.AttemptsLeft = 3 - sess.wrongGuesses
+
+	case *game.ChordsGame:
+		if input == "" {
+			return resp, errors.New("empty answer")
+		}
+		if !g.CheckAnswer(input) {
+			sess.mistakes++
+			break
+		}
+		resp.Correct = true
+		_ = g.Next()
+		// The TUI waits for Enter after the last interval; the API moves on
+		// to the next chord right away.
+		if g.Phase() == game.ChordPhaseComplete {
+			_ = g.Next()
+		}
+	}
+	resp.Session = sess.state()
+	return resp, nil
+}
+
+func (sess *apiSession) gameOver() bool {
+	switch g := sess.game.(type) {
+	case *game.SingleNoteGame:
+		return g.IsGameOver()
+	case *game.ChordsGame:
+		return g.IsGameOver()
+	}
+	return false
+}
+
+// state snapshots the session for a response.
+func (sess *apiSession) state() apiSessionState {
+	inst := sess.game.GetInstrument()
+	st := apiSessionState{
+		ID:          sess.id,
+		Mode:        sess.mode,
+		Instrument:  inst.Type,
+		Tuning:      inst.Tuning,
+		Frets:       inst.Frets,
+		Mistakes:    sess.mistakes,
+		GameOver:    sess.gameOver(),
+		TimeSeconds: time.Since(sess.started).Seconds(),
+	}
+	switch g := sess.game.(type) {
+	case *game.SingleNoteGame:
+		st.Progress.Correct, st.Progress.Total = g.Progress()
+		if !st.GameOver {
+			si, fret := g.CurrentPosition()
+			st.Question = &apiQuestion{String: si + 1, Fret: fret, Open: inst.Strings[si].Notes[0].Name}
+		}
+	case *game.ChordsGame:
+		st.Progress.Correct, st.Progress.Total = g.Progress()
+		if !st.GameOver {
+			st.Question = chordQuestion(g)
+		}
+	}
+	return st
+}
+
+func chordQuestion(g *game.ChordsGame) *apiQuestion {
+	q := &apiQuestion{Phase: "naming", Prompt: "Which chord is this?"}
+	if g.Phase() == game.ChordPhaseIntervals {
+		q.Phase, q.Chord, q.Prompt = "intervals", g.ChordDisplayName(), g.CurrentIntervalPrompt()
+	}
+	for si, s := range g.GetInstrument().Strings {
+		if s.Notes[0].Muted {
+			q.Muted = append(q.Muted, si+1)
+		}
+		for fi, n := range s.Notes {
+			// Interval labels would give the answer away; only the shape is sent.
+			if n.Interval != "" {
+				q.Positions = append(q.Positions, apiChordPoint{String: si + 1, Fret: fi})
+			}
+		}
+	}
+	return q
+}
+
+// ── leaderboard ───────────────────────────────────────────────────────────────
+
+// leaderboardHandler serves a ranked leaderboard, e.g.
+// /api/leaderboard?mode=single&instrument=guitar&tuning=E-A-D-G-B-E&frets=12.
+// team and limit (default 10, 0 = all) are optional, and so are chords
+// (default 20) and difficulty (default easy) for mode=chords.
+func leaderboardHandler(store *stats.Store) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		mode := q.Get("mode")
+		if mode == "" {
+			mode = "single"
+		}
+		if !slices.Contains(stats.LeaderboardModes, mode) {
+			writeAPIError(w, http.StatusBadRequest, "unknown mode")
+			return
+		}
+		limit := 10
+		if l := q.Get("limit"); l != "" {
+			var err error
+			if limit, err = strconv.Atoi(l); err != nil || limit < 0 {
+				writeAPIError(w, http.StatusBadRequest, "invalid limit")
+				return
+			}
+		}
+		setup, err := setupFromQuery(q)
+		if err != nil {
+			writeAPIError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		inst, err := setup.build()
+		if err != nil {
+			writeAPIError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		chordCount, difficulty := 20, "easy"
+		if c := q.Get("chords"); c != "" {
+			if chordCount, err = strconv.Atoi(c); err != nil || chordCount < 1 || chordCount > 99 {
+				writeAPIError(w, http.StatusBadRequest, "chords must be 1-99")
+				return
+			}
+		}
+		if d := q.Get("difficulty"); d != "" {
+			if !slices.Contains(chordDifficulties, d) {
+				writeAPIError(w, http.StatusBadRequest, "unknown difficulty")
+				return
+			}
+			difficulty = d
+		}
+
+		key := stats.ConfigKey(mode, inst.Type, inst.Tuning, inst.Frets, chordCount, difficulty)
+		entries, err := store.Leaderboard(key, q.Get("team"), limit)
+		if err != nil {
+			log.Printf("leaderboard: %v", err)
+			writeAPIError(w, http.StatusInternalServerError, "internal error")
+			return
+		}
+		if entries == nil {
+			entries = []stats.Entry{}
+		}
+		writeJSON(w, http.StatusOK, struct {
+			Key     string        `json:"key"`
+			Entries []stats.Entry `json:"entries"`
+		}{key, entries})
+	})
+}
+
+// ── player results ────────────────────────────────────────────────────────────
+
+// playerAPI stores web results in the same stats store as the SSH server.
+type playerAPI struct {
+	store   *stats.Store
+	created *rateLimiter // new players per client
+}
+
+// rateLimiter allows each key limit events per window. Windows are fixed and
+// start with a key's first event.
+type rateLimiter struct {
+	limit  int
+	window time.Duration
+
+	mu   sync.Mutex
+	seen map[string]rateWindow
+}
+
+type rateWindow struct {
+	start time.Time
+	n     int
+}
+
+func newRateLimiter(limit int, window time.Duration) *rateLimiter {
+	return &rateLimiter{limit: limit, window: window, seen: map[string]rateWindow{}}
+}
+
+// allow records an event for key and reports whether it is within the limit.
+func (l *rateLimiter) allow(key string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := time.Now()
+	for k, w := range l.seen {
+		if now.Sub(w.start) >= l.window {
+			delete(l.seen, k)
+		}
+	}
+	w, ok := l.seen[key]
+	if !ok {
+		w.start = now
+	}
+	if w.n >= l.limit {
+		return false
+	}
+	w.n++
+	l.seen[key] = w
+	return true
+}
+
+// clientNetwork returns the key a client is limited by: its IPv4 address, or
+// the /64 network of an IPv6 one, as a single host usually holds a whole /64.
+func clientNetwork(client string) string {
+	ip := net.ParseIP(client)
+	if ip == nil || ip.To4() != nil {
+		return client
+	}
+	return ip.Mask(net.CIDRMask(64, 128)).String()
+}
+
+// player resolves the request's bearer token. On failure it writes the error
+// response and returns "".
+func (p playerAPI) player(w http.ResponseWriter, r *http.Request) string {
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok || token == "" {
+		writeAPIError(w, http.StatusUnauthorized, "missing bearer token")
+		return ""
+	}
+	player, err := p.store.TokenPlayer(token)
+	if err != nil {
+		log.Printf("token lookup: %v", err)
+		writeAPIError(w, http.StatusInternalServerError, "internal error")
+		return ""
+	}
+	if player == "" {
+		writeAPIError(w, http.StatusUnauthorized, "unknown token")
+	}
+	return player
+}
+
+// handleCreate starts an anonymous player with the results in the body, a
+// non-empty JSON array as for handleUpload. The token is only handed out
+// with a first result, and each client may only create apiPlayersPerClient
+// players per apiPlayersWindow; tokens that never sync expire.
+func (p playerAPI) handleCreate(w http.ResponseWriter, r *http.Request) {
+	if !p.created.allow(clientNetwork(requestClient(r))) {
+		w.Header().Set("Retry-After", strconv.Itoa(int(apiPlayersWindow.Seconds())))
+		writeAPIError(w, http.StatusTooManyRequests, "too many new players from this address; try again later")
+		return
+	}
+	results, ok := decodeResults(w, r)
+	if !ok {
+		return
+	}
+	if len(results) == 0 {
+		writeAPIError(w, http.StatusBadRequest, "a new player needs at least one result")
+		return
+	}
+	player, token, err := p.store.NewAnonymousPlayer(results)
+	if err != nil {
+		log.Printf("new player: %v", err)
+		writeAPIError(w, http.StatusInternalServerError, "internal error")
+		return
+	}
+	writeJSON(w, http.StatusCreated, struct {
+		Player string `json:"player"`
+		Token  string `json:"token"`
+	}{player, token})
+}
+
+func (p playerAPI) handleResults(w http.ResponseWriter, r *http.Request) {
+	player := p.player(w, r)
+	if player == "" {
+		return
+	}
+	results, err := p.store.Results(player)
+	if err != nil {
+		log.Printf("results: %v", err)
+		writeAPIError(w, http.StatusInternalServerError, "internal error")
+		return
+	}
+	if results == nil {
+		results = []stats.Result{}
+	}
+	writeJSON(w, http.StatusOK, results)
+}
+
+func (p playerAPI) handleStats(w http.ResponseWriter, r *http.Request) {
+	player := p.player(w, r)
+	if player == "" {
+		return
+	}
+	results, err := p.store.Results(player)
+	if err != nil {
+		log.Printf("results: %v", err)
+		writeAPIError(w, http.StatusInternalServerError, "internal error")
+		return
+	}
+	writeJSON(w, http.StatusOK, stats.Summarize(results))
+}
+
+// handleUpload merges a JSON array of results (the page's frem_stats
+// records) into the player's history. Results already stored are skipped,
+// so a client may upload its whole history on every sync.
+func (p playerAPI) handleUpload(w http.ResponseWriter, r *http.Request) {
+	player := p.player(w, r)
+	if player == "" {
+		return
+	}
+	results, ok := decodeResults(w, r)
+	if !ok {
+		return
+	}
+	added, err := p.store.Merge(player, results)
+	if err != nil {
+		log.Printf("merge results: %v", err)
+		writeAPIError(w, http.StatusInternalServerError, "internal error")
+		return
+	}
+	writeJSON(w, http.StatusOK, struct {
+		Added int `json:"added"`
+	}{added})
+}
+
+// decodeResults reads a JSON array of results from the request body. On
+// failure it writes the error response and returns false.
+func decodeResults(w http.ResponseWriter, r *http.Request) ([]stats.Result, bool) {
+	var results []stats.Result
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, apiMaxUploadBytes)).Decode(&results); err != nil {
+		writeAPIError(w, http.StatusBadRequest, "body must be a JSON array of results")
+		return nil, false
+	}
+	for i, res := range results {
+		if res.Mode == "" || len(res.Mode) > 32 || res.Timestamp <= 0 || res.TimeSeconds < 0 || res.TotalItems < 0 {
+			writeAPIError(w, http.StatusBadRequest, fmt.Sprintf("result %d: mode, timestamp and non-negative times are required", i))
+			return nil, false
+		}
+	}
+	return results, true
+}
@@ -0,0 +1,414 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/funkymcb/fremorizer/instrument"
+	"github.com/funkymcb/fremorizer/stats"
+)
+
+// apiRequest sends a request to h and decodes the JSON response into out,
+// if out is not nil.
+func apiRequest(t *testing.T, h http.Handler, method, path, body string, out any) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if out != nil {
+		if err := json.Unmarshal(rec.Body.Bytes(), out); err != nil {
+			t.Fatalf("%s %s: invalid JSON %q: %v", method, path, rec.Body, err)
+		}
+	}
+	return rec
+}
+
+func newAPIMux() *http.ServeMux {
+	mux := http.NewServeMux()
+	registerAPI(mux, nil)
+	return mux
+}
+
+func TestAPIFretboard(t *testing.T) {
+	mux := newAPIMux()
+	tests := []struct {
+		query   string
+		status  int
+		strings int
+		frets   int
+	}{
+		{"", http.StatusOK, 6, 12},
+		{"?instrument=bass&frets=24", http.StatusOK, 4, 24},
+		{"?instrument=guitar&tuning=B-E-A-D-G-B-E", http.StatusOK, 7, 12},
+		{"?instrument=ukulele", http.StatusOK, 4, 12},
+		{"?frets=many", http.StatusBadRequest, 0, 0},
+		{"?frets=30", http.StatusBadRequest, 0, 0},
+		{"?instrument=banjo", http.StatusBadRequest, 0, 0},
+		{"?tuning=E-A-D", http.StatusBadRequest, 0, 0},
+		{"?tuning=E-A-D-G-B-X", http.StatusBadRequest, 0, 0},
+	}
+	for _, tt := range tests {
+		var fb apiFretboard
+		rec := apiRequest(t, mux, "GET", "/api/fretboard"+tt.query, "", &fb)
+		if rec.Code != tt.status {
+			t.Errorf("%q: status %d, want %d (%s)", tt.query, rec.Code, tt.status, rec.Body)
+			continue
+		}
+		if tt.status != http.StatusOK {
+			continue
+		}
+		if len(fb.Strings) != tt.strings || fb.Frets != tt.frets || len(fb.Strings[0].Notes) != tt.frets+1 {
+			t.Errorf("%q: %d strings, %d frets, %d notes per string", tt.query, len(fb.Strings), fb.Frets, len(fb.Strings[0].Notes))
+		}
+	}
+
+	var fb apiFretboard
+	apiRequest(t, mux, "GET", "/api/fretboard", "", &fb)
+	if s := fb.Strings[0]; s.Number != 1 || s.Open != "E" || s.Notes[1] != "F" {
+		t.Errorf("string 1 = %+v, want the high E", s)
+	}
+}
+
+func TestAPIUnknownPath(t *testing.T) {
+	mux := newAPIMux()
+	for _, path := range []string{"/api/", "/api/nope", "/api/sessions", "/api/me/results"} {
+		var body struct{ Error string }
+		rec := apiRequest(t, mux, "GET", path, "", &body)
+		if rec.Code != http.StatusNotFound || body.Error == "" {
+			t.Errorf("GET %s: status %d, error %q, want 404", path, rec.Code, body.Error)
+		}
+	}
+}
+
+func TestAPISessionCycle(t *testing.T) {
+	mux := newAPIMux()
+	var st apiSessionState
+	rec := apiRequest(t, mux, "POST", "/api/sessions", `{"mode":"single","seed":7}`, &st)
+	if rec.Code != http.StatusCreated || st.ID == "" || st.Question == nil {
+		t.Fatalf("create: status %d, %+v", rec.Code, st)
+	}
+	path := "/api/sessions/" + st.ID
+
+	var got apiSessionState
+	if rec := apiRequest(t, mux, "GET", path, "", &got); rec.Code != http.StatusOK || got.Question.String != st.Question.String || got.Question.Fret != st.Question.Fret {
+		t.Fatalf("get: status %d, question %+v, want %+v", rec.Code, got.Question, st.Question)
+	}
+
+	for _, tt := range []struct {
+		body   string
+		status int
+	}{
+		{`{"answer":"H"}`, http.StatusBadRequest},
+		{`not json`, http.StatusBadRequest},
+	} {
+		if rec := apiRequest(t, mux, "POST", path+"/answers", tt.body, nil); rec.Code != tt.status {
+			t.Errorf("answer %s: status %d, want %d", tt.body, rec.Code, tt.status)
+		}
+	}
+
+	guitar, err := instrument.NewGuitar(instrument.DefaultGuitarTuning(6), 12)
+	if err != nil {
+		t.Fatal(err)
+	}
+	note := func(q *apiQuestion) string {
+		name, _, _ := strings.Cut(guitar.Strings[q.String-1].Notes[q.Fret].Name, "/")
+		return name
+	}
+
+	// Three wrong answers reveal the note.
+	q := *st.Question
+	wrong := "C"
+	if note(&q) == "C" {
+		wrong = "D"
+	}
+	var resp answerResponse
+	for i := 2; i >= 0; i-- {
+		resp = answerResponse{}
+		apiRequest(t, mux, "POST", path+"/answers", `{"answer":"`+wrong+`"}`, &resp)
+		if resp.Correct || resp.AttemptsLeft != i {
+			t.Fatalf("wrong answer: %+v, want %d attempts left", resp, i)
+		}
+	}
+	if resp.Solution == "" || resp.Session.Mistakes != 3 {
+		t.Errorf("after three wrong answers: solution %q, %d mistakes", resp.Solution, resp.Session.Mistakes)
+	}
+
+	// Answer everything else right until the game is over.
+	for n := 0; !resp.Session.GameOver; n++ {
+		if n > 200 {
+			t.Fatal("game does not end")
+		}
+		q := resp.Session.Question
+		resp = answerResponse{}
+		apiRequest(t, mux, "POST", path+"/answers", `{"answer":"`+note(q)+`"}`, &resp)
+		if !resp.Correct {
+			t.Fatalf("answer %s for string %d fret %d: %+v", note(q), q.String, q.Fret, resp)
+		}
+	}
+	if p := resp.Session.Progress; resp.Session.Question != nil || p.Correct != p.Total {
+		t.Errorf("game over: progress %+v, question %+v", p, resp.Session.Question)
+	}
+	if rec := apiRequest(t, mux, "POST", path+"/answers", `{"answer":"C"}`, nil); rec.Code != http.StatusConflict {
+		t.Errorf("answer after game over: status %d, want 409", rec.Code)
+	}
+
+	if rec := apiRequest(t, mux, "DELETE", path, "", nil); rec.Code != http.StatusNoContent {
+		t.Errorf("delete: status %d", rec.Code)
+	}
+	if rec := apiRequest(t, mux, "GET", path, "", nil); rec.Code != http.StatusNotFound {
+		t.Errorf("get after delete: status %d, want 404", rec.Code)
+	}
+}
+
+func TestAPISessionCreateValidation(t *testing.T) {
+	mux := newAPIMux()
+	tests := []struct {
+		body   string
+		status int
+	}{
+		{`{"mode":"chords","chordCount":3}`, http.StatusCreated},
+		{`{"mode":"single","instrument":"bass","frets":24}`, http.StatusCreated},
+		{`{"mode":"fretset"}`, http.StatusBadRequest},
+		{`{}`, http.StatusBadRequest},
+		{`{"mode":"single","instrument":"banjo"}`, http.StatusBadRequest},
+		{`{"mode":"single","frets":99}`, http.StatusBadRequest},
+		{`{"mode":`, http.StatusBadRequest},
+		{`{"mode":"single","tuning":"` + strings.Repeat("E-", 4<<10) + `E"}`, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		if rec := apiRequest(t, mux, "POST", "/api/sessions", tt.body, nil); rec.Code != tt.status {
+			t.Errorf("create %.40s: status %d, want %d (%s)", tt.body, rec.Code, tt.status, rec.Body)
+		}
+	}
+}
+
+func TestAPISessionExpiry(t *testing.T) {
+	s := newAPISessions()
+	mux := http.NewServeMux()
+	s.register(mux)
+
+	var st apiSessionState
+	apiRequest(t, mux, "POST", "/api/sessions", `{"mode":"single"}`, &st)
+	s.sessions[st.ID].lastUsed = time.Now().Add(-apiSessionTTL - time.Second)
+	if rec := apiRequest(t, mux, "GET", "/api/sessions/"+st.ID, "", nil); rec.Code != http.StatusNotFound {
+		t.Errorf("expired session: status %d, want 404", rec.Code)
+	}
+	if _, ok := s.sessions[st.ID]; ok {
+		t.Error("expired session kept")
+	}
+}
+
+func TestAPIMaxSessions(t *testing.T) {
+	s := newAPISessions()
+	mux := http.NewServeMux()
+	s.register(mux)
+
+	for range apiMaxSessions {
+		id := newSessionID()
+		s.sessions[id] = &apiSession{id: id, lastUsed: time.Now()}
+	}
+	if rec := apiRequest(t, mux, "POST", "/api/sessions", `{"mode":"single"}`, nil); rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("create at the limit: status %d, want 503", rec.Code)
+	}
+
+	// Expired sessions make room.
+	for _, sess := range s.sessions {
+		sess.lastUsed = time.Now().Add(-apiSessionTTL - time.Second)
+		break
+	}
+	if rec := apiRequest(t, mux, "POST", "/api/sessions", `{"mode":"single"}`, nil); rec.Code != http.StatusCreated {
+		t.Errorf("create after expiry: status %d, want 201", rec.Code)
+	}
+	if len(s.sessions) != apiMaxSessions {
+		t.Errorf("%d sessions, want %d", len(s.sessions), apiMaxSessions)
+	}
+}
+
+func TestAPIPlayers(t *testing.T) {
+	store, err := stats.Open(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	mux := http.NewServeMux()
+	registerAPI(mux, store)
+
+	for _, body := range []string{``, `[]`, `{}`, `[{"mode":"random"}]`} {
+		if rec := apiRequest(t, mux, "POST", "/api/players", body, nil); rec.Code != http.StatusBadRequest {
+			t.Errorf("create player with %q: status %d, want 400", body, rec.Code)
+		}
+	}
+
+	var created struct{ Player, Token string }
+	rec := apiRequest(t, mux, "POST", "/api/players", `[{"mode":"random","timeSeconds":60,"totalItems":10,"timestamp":1000}]`, &created)
+	if rec.Code != http.StatusCreated || created.Token == "" {
+		t.Fatalf("create player: status %d, %+v", rec.Code, created)
+	}
+
+	req := httptest.NewRequest("GET", "/api/me/results", nil)
+	req.Header.Set("Authorization", "Bearer "+created.Token)
+	rr := httptest.NewRecorder()
+	mux.ServeHTTP(rr, req)
+	var results []stats.Result
+	if err := json.Unmarshal(rr.Body.Bytes(), &results); err != nil || len(results) != 1 || results[0].Mode != "single" {
+		t.Errorf("results = %s, want the first result as mode single", rr.Body)
+	}
+
+	if rec := apiRequest(t, mux, "GET", "/api/me/results", "", nil); rec.Code != http.StatusUnauthorized {
+		t.Errorf("results without a token: status %d, want 401", rec.Code)
+	}
+}
+
+func TestAPIPlayersRateLimit(t *testing.T) {
+	store, err := stats.Open(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	mux := http.NewServeMux()
+	registerAPI(mux, store)
+	// Behind the proxy, the client is the last X-Forwarded-For entry.
+	h := accessLog(mux, true)
+	create := func(client string) int {
+		req := httptest.NewRequest("POST", "/api/players", strings.NewReader(`[{"mode":"random","timestamp":1000}]`))
+		req.Header.Set("X-Forwarded-For", "203.0.113.9, "+client)
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec.Code
+	}
+	for i := range apiPlayersPerClient {
+		if code := create("192.0.2.1"); code != http.StatusCreated {
+			t.Fatalf("player %d: status %d", i+1, code)
+		}
+	}
+	if code := create("192.0.2.1"); code != http.StatusTooManyRequests {
+		t.Errorf("player over the limit: status %d, want 429", code)
+	}
+	if code := create("192.0.2.2"); code != http.StatusCreated {
+		t.Errorf("other client: status %d, want 201", code)
+	}
+
+	for _, tt := range []struct{ client, want string }{
+		{"192.0.2.1", "192.0.2.1"},
+		{"2001:db8:1:2:3:4:5:6", "2001:db8:1:2::"},
+		{"2001:db8:1:2::ffff", "2001:db8:1:2::"},
+		{"not an ip", "not an ip"},
+	} {
+		if got := clientNetwork(tt.client); got != tt.want {
+			t.Errorf("clientNetwork(%q) = %q, want %q", tt.client, got, tt.want)
+		}
+	}
+}
+
+func TestRateLimiterWindow(t *testing.T) {
+	l := newRateLimiter(1, 20*time.Millisecond)
+	if !l.allow("a") || l.allow("a") || !l.allow("b") {
+		t.Fatal("limit of 1 per window not applied per key")
+	}
+	time.Sleep(30 * time.Millisecond)
+	if !l.allow("a") {
+		t.Error("new window: still limited")
+	}
+}
+
+func TestRemoteStatsSync(t *testing.T) {
+	server, err := stats.Open(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	token, err := server.NewToken("alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	web := stats.Result{Mode: "find", TimeSeconds: 70, TotalItems: 12, Timestamp: 2000}
+	if _, err := server.Merge("alice", []stats.Result{web}); err != nil {
+		t.Fatal(err)
+	}
+	mux := http.NewServeMux()
+	registerAPI(mux, server)
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	local, err := stats.Open(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	tui := stats.Result{Mode: "single", TimeSeconds: 60, TotalItems: 10, Timestamp: 1000}
+	if err := local.Add(localPlayer, tui); err != nil {
+		t.Fatal(err)
+	}
+
+	rs, err := newRemoteStats(ts.URL+"/", token)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for range 2 {
+		if err := rs.sync(local, localPlayer); err != nil {
+			t.Fatalf("sync: %v", err)
+		}
+	}
+	for name, s := range map[string]struct {
+		store  *stats.Store
+		player string
+	}{"local": {local, localPlayer}, "server": {server, "alice"}} {
+		got, _ := s.store.Results(s.player)
+		if len(got) != 2 {
+			t.Errorf("%s results = %+v, want the TUI and the web result", name, got)
+		}
+	}
+
+	if _, err := newRemoteStats(ts.URL, ""); err == nil {
+		t.Error("newRemoteStats without a token: expected error")
+	}
+	bad, _ := newRemoteStats(ts.URL, "wrong")
+	if err := bad.sync(local, localPlayer); err == nil || !strings.Contains(err.Error(), "unknown token") {
+		t.Errorf("sync with a wrong token = %v, want unknown token", err)
+	}
+}
+
+func TestAPIDiagram(t *testing.T) {
+	mux := newAPIMux()
+	const chord = "/api/diagram?positions=2:1:1,3:0:5,4:2:3,5:3:1,1:0:3&muted=6&to=4&title=C"
+	tests := []struct {
+		query       string
+		status      int
+		contentType string
+	}{
+		{"", http.StatusOK, "image/svg+xml"},
+		{"&format=png", http.StatusOK, "image/png"},
+		{"&lefty=true&flip=1", http.StatusOK, "image/svg+xml"},
+		{"&lefty=false", http.StatusOK, "image/svg+xml"},
+		{"&lefty=yes", http.StatusBadRequest, ""},
+		{"&flip=2", http.StatusBadRequest, ""},
+		{"&format=gif", http.StatusBadRequest, ""},
+		{"?positions=7:1", http.StatusBadRequest, ""},
+		{"&from=5", http.StatusBadRequest, ""}, // above to=4
+	}
+	bodies := map[string]string{}
+	for _, tt := range tests {
+		// Queries starting with ? replace the chord's.
+		path := chord + tt.query
+		if strings.HasPrefix(tt.query, "?") {
+			path = "/api/diagram" + tt.query
+		}
+		rec := apiRequest(t, mux, "GET", path, "", nil)
+		if rec.Code != tt.status {
+			t.Errorf("%q: status %d, want %d (%s)", tt.query, rec.Code, tt.status, rec.Body)
+			continue
+		}
+		if tt.status == http.StatusOK {
+			if ct := rec.Header().Get("Content-Type"); ct != tt.contentType {
+				t.Errorf("%q: Content-Type %q, want %q", tt.query, ct, tt.contentType)
+			}
+			bodies[tt.query] = rec.Body.String()
+		}
+	}
+	if bodies[""] == bodies["&lefty=true&flip=1"] {
+		t.Error("lefty and flip do not change the diagram")
+	}
+	if bodies[""] != bodies["&lefty=false"] {
+		t.Error("lefty=false changes the diagram")
+	}
+}
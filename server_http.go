@@ -6,15 +6,20 @@ import (
 	"crypto/sha256"
 	"crypto/tls"
 	"embed"
+	"encoding/base64"
 	"encoding/hex"
+	"fmt"
 	"log"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
+	"regexp"
 	"strings"
 	"syscall"
 	"time"
 
+	"github.com/funkymcb/fremorizer/stats"
 	"golang.org/x/crypto/acme/autocert"
 )
 
@@ -30,28 +35,46 @@ var libJS []byte
 //go:embed html/favicon.ico
 var faviconBytes []byte
 
-// Strat samples — medium-velocity layer only (~11MB). Extend the glob to
-// embed gt1-*.wav / gt3-*.wav if SAMPLE_LAYER in the page is changed.
+// The production build from tools/precompile-jsx. Checkouts that have not
+// run it only contain the directory's README; the page is then served from
+// the sources above.
 //
-//go:embed html/assets/sounds/strat/gt2-*.wav
-var stratSamples embed.FS
+//go:embed html/dist
+var buildFiles embed.FS
 
-func serveHTTP(domain, addr string) {
+// Sample banks: the Strat recordings (all three velocity layers, ~33MB) and
+// the synthetic bass and ukulele banks. --samples replaces them with
+// directories on disk.
+//
+//go:embed html/assets/sounds/strat/*.wav html/assets/sounds/bass/*.wav html/assets/sounds/ukulele/*.wav
+var sampleFiles embed.FS
+
+func serveHTTP(domain, addr, metricsAddr string, sampleDirs map[string]string) {
+	samples, err := openSampleBanks(sampleFiles, sampleDirs)
+	if err != nil {
+		log.Fatal("load samples: ", err)
+	}
+	store := openServerStats()
+	ops := serveOps(metricsAddr, store)
+	handler := pageHandler(store, samples)
 	if addr != "" {
-		serveHTTPProxy(addr)
-		return
+		serveHTTPProxy(addr, handler)
+	} else {
+		serveHTTPStandalone(domain, handler)
+	}
+	if ops != nil {
+		_ = ops.Close()
 	}
-	serveHTTPStandalone(domain)
 }
 
 // serveHTTPProxy runs a plain HTTP server on addr for use behind a reverse
 // proxy (e.g. Caddy) that terminates TLS. The server binds only to the given
 // address, which should be a localhost interface so it is not reachable from
 // outside the machine.
-func serveHTTPProxy(addr string) {
+func serveHTTPProxy(addr string, handler http.Handler) {
 	srv := &http.Server{
 		Addr:              addr,
-		Handler:           pageHandler(),
+		Handler:           accessLog(handler, true),
 		ReadHeaderTimeout: 5 * time.Second,
 		ReadTimeout:       15 * time.Second,
 		WriteTimeout:      15 * time.Second,
@@ -61,16 +84,22 @@ func serveHTTPProxy(addr string) {
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, os.Interrupt, syscall.SIGTERM)
 
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		log.Fatal("HTTP server error:", err)
+	}
+	ready.Store(true)
 	errCh := make(chan error, 1)
 	go func() {
 		log.Printf("HTTP server listening on %s (proxy mode)", addr)
-		errCh <- srv.ListenAndServe()
+		errCh <- srv.Serve(ln)
 	}()
 
 	select {
 	case err := <-errCh:
 		log.Fatal("HTTP server error:", err)
 	case <-quit:
+		ready.Store(false)
 		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 		defer cancel()
 		_ = srv.Shutdown(ctx)
@@ -80,7 +109,7 @@ func serveHTTPProxy(addr string) {
 // serveHTTPStandalone runs a self-contained HTTPS server with automatic
 // Let's Encrypt certificates. Use this when Caddy (or another reverse proxy)
 // is not in front of fremorizer.
-func serveHTTPStandalone(domain string) {
+func serveHTTPStandalone(domain string, handler http.Handler) {
 	certCache := "/var/cache/fremorizer-certs"
 	if _, err := os.Stat(certCache); os.IsNotExist(err) {
 		certCache = ".certs" // fallback for local testing
@@ -103,10 +132,11 @@ func serveHTTPStandalone(domain string) {
 		tls.TLS_ECDHE_ECDSA_WITH_CHACHA20_POLY1305_SHA256,
 		tls.TLS_ECDHE_RSA_WITH_CHACHA20_POLY1305_SHA256,
 	}
+	trackCertExpiry(tlsCfg, domain)
 
 	httpsServer := &http.Server{
 		Addr:              ":443",
-		Handler:           pageHandler(),
+		Handler:           accessLog(handler, false),
 		TLSConfig:         tlsCfg,
 		ReadHeaderTimeout: 5 * time.Second,
 		ReadTimeout:       15 * time.Second,
@@ -117,7 +147,7 @@ func serveHTTPStandalone(domain string) {
 	// Port 80 only exists to redirect to HTTPS and satisfy ACME HTTP-01 challenges.
 	httpServer := &http.Server{
 		Addr:              ":80",
-		Handler:           certManager.HTTPHandler(http.HandlerFunc(redirectToHTTPS)),
+		Handler:           accessLog(certManager.HTTPHandler(http.HandlerFunc(redirectToHTTPS)), false),
 		ReadHeaderTimeout: 5 * time.Second,
 		ReadTimeout:       15 * time.Second,
 		WriteTimeout:      15 * time.Second,
@@ -133,16 +163,23 @@ func serveHTTPStandalone(domain string) {
 		}
 	}()
 
+	ln, err := net.Listen("tcp", httpsServer.Addr)
+	if err != nil {
+		log.Fatal("HTTPS server error:", err)
+	}
+	ready.Store(true)
+	go loadCertExpiry(tlsCfg, domain)
 	errCh := make(chan error, 1)
 	go func() {
 		log.Printf("HTTPS server listening on :443 — https://%s", domain)
-		errCh <- httpsServer.ListenAndServeTLS("", "")
+		errCh <- httpsServer.ServeTLS(ln, "", "")
 	}()
 
 	select {
 	case err := <-errCh:
 		log.Fatal("HTTPS server error:", err)
 	case <-quit:
+		ready.Store(false)
 		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 		defer cancel()
 		_ = httpsServer.Shutdown(ctx)
@@ -156,77 +193,134 @@ func assetHash(data []byte) string {
 	return hex.EncodeToString(sum[:4])
 }
 
-// versionedPage rewrites the embedded page's asset references to carry a
-// content hash (lib.js → lib.js?v=<hash>). lib.js and styles.css are served
-// with a 24h shared-cache lifetime, so CDNs (e.g. Cloudflare) hold on to them
-// across deploys; versioned URLs make every deploy an automatic cache miss,
-// so a fresh page can never run against a stale cached lib.js.
-func versionedPage() []byte {
-	page := htmlPage
-	page = bytes.ReplaceAll(page,
-		[]byte(`src="lib.js"`),
-		[]byte(`src="lib.js?v=`+assetHash(libJS)+`"`))
-	page = bytes.ReplaceAll(page,
-		[]byte(`href="styles.css"`),
-		[]byte(`href="styles.css?v=`+assetHash(cssPage)+`"`))
+// versionedPage rewrites the page's asset references to the versioned URLs
+// in urls, keyed by the reference as it appears in the page. A build maps
+// them to content-hashed files (lib.js → static/lib.<hash>.js); without one
+// the sources get a content hash query (lib.js → lib.js?v=<hash>). Either
+// way every deploy changes the URLs of changed assets, so CDNs (e.g.
+// Cloudflare) that hold on to them across deploys can never pair a fresh
+// page with a stale cached lib.js.
+func versionedPage(page []byte, urls map[string]string) []byte {
+	for _, name := range sortedKeys(urls) {
+		page = bytes.ReplaceAll(page,
+			[]byte(`"`+name+`"`),
+			[]byte(`"`+urls[name]+`"`))
+	}
 	return page
 }
 
 // pageHandler returns an http.Handler that serves the embedded HTML page with
-// security headers.
-func pageHandler() http.Handler {
-	page := versionedPage()
+// security headers, the samples, the web app manifest and service worker and
+// the JSON API. A nil store disables the leaderboard.
+func pageHandler(store *stats.Store, samples *sampleBanks) http.Handler {
+	build, err := loadBuild(buildFiles, "html/dist")
+	if err != nil {
+		log.Fatal("load build: ", err)
+	}
+	html, urls := htmlPage, map[string]string{
+		"lib.js":     "lib.js?v=" + assetHash(libJS),
+		"styles.css": "styles.css?v=" + assetHash(cssPage),
+	}
+	if build != nil {
+		html, urls = build.page, build.manifest
+	}
+	page := newAsset(versionedPage(html, urls), "text/html; charset=utf-8", "no-cache", textCompression)
+	css := newAsset(cssPage, "text/css; charset=utf-8", "public, max-age=86400", textCompression)
+	lib := newAsset(libJS, "application/javascript; charset=utf-8", "public, max-age=86400", textCompression)
+	favicon := newAsset(faviconBytes, "image/x-icon", "public, max-age=604800", binaryCompression)
+
 	mux := http.NewServeMux()
-	mux.HandleFunc("/styles.css", func(w http.ResponseWriter, r *http.Request) {
-		h := w.Header()
-		h.Set("Content-Type", "text/css; charset=utf-8")
-		h.Set("Cache-Control", "public, max-age=86400")
-		w.Write(cssPage)
-	})
-	mux.HandleFunc("/lib.js", func(w http.ResponseWriter, r *http.Request) {
-		h := w.Header()
-		h.Set("Content-Type", "application/javascript; charset=utf-8")
-		h.Set("Cache-Control", "public, max-age=86400")
-		w.Write(libJS)
-	})
-	mux.HandleFunc("/favicon.ico", func(w http.ResponseWriter, r *http.Request) {
-		h := w.Header()
-		h.Set("Content-Type", "image/x-icon")
-		h.Set("Cache-Control", "public, max-age=604800")
-		w.Write(faviconBytes)
-	})
-	mux.HandleFunc("/assets/sounds/strat/", func(w http.ResponseWriter, r *http.Request) {
-		// Only serve .wav files from the embedded sample bank; reject
-		// anything else (defense in depth, even though embed.FS is
-		// traversal-safe).
-		if !strings.HasSuffix(r.URL.Path, ".wav") {
-			http.NotFound(w, r)
-			return
-		}
-		data, err := stratSamples.ReadFile("html" + r.URL.Path)
+	registerAPI(mux, store)
+	mux.HandleFunc("/styles.css", css.serve)
+	mux.HandleFunc("/lib.js", lib.serve)
+	mux.HandleFunc("/favicon.ico", favicon.serve)
+	if build != nil {
+		mux.HandleFunc("/static/", func(w http.ResponseWriter, r *http.Request) {
+			a, ok := build.files[strings.TrimPrefix(r.URL.Path, "/")]
+			if !ok {
+				http.NotFound(w, r)
+				return
+			}
+			a.serve(w, r)
+		})
+	}
+	samples.register(mux)
+
+	// Installable web app: the manifest, its icons and the offline service
+	// worker. The worker is no-cache like the page, so browsers pick up a
+	// new version on the next visit.
+	webapp := newAsset(webManifest, "application/manifest+json", "no-cache", textCompression)
+	sw := newAsset(serviceWorker(page, samples, precacheURLs(build, urls)), "application/javascript; charset=utf-8", "no-cache", textCompression)
+	mux.HandleFunc("/manifest.webmanifest", webapp.serve)
+	mux.HandleFunc("/sw.js", sw.serve)
+	for _, size := range appIconSizes {
+		data, err := appIcon(faviconBytes, size)
 		if err != nil {
-			http.NotFound(w, r)
-			return
+			log.Fatal("app icon: ", err)
 		}
-		h := w.Header()
-		h.Set("Content-Type", "audio/wav")
-		h.Set("Cache-Control", "public, max-age=604800")
-		w.Write(data)
-	})
-	// Dev builds ship raw JSX and need Babel standalone, which requires
-	// 'unsafe-eval'. Production images run tools/precompile-jsx before
-	// `go build`, so the embedded page is plain JS and eval stays blocked.
-	scriptSrc := "script-src 'self' https://unpkg.com 'unsafe-inline'"
-	if bytes.Contains(htmlPage, []byte(`type="text/babel"`)) {
-		scriptSrc += " 'unsafe-eval'"
+		icon := newAsset(data, "image/png", "public, max-age=604800", storedCompression)
+		mux.HandleFunc(fmt.Sprintf("/icon-%d.png", size), icon.serve)
+	}
+
+	// The source page ships raw JSX that Babel standalone compiles (which
+	// requires 'unsafe-eval') and runs as inline scripts, and loads React,
+	// Babel and the fonts from CDNs. Production images run
+	// tools/precompile-jsx before `go build`: the built page has no inline
+	// script, eval stays blocked and everything comes from this origin. Any
+	// other inline block is allowed by its hash; a per-request nonce would
+	// defeat the precompressed page, its ETag and the service worker's copy.
+	scriptSrc := "script-src 'self'"
+	if bytes.Contains(html, []byte(`src="https://unpkg.com/`)) {
+		scriptSrc += " https://unpkg.com"
+	}
+	if bytes.Contains(html, []byte(`type="text/babel"`)) {
+		// Browsers ignore 'unsafe-inline' next to a hash, so there are none.
+		scriptSrc += " 'unsafe-inline' 'unsafe-eval'"
+	} else {
+		scriptSrc += cspHashes(html, "script")
+	}
+	styleSrc, fontSrc := "style-src 'self'"+cspHashes(html, "style"), "font-src 'self'"
+	if bytes.Contains(html, []byte(`href="https://fonts.googleapis.com/css`)) {
+		styleSrc += " https://fonts.googleapis.com"
+		fontSrc += " https://fonts.gstatic.com"
 	}
 	csp := "default-src 'none'; " +
 		scriptSrc + "; " +
-		"style-src 'self' https://fonts.googleapis.com 'unsafe-inline'; " +
-		"font-src https://fonts.gstatic.com; " +
+		styleSrc + "; " +
+		fontSrc + "; " +
+		"connect-src 'self'; " +
+		"img-src 'self'; " +
+		"manifest-src 'self'; " +
+		"worker-src 'self'; " +
+		"base-uri 'none'; " +
+		"form-action 'self'; " +
+		"frame-ancestors 'none';"
+
+	// The browser terminal page loads nothing from other origins.
+	termPage := newAsset(bytes.ReplaceAll(terminalPage,
+		[]byte(`src="terminal.js"`),
+		[]byte(`src="terminal.js?v=`+assetHash(terminalJS)+`"`)),
+		"text/html; charset=utf-8", "no-cache", textCompression)
+	termJS := newAsset(terminalJS, "application/javascript; charset=utf-8", "public, max-age=86400", textCompression)
+	termCSP := "default-src 'none'; " +
+		"script-src 'self'; " +
+		"style-src 'self'" + cspHashes(terminalPage, "style") + "; " +
 		"connect-src 'self'; " +
 		"img-src 'self'; " +
+		"base-uri 'none'; " +
+		"form-action 'none'; " +
 		"frame-ancestors 'none';"
+	mux.HandleFunc("/terminal.js", termJS.serve)
+	mux.HandleFunc("/terminal", func(w http.ResponseWriter, r *http.Request) {
+		h := w.Header()
+		h.Set("Strict-Transport-Security", "max-age=63072000; includeSubDomains; preload")
+		h.Set("X-Content-Type-Options", "nosniff")
+		h.Set("X-Frame-Options", "DENY")
+		h.Set("Referrer-Policy", "strict-origin-when-cross-origin")
+		h.Set("Content-Security-Policy", termCSP)
+		termPage.serve(w, r)
+	})
+	mux.Handle("/ws/terminal", newTerminalHub(store).handler())
 
 	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
 		h := w.Header()
@@ -235,13 +329,55 @@ func pageHandler() http.Handler {
 		h.Set("X-Frame-Options", "DENY")
 		h.Set("Referrer-Policy", "strict-origin-when-cross-origin")
 		h.Set("Content-Security-Policy", csp)
-		// The page must always revalidate: it carries the versioned asset
-		// URLs, so a cached copy would defeat the cache busting above.
-		h.Set("Cache-Control", "no-cache")
-		h.Set("Content-Type", "text/html; charset=utf-8")
-		w.Write(page)
+		// The page is no-cache and must always revalidate: it carries the
+		// versioned asset URLs, so a stale copy would defeat the cache
+		// busting above. Revalidation is a cheap 304 thanks to the ETag.
+		page.serve(w, r)
 	})
-	return mux
+	return instrumentHTTP(mux)
+}
+
+// inlineElements match the script and style elements of a page with their
+// attributes and content.
+var inlineElements = map[string]*regexp.Regexp{
+	"script": regexp.MustCompile(`(?is)<script\b([^>]*)>(.*?)</script\s*>`),
+	"style":  regexp.MustCompile(`(?is)<style\b([^>]*)>(.*?)</style\s*>`),
+}
+
+var (
+	srcAttr  = regexp.MustCompile(`(?i)\ssrc\s*=`)
+	typeAttr = regexp.MustCompile(`(?i)\stype\s*=\s*["']?([^"'\s>]+)`)
+)
+
+// cspHashes returns CSP source expressions (" 'sha256-…'") for the inline
+// <tag> elements of page, tag being "script" or "style", so a policy can
+// allow exactly those blocks instead of 'unsafe-inline'. Scripts the browser
+// does not run inline are skipped: <script src=…> and data blocks such as
+// <script type="text/babel">.
+func cspHashes(page []byte, tag string) string {
+	var b strings.Builder
+	for _, m := range inlineElements[tag].FindAllSubmatch(page, -1) {
+		if tag == "script" && !inlineScript(m[1]) {
+			continue
+		}
+		sum := sha256.Sum256(m[2])
+		b.WriteString(" 'sha256-" + base64.StdEncoding.EncodeToString(sum[:]) + "'")
+	}
+	return b.String()
+}
+
+// inlineScript reports whether a script element with these attributes runs
+// its own content as JavaScript.
+func inlineScript(attrs []byte) bool {
+	if srcAttr.Match(attrs) {
+		return false
+	}
+	m := typeAttr.FindSubmatch(attrs)
+	if m == nil {
+		return true
+	}
+	typ := strings.ToLower(string(m[1]))
+	return typ == "module" || strings.Contains(typ, "javascript") || strings.Contains(typ, "ecmascript")
 }
 
 func redirectToHTTPS(w http.ResponseWriter, r *http.Request) {
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"golang.org/x/net/html"
+)
+
+// inlineBlockHashes lists the CSP hashes a page needs, found with an HTML
+// tokenizer rather than cspHashes' regular expressions.
+func inlineBlockHashes(t *testing.T, page []byte) (scripts, styles []string) {
+	t.Helper()
+	z := html.NewTokenizer(bytes.NewReader(page))
+	for {
+		switch z.Next() {
+		case html.ErrorToken:
+			return scripts, styles
+		case html.StartTagToken:
+			tok := z.Token()
+			if tok.Data != "script" && tok.Data != "style" {
+				continue
+			}
+			runs := true
+			for _, a := range tok.Attr {
+				switch {
+				case a.Key == "src":
+					runs = false
+				case a.Key == "type" && tok.Data == "script":
+					runs = a.Val == "module" || strings.Contains(a.Val, "javascript")
+				}
+			}
+			if z.Next() != html.TextToken || !runs {
+				continue
+			}
+			sum := sha256.Sum256(z.Raw())
+			hash := "'sha256-" + base64.StdEncoding.EncodeToString(sum[:]) + "'"
+			if tok.Data == "script" {
+				scripts = append(scripts, hash)
+			} else {
+				styles = append(styles, hash)
+			}
+		}
+	}
+}
+
+// directive returns the source list of one CSP directive.
+func directive(csp, name string) string {
+	for _, d := range strings.Split(csp, ";") {
+		if rest, ok := strings.CutPrefix(strings.TrimSpace(d), name+" "); ok {
+			return rest
+		}
+	}
+	return ""
+}
+
+func TestCSPCoversInlineBlocks(t *testing.T) {
+	samples, err := openSampleBanks(sampleFiles, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := pageHandler(nil, samples)
+	build, err := loadBuild(buildFiles, "html/dist")
+	if err != nil {
+		t.Fatal(err)
+	}
+	page := htmlPage
+	if build != nil {
+		page = build.page
+	}
+
+	for _, tt := range []struct {
+		path string
+		page []byte
+	}{
+		{"/", page},
+		{"/terminal", terminalPage},
+	} {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest("GET", tt.path, nil))
+		csp := rec.Header().Get("Content-Security-Policy")
+		scripts, styles := inlineBlockHashes(t, tt.page)
+		scriptSrc := directive(csp, "script-src")
+		for _, hash := range scripts {
+			if !strings.Contains(scriptSrc, hash) && !strings.Contains(scriptSrc, "'unsafe-inline'") {
+				t.Errorf("%s: inline script %s not allowed by %q", tt.path, hash, scriptSrc)
+			}
+		}
+		for _, hash := range styles {
+			if !strings.Contains(directive(csp, "style-src"), hash) {
+				t.Errorf("%s: inline style %s not allowed by %q", tt.path, hash, csp)
+			}
+		}
+		if strings.Contains(scriptSrc, "'unsafe-inline'") && strings.Contains(scriptSrc, "'sha256-") {
+			t.Errorf("%s: a hash disables 'unsafe-inline' in %q", tt.path, scriptSrc)
+		}
+	}
+}
+
+func TestCSPHashes(t *testing.T) {
+	page := []byte(`<html><head>
+<style media="screen">body { color: red }</style>
+<STYLE>p{}</STYLE>
+<script src="lib.js"></script>
+<script type="text/babel">const x = <App/>;</script>
+<script type="application/json">{"a": 1}</script>
+<script type="module">import "./a.js";</script>
+<script defer>run()</script>
+<script>init()</script >
+</head></html>`)
+	scripts, styles := inlineBlockHashes(t, page)
+	if len(scripts) != 3 || len(styles) != 2 {
+		t.Fatalf("reference found %d scripts and %d styles, want 3 and 2", len(scripts), len(styles))
+	}
+	for tag, want := range map[string][]string{"script": scripts, "style": styles} {
+		if got := cspHashes(page, tag); got != " "+strings.Join(want, " ") {
+			t.Errorf("cspHashes(%s) = %q, want %q", tag, got, want)
+		}
+	}
+}
@@ -3,6 +3,7 @@ package main
 import (
 	"context"
 	"log"
+	"net"
 	"os"
 	"os/signal"
 	"syscall"
@@ -13,23 +14,60 @@ import (
 	"github.com/charmbracelet/wish"
 	wishbt "github.com/charmbracelet/wish/bubbletea"
 	"github.com/muesli/termenv"
+	gossh "golang.org/x/crypto/ssh"
 )
 
-func serveSSH() {
+func serveSSH(metricsAddr string) {
 	addr := "0.0.0.0:2222"
 	hostKey := "/opt/fremorizer/host_key"
 	// Fall back to a local path when running outside of the server environment.
 	if _, err := os.Stat(hostKey); os.IsNotExist(err) {
 		hostKey = "./host_key"
 	}
+	store := openServerStats()
+	ops := serveOps(metricsAddr, store)
+
+	races := newRaceHub()
+	sessions := newSessionRegistry()
 
 	s, err := wish.NewServer(
 		wish.WithAddress(addr),
 		wish.WithHostKeyPath(hostKey),
+		// Accept every client. Public keys are only used to tie stats to a
+		// player; clients without a key fall back to keyboard-interactive and
+		// play anonymously.
+		wish.WithPublicKeyAuth(func(ssh.Context, ssh.PublicKey) bool { return true }),
+		wish.WithKeyboardInteractiveAuth(func(ssh.Context, gossh.KeyboardInteractiveChallenge) bool { return true }),
 		wish.WithMiddleware(
 			wishbt.MiddlewareWithColorProfile(func(sess ssh.Session) (tea.Model, []tea.ProgramOption) {
-				return initialModel(wishbt.MakeRenderer(sess)), []tea.ProgramOption{tea.WithAltScreen()}
+				if args := sess.Command(); len(args) > 0 && args[0] == "watch" {
+					code := ""
+					if len(args) > 1 {
+						code = args[1]
+					}
+					sm := newSpectatorModel(wishbt.MakeRenderer(sess), sessions, code)
+					go func() {
+						<-sess.Context().Done()
+						sm.unwatch()
+					}()
+					return sm, []tea.ProgramOption{tea.WithAltScreen()}
+				}
+
+				m := initialModel(wishbt.MakeRenderer(sess))
+				name := displayName(sess.User())
+				m.stats, m.player, m.playerName = store, sshPlayerID(sess), name
+				m.race = races.connect(name)
+				m.shares, m.shareOwner = sessions, sess.Context().SessionID()
+				go func() {
+					<-sess.Context().Done()
+					m.race.close()
+					sessions.closeOwner(m.shareOwner)
+				}()
+				return m, []tea.ProgramOption{tea.WithAltScreen()}
 			}, termenv.TrueColor),
+			commandMiddleware(store),
+			sessionMetricsMiddleware(),
+			sessionLogMiddleware(),
 		),
 	)
 	if err != nil {
@@ -40,8 +78,13 @@ func serveSSH() {
 	signal.Notify(quit, os.Interrupt, syscall.SIGTERM)
 	log.Printf("SSH server listening on %s — connect with: ssh -p 2222 <host>", addr)
 
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		log.Fatal("SSH server error:", err)
+	}
+	ready.Store(true)
 	errCh := make(chan error, 1)
-	go func() { errCh <- s.ListenAndServe() }()
+	go func() { errCh <- s.Serve(ln) }()
 
 	select {
 	case err := <-errCh:
@@ -50,6 +93,10 @@ func serveSSH() {
 	}
 
 	log.Println("Shutting down...")
+	ready.Store(false)
+	if ops != nil {
+		defer ops.Close()
+	}
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 	if err := s.Shutdown(ctx); err != nil {
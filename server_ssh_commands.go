@@ -0,0 +1,424 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"math/rand"
+	"slices"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/charmbracelet/ssh"
+	"github.com/charmbracelet/wish"
+	"github.com/funkymcb/fremorizer/game"
+	"github.com/funkymcb/fremorizer/stats"
+	gossh "golang.org/x/crypto/ssh"
+)
+
+const sshCommandUsage = `usage: ssh -p 2222 <host> [command]
+
+Without a command the interactive TUI starts. Commands:
+  stats [--json]       summary of your finished games
+  export               all your results as JSON
+  quiz [--count N]     line-based note quiz (also: --instrument, --frets, --json,
+                       --daily for today's challenge sequence)
+  leaderboard          best times for one setup (--mode single|chords,
+                       --instrument, --tuning E-A-D-G-B-E, --frets, --chords N,
+                       --difficulty, --team, --limit N, --json)
+  team [NAME]          show your team, or join NAME (--clear to leave)
+  token                a token that links the web page to your stats
+  watch CODE           watch a player's session live (needs ssh -t)
+  help                 show this message
+
+Stats are tied to your SSH public key.
+`
+
+// sshPlayerID derives the stats player ID from the session's public key. It
+// returns "" for sessions authenticated without a key.
+func sshPlayerID(sess ssh.Session) string {
+	key := sess.PublicKey()
+	if key == nil {
+		return ""
+	}
+	return stats.PlayerID(gossh.FingerprintSHA256(key))
+}
+
+// commandMiddleware answers non-interactive invocations such as
+// `ssh -p 2222 host stats` with plain text or JSON. Sessions without a
+// command fall through to the TUI, so it must be passed to
+// wish.WithMiddleware after the bubbletea middleware (i.e. run before it).
+func commandMiddleware(store *stats.Store) wish.Middleware {
+	return func(next ssh.Handler) ssh.Handler {
+		return func(sess ssh.Session) {
+			args := sess.Command()
+			// watch is interactive and runs in the TUI middleware.
+			if len(args) == 0 || args[0] == "watch" {
+				next(sess)
+				return
+			}
+			code := runSSHCommand(args, sess, sess, sess.Stderr(), store, sshPlayerID(sess))
+			_ = sess.Exit(code)
+		}
+	}
+}
+
+// runSSHCommand executes a single command and returns its exit status.
+func runSSHCommand(args []string, in io.Reader, out, errOut io.Writer, store *stats.Store, player string) int {
+	switch args[0] {
+	case "stats":
+		return cmdStats(args[1:], out, errOut, store, player)
+	case "export":
+		return cmdExport(out, errOut, store, player)
+	case "quiz":
+		return cmdQuiz(args[1:], in, out, errOut, store, player)
+	case "leaderboard":
+		return cmdLeaderboard(args[1:], out, errOut, store)
+	case "team":
+		return cmdTeam(args[1:], out, errOut, store, player)
+	case "token":
+		return cmdToken(out, errOut, store, player)
+	case "help", "-h", "--help":
+		fmt.Fprint(out, sshCommandUsage)
+		return 0
+	default:
+		fmt.Fprintf(errOut, "unknown command %q\n\n%s", args[0], sshCommandUsage)
+		return 2
+	}
+}
+
+// requirePlayer reports whether per-player data is available, explaining why
+// on errOut if not.
+func requirePlayer(errOut io.Writer, store *stats.Store, player string) bool {
+	if store == nil {
+		fmt.Fprintln(errOut, "stats are not available on this server")
+		return false
+	}
+	if player == "" {
+		fmt.Fprintln(errOut, "no public key: connect with an SSH key to track stats")
+		return false
+	}
+	return true
+}
+
+func loadPlayerResults(errOut io.Writer, store *stats.Store, player string) ([]stats.Result, bool) {
+	if !requirePlayer(errOut, store, player) {
+		return nil, false
+	}
+	results, err := store.Results(player)
+	if err != nil {
+		fmt.Fprintf(errOut, "error: %v\n", err)
+		return nil, false
+	}
+	return results, true
+}
+
+func cmdStats(args []string, out, errOut io.Writer, store *stats.Store, player string) int {
+	fs := flag.NewFlagSet("stats", flag.ContinueOnError)
+	fs.SetOutput(errOut)
+	asJSON := fs.Bool("json", false, "print JSON instead of a table")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	results, ok := loadPlayerResults(errOut, store, player)
+	if !ok {
+		return 1
+	}
+	summary := stats.Summarize(results)
+
+	if *asJSON {
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(summary); err != nil {
+			return 1
+		}
+		return 0
+	}
+
+	if len(summary) == 0 {
+		fmt.Fprintln(out, "No finished games yet.")
+		return 0
+	}
+	tw := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "MODE\tGAMES\tBEST\tAVG\tBEST/ITEM\tLAST PLAYED")
+	for _, s := range summary {
+		fmt.Fprintf(tw, "%s\t%d\t%s\t%s\t%.1fs\t%s\n",
+			s.Mode, s.Games,
+			formatDuration(secondsToDuration(s.BestSeconds)),
+			formatDuration(secondsToDuration(s.AvgSeconds)),
+			s.BestAvgItem,
+			time.UnixMilli(s.LastPlayed).UTC().Format("2006-01-02 15:04"))
+	}
+	tw.Flush()
+	return 0
+}
+
+func cmdExport(out, errOut io.Writer, store *stats.Store, player string) int {
+	results, ok := loadPlayerResults(errOut, store, player)
+	if !ok {
+		return 1
+	}
+	if results == nil {
+		results = []stats.Result{}
+	}
+	enc := json.NewEncoder(out)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(results); err != nil {
+		return 1
+	}
+	return 0
+}
+
+// quizAnswer is one question of a `quiz` run, as reported by --json.
+type quizAnswer struct {
+	String  int    `json:"string"` // 1 = highest string
+	Fret    int    `json:"fret"`
+	Note    string `json:"note"`
+	Answer  string `json:"answer"`
+	Correct bool   `json:"correct"`
+}
+
+// cmdQuiz asks for note names line by line on stdin. It needs no terminal,
+// so it also works from scripts: `printf 'C\nD\n' | ssh host quiz --count 2`.
+func cmdQuiz(args []string, in io.Reader, out, errOut io.Writer, store *stats.Store, player string) int {
+	fs := flag.NewFlagSet("quiz", flag.ContinueOnError)
+	fs.SetOutput(errOut)
+	count := fs.Int("count", 10, "number of questions")
+	instrType := fs.String("instrument", "guitar", "guitar, bass or ukulele")
+	frets := fs.Int("frets", 12, "number of frets (12-24)")
+	asJSON := fs.Bool("json", false, "print the result as JSON")
+	daily := fs.Bool("daily", false, "ask today's daily challenge notes (standard guitar, 12 frets)")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if *count < 1 {
+		fmt.Fprintln(errOut, "--count must be at least 1")
+		return 2
+	}
+
+	tuning := defaultTuning(*instrType, defaultStringCount(*instrType))
+	var rng *rand.Rand
+	if *daily {
+		*instrType, tuning, *frets = dailyInstrument, dailyTuning(), dailyFrets
+		rng = rand.New(rand.NewSource(game.DailySeed(time.Now())))
+	}
+	inst, err := buildInstrument(*instrType, tuning, *frets)
+	if err != nil {
+		fmt.Fprintf(errOut, "error: %v\n", err)
+		return 2
+	}
+
+	g := game.NewSingleNoteGame(inst, rng)
+	metrics.gameStarted("quiz")
+	lines := bufio.NewScanner(in)
+	var answers []quizAnswer
+	start := time.Now()
+
+	for len(answers) < *count && !g.IsGameOver() {
+		si, fret := g.CurrentPosition()
+		open := strings.Split(inst.Strings[si].Notes[0].Name, "/")[0]
+		note := g.CurrentNoteName()
+		if !*asJSON {
+			fmt.Fprintf(out, "%d/%d  string %d (%s), fret %d: ", len(answers)+1, *count, si+1, open, fret)
+		}
+		if !lines.Scan() {
+			if !*asJSON {
+				fmt.Fprintln(out)
+			}
+			break
+		}
+		answer := strings.TrimSpace(lines.Text())
+		correct := g.CheckAnswer(answer)
+		if !*asJSON {
+			if correct {
+				fmt.Fprintln(out, "correct")
+			} else {
+				fmt.Fprintf(out, "wrong — it was %s\n", note)
+			}
+		}
+		answers = append(answers, quizAnswer{String: si + 1, Fret: fret, Note: note, Answer: answer, Correct: correct})
+		g.RevealNote(correct)
+		_ = g.Next()
+	}
+
+	elapsed := time.Since(start)
+	score := 0
+	for _, a := range answers {
+		if a.Correct {
+			score++
+		}
+	}
+
+	// Only complete runs count towards stats; an aborted quiz would skew
+	// averages. A run is complete when the game is over, which can be before
+	// --count questions, or when input lasted for all of them.
+	complete := g.IsGameOver() || len(answers) == *count
+	if complete {
+		metrics.gameCompleted("quiz")
+	}
+	if store != nil && player != "" && complete {
+		r := stats.NewResult("quiz", inst.Type, inst.Tuning, inst.Frets, elapsed, len(answers))
+		if err := store.Add(player, r); err != nil {
+			fmt.Fprintf(errOut, "warning: could not save result: %v\n", err)
+		}
+	}
+
+	if *asJSON {
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		err := enc.Encode(struct {
+			Score       int          `json:"score"`
+			Questions   int          `json:"questions"`
+			TimeSeconds float64      `json:"timeSeconds"`
+			Answers     []quizAnswer `json:"answers"`
+		}{score, len(answers), elapsed.Seconds(), answers})
+		if err != nil {
+			return 1
+		}
+		return 0
+	}
+	fmt.Fprintf(out, "Score: %d/%d  Time: %s\n", score, len(answers), formatDuration(elapsed))
+	return 0
+}
+
+// cmdLeaderboard prints the ranked board of one configuration. Results are
+// only comparable within the same mode, instrument, tuning and fret count,
+// and for chords the same chord count and difficulty.
+func cmdLeaderboard(args []string, out, errOut io.Writer, store *stats.Store) int {
+	fs := flag.NewFlagSet("leaderboard", flag.ContinueOnError)
+	fs.SetOutput(errOut)
+	mode := fs.String("mode", "single", "single or chords")
+	instrType := fs.String("instrument", "guitar", "guitar, bass or ukulele")
+	tuning := fs.String("tuning", "", "tuning, e.g. E-A-D-G-B-E (default: standard)")
+	frets := fs.Int("frets", 12, "number of frets (12-24)")
+	chordCount := fs.Int("chords", 20, "chords per run (mode chords)")
+	difficulty := fs.String("difficulty", "easy", "easy, medium or hard (mode chords)")
+	team := fs.String("team", "", "only show members of this team")
+	limit := fs.Int("limit", 10, "number of entries (0 = all)")
+	asJSON := fs.Bool("json", false, "print JSON instead of a table")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if !slices.Contains(stats.LeaderboardModes, *mode) {
+		fmt.Fprintf(errOut, "--mode must be one of: %s\n", strings.Join(stats.LeaderboardModes, ", "))
+		return 2
+	}
+	if !slices.Contains(chordDifficulties, *difficulty) {
+		fmt.Fprintf(errOut, "--difficulty must be one of: %s\n", strings.Join(chordDifficulties, ", "))
+		return 2
+	}
+	if store == nil {
+		fmt.Fprintln(errOut, "stats are not available on this server")
+		return 1
+	}
+
+	tun := defaultTuning(*instrType, defaultStringCount(*instrType))
+	if *tuning != "" {
+		tun = strings.Split(*tuning, "-")
+	}
+	inst, err := buildInstrument(*instrType, tun, *frets)
+	if err != nil {
+		fmt.Fprintf(errOut, "error: %v\n", err)
+		return 2
+	}
+
+	key := stats.ConfigKey(*mode, inst.Type, inst.Tuning, inst.Frets, *chordCount, *difficulty)
+	entries, err := store.Leaderboard(key, *team, *limit)
+	if err != nil {
+		fmt.Fprintf(errOut, "error: %v\n", err)
+		return 1
+	}
+
+	if *asJSON {
+		if entries == nil {
+			entries = []stats.Entry{}
+		}
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(entries); err != nil {
+			return 1
+		}
+		return 0
+	}
+
+	fmt.Fprintf(out, "%s — %s %s, %d frets\n\n", *mode, inst.Type, strings.Join(inst.Tuning, "-"), inst.Frets)
+	if len(entries) == 0 {
+		fmt.Fprintln(out, "No results for this setup yet.")
+		return 0
+	}
+	tw := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "RANK\tPLAYER\tTEAM\tTIME\tACCURACY\tDATE")
+	for _, e := range entries {
+		fmt.Fprintf(tw, "%d\t%s\t%s\t%s\t%.1f%%\t%s\n",
+			e.Rank, displayName(e.Player), displayName(e.Team),
+			formatDuration(secondsToDuration(e.TimeSeconds)),
+			e.Accuracy*100,
+			time.UnixMilli(e.Timestamp).UTC().Format("2006-01-02"))
+	}
+	tw.Flush()
+	return 0
+}
+
+// cmdTeam shows or changes the player's team.
+func cmdTeam(args []string, out, errOut io.Writer, store *stats.Store, player string) int {
+	fs := flag.NewFlagSet("team", flag.ContinueOnError)
+	fs.SetOutput(errOut)
+	leave := fs.Bool("clear", false, "leave your current team")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if !requirePlayer(errOut, store, player) {
+		return 1
+	}
+
+	name := strings.Join(fs.Args(), " ")
+	switch {
+	case *leave:
+		if err := store.SetTeam(player, ""); err != nil {
+			fmt.Fprintf(errOut, "error: %v\n", err)
+			return 1
+		}
+		fmt.Fprintln(out, "You left your team.")
+	case name != "":
+		if err := store.SetTeam(player, name); err != nil {
+			fmt.Fprintf(errOut, "error: %v\n", err)
+			return 1
+		}
+		fmt.Fprintf(out, "You are now in team %q.\n", strings.TrimSpace(name))
+	default:
+		team, err := store.Team(player)
+		if err != nil {
+			fmt.Fprintf(errOut, "error: %v\n", err)
+			return 1
+		}
+		if team == "" {
+			fmt.Fprintln(out, "You are not in a team. Join one with: team NAME")
+		} else {
+			fmt.Fprintln(out, team)
+		}
+	}
+	return 0
+}
+
+// cmdToken prints a new web token for the player. Entered on the web page's
+// stats screen, it makes browser results count towards the same stats.
+func cmdToken(out, errOut io.Writer, store *stats.Store, player string) int {
+	if !requirePlayer(errOut, store, player) {
+		return 1
+	}
+	token, err := store.NewToken(player)
+	if err != nil {
+		fmt.Fprintf(errOut, "error: %v\n", err)
+		return 1
+	}
+	fmt.Fprintln(out, token)
+	return 0
+}
+
+func secondsToDuration(s float64) time.Duration {
+	return time.Duration(s * float64(time.Second))
+}
@@ -0,0 +1,236 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/funkymcb/fremorizer/stats"
+)
+
+// playedOn is 2025-03-04 05:06 UTC in unix milliseconds.
+var playedOn = time.Date(2025, 3, 4, 5, 6, 0, 0, time.UTC).UnixMilli()
+
+// storeWithResults is a store where alice has played two single note games
+// and one chord game.
+func storeWithResults(t *testing.T) *stats.Store {
+	t.Helper()
+	store, err := stats.Open(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	std := defaultTuning("guitar", 6)
+	for _, r := range []stats.Result{
+		stats.NewResult("single", "guitar", std, 12, 90*time.Second, 30),
+		stats.NewResult("single", "guitar", std, 12, 150*time.Second, 30),
+		stats.NewResult("chords", "guitar", std, 12, 45*time.Second, 20),
+	} {
+		r.Timestamp = playedOn
+		if err := store.Add("alice", r); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return store
+}
+
+// run runs an SSH command and returns its exit status and output.
+func run(store *stats.Store, player string, args ...string) (int, string, string) {
+	var out, errOut bytes.Buffer
+	code := runSSHCommand(args, strings.NewReader(""), &out, &errOut, store, player)
+	return code, out.String(), errOut.String()
+}
+
+func TestCmdStats(t *testing.T) {
+	store := storeWithResults(t)
+	code, out, _ := run(store, "alice", "stats")
+	want := "MODE    GAMES  BEST    AVG     BEST/ITEM  LAST PLAYED\n" +
+		"chords  1      45s     45s     2.2s       2025-03-04 05:06\n" +
+		"single  2      1m 30s  2m 00s  3.0s       2025-03-04 05:06\n"
+	if code != 0 || out != want {
+		t.Errorf("stats = %d:\n%s\nwant:\n%s", code, out, want)
+	}
+
+	code, out, _ = run(store, "alice", "stats", "--json")
+	var summary []stats.ModeSummary
+	if err := json.Unmarshal([]byte(out), &summary); err != nil || code != 0 || len(summary) != 2 || summary[1].Games != 2 {
+		t.Errorf("stats --json = %d %s (%v)", code, out, err)
+	}
+
+	// A player without results, in an empty store or not, has nothing yet.
+	for _, s := range []*stats.Store{store, storeWithResults(t)} {
+		if code, out, _ := run(s, "bob", "stats"); code != 0 || out != "No finished games yet.\n" {
+			t.Errorf("stats for an unknown player = %d %q", code, out)
+		}
+		if code, out, _ := run(s, "bob", "stats", "--json"); code != 0 || out != "[]\n" {
+			t.Errorf("stats --json for an unknown player = %d %q", code, out)
+		}
+	}
+}
+
+func TestCmdExport(t *testing.T) {
+	store := storeWithResults(t)
+	code, out, _ := run(store, "alice", "export")
+	var results []stats.Result
+	if err := json.Unmarshal([]byte(out), &results); err != nil || code != 0 {
+		t.Fatalf("export = %d %s (%v)", code, out, err)
+	}
+	if len(results) != 3 || results[0].Mode != "single" || results[0].TimeSeconds != 90 || results[2].Mode != "chords" {
+		t.Errorf("exported %+v", results)
+	}
+	if !strings.HasPrefix(out, "[\n  {\n    \"mode\": \"single\",") {
+		t.Errorf("export is not indented JSON:\n%s", out)
+	}
+	if code, out, _ := run(store, "bob", "export"); code != 0 || out != "[]\n" {
+		t.Errorf("export for an unknown player = %d %q, want []", code, out)
+	}
+}
+
+func TestPlayerCommandsNeedAPlayer(t *testing.T) {
+	store := storeWithResults(t)
+	for _, cmd := range []string{"stats", "export"} {
+		for _, tc := range []struct {
+			store  *stats.Store
+			player string
+			want   string
+		}{
+			{nil, "alice", "stats are not available on this server\n"},
+			{store, "", "no public key: connect with an SSH key to track stats\n"},
+			{store, "../alice", "error: "},
+		} {
+			code, out, errOut := run(tc.store, tc.player, cmd)
+			if code != 1 || out != "" || !strings.HasPrefix(errOut, tc.want) {
+				t.Errorf("%s as %q: %d %q %q, want status 1 and %q", cmd, tc.player, code, out, errOut, tc.want)
+			}
+		}
+	}
+}
+
+func TestCmdLeaderboard(t *testing.T) {
+	store, err := stats.Open(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	code, out, _ := run(store, "", "leaderboard")
+	if want := "single — guitar E-A-D-G-B-E, 12 frets\n\nNo results for this setup yet.\n"; code != 0 || out != want {
+		t.Errorf("empty leaderboard = %d %q, want %q", code, out, want)
+	}
+	if code, out, _ := run(store, "", "leaderboard", "--json"); code != 0 || out != "[]\n" {
+		t.Errorf("empty leaderboard --json = %d %q", code, out)
+	}
+
+	std := defaultTuning("guitar", 6)
+	for i, name := range []string{"slow\x1b[2J", "fast", "bass player"} {
+		e := stats.Entry{PlayerID: "p" + strconv.Itoa(i), Player: name, Result: stats.NewResult("single", "guitar", std, 12, time.Duration(100-40*i)*time.Second, 30)}
+		if name == "bass player" {
+			e.Instrument, e.Tuning = "bass", defaultTuning("bass", 4)
+		}
+		e.Timestamp, e.Accuracy = playedOn, 0.9
+		if err := store.Submit(e); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := store.SetTeam("p1", "band"); err != nil {
+		t.Fatal(err)
+	}
+
+	code, out, _ = run(store, "", "leaderboard")
+	want := "single — guitar E-A-D-G-B-E, 12 frets\n\n" +
+		"RANK  PLAYER   TEAM  TIME    ACCURACY  DATE\n" +
+		"1     fast     band  1m 00s  90.0%     2025-03-04\n" +
+		"2     slow[2J        1m 40s  90.0%     2025-03-04\n"
+	if code != 0 || out != want {
+		t.Errorf("leaderboard = %d:\n%s\nwant:\n%s", code, out, want)
+	}
+
+	for _, tc := range []struct {
+		args []string
+		want string // players, best first
+	}{
+		{[]string{"--limit", "1"}, "fast"},
+		{[]string{"--team", "BAND"}, "fast"},
+		{[]string{"--team", "nobody"}, ""},
+		{[]string{"--instrument", "bass"}, "bass player"},
+		{[]string{"--mode", "chords"}, ""},
+		{[]string{"--frets", "24"}, ""},
+	} {
+		code, out, errOut := run(store, "", append([]string{"leaderboard", "--json"}, tc.args...)...)
+		var entries []stats.Entry
+		if err := json.Unmarshal([]byte(out), &entries); err != nil || code != 0 {
+			t.Errorf("%q: %d %s %s", tc.args, code, out, errOut)
+			continue
+		}
+		var names []string
+		for _, e := range entries {
+			names = append(names, e.Player)
+		}
+		if got := strings.Join(names, ","); got != tc.want {
+			t.Errorf("%q: players %q, want %q", tc.args, got, tc.want)
+		}
+	}
+
+	for _, args := range [][]string{{"--mode", "fretset"}, {"--difficulty", "extreme"}, {"--instrument", "banjo"}, {"--frets", "5"}} {
+		if code, _, errOut := run(store, "", append([]string{"leaderboard"}, args...)...); code != 2 || errOut == "" {
+			t.Errorf("%q: status %d, want 2 with a message", args, code)
+		}
+	}
+	if code, _, errOut := run(nil, "", "leaderboard"); code != 1 || errOut != "stats are not available on this server\n" {
+		t.Errorf("leaderboard without a store: %d %q", code, errOut)
+	}
+}
+
+// quizPlayer answers the quiz's prompts correctly, reading each question
+// from what the quiz has printed so far.
+type quizPlayer struct {
+	out  *bytes.Buffer
+	inst string
+}
+
+var quizPrompt = regexp.MustCompile(`string (\d+) \(\w+\), fret (\d+): $`)
+
+func (p quizPlayer) Read(b []byte) (int, error) {
+	m := quizPrompt.FindStringSubmatch(p.out.String())
+	if m == nil {
+		return 0, fmt.Errorf("no question in %q", p.out.String())
+	}
+	si, _ := strconv.Atoi(m[1])
+	fret, _ := strconv.Atoi(m[2])
+	inst, err := buildInstrument(p.inst, defaultTuning(p.inst, defaultStringCount(p.inst)), 12)
+	if err != nil {
+		return 0, err
+	}
+	name, _, _ := strings.Cut(inst.Strings[si-1].Notes[fret].Name, "/")
+	return copy(b, name+"\n"), nil
+}
+
+func TestQuizSavesFinishedGames(t *testing.T) {
+	store, err := stats.Open(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Answering every note ends the game before --count questions.
+	var out bytes.Buffer
+	code := cmdQuiz([]string{"--count", "500", "--instrument", "ukulele"}, quizPlayer{&out, "ukulele"}, &out, &out, store, "alice")
+	if code != 0 || !strings.Contains(out.String(), "Score: 48/48") {
+		t.Fatalf("quiz = %d, output ends %q", code, out.String()[max(0, out.Len()-200):])
+	}
+	results, _ := store.Results("alice")
+	if len(results) != 1 || results[0].Mode != "quiz" || results[0].TotalItems != 48 || results[0].Instrument != "ukulele" {
+		t.Errorf("saved %+v, want the finished game", results)
+	}
+
+	// A run stopped by the end of input before --count is not saved.
+	cmdQuiz([]string{"--count", "3"}, strings.NewReader("C\nD\n"), &out, &out, store, "alice")
+	if results, _ := store.Results("alice"); len(results) != 1 {
+		t.Errorf("aborted quiz saved: %d results", len(results))
+	}
+	cmdQuiz([]string{"--count", "2"}, strings.NewReader("C\nD\n"), &out, &out, store, "alice")
+	if results, _ := store.Results("alice"); len(results) != 2 || results[1].TotalItems != 2 {
+		t.Errorf("complete quiz not saved: %+v", results)
+	}
+}
@@ -0,0 +1,211 @@
+package main
+
+import (
+	"crypto/rand"
+	_ "embed"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"sync/atomic"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/funkymcb/fremorizer/stats"
+	"github.com/muesli/termenv"
+	"golang.org/x/net/websocket"
+)
+
+// ── browser terminal ──────────────────────────────────────────────────────────
+//
+// /terminal serves a small terminal emulator page that runs the TUI over a
+// WebSocket (/ws/terminal), for players whose network blocks the SSH port.
+//
+// Protocol: the page first sends a JSON start message with its size, the
+// stats token if the web page has one, and the player name and spectator
+// code from /terminal?name=&watch=. After that, text frames starting with
+// "i" carry keyboard input and frames starting with "r" a new size as JSON.
+// The server sends the TUI's output as binary frames.
+//
+// Browser terminals race and share sessions with each other like SSH
+// sessions do; the two servers run as separate processes, so not across.
+
+//go:embed html/terminal.html
+var terminalPage []byte
+
+//go:embed html/terminal.js
+var terminalJS []byte
+
+const (
+	maxTerminals     = 100              // concurrent browser terminals
+	terminalIdle     = 30 * time.Minute // input timeout
+	maxTerminalFrame = 64 << 10
+)
+
+type terminalSize struct {
+	Cols int `json:"cols"`
+	Rows int `json:"rows"`
+}
+
+type terminalStart struct {
+	terminalSize
+	Token string `json:"token"`
+	Name  string `json:"name"`
+	Watch string `json:"watch"` // spectator code; watch instead of play
+}
+
+// terminalHub is the state browser terminals share.
+type terminalHub struct {
+	store    *stats.Store
+	races    *raceHub
+	sessions *sessionRegistry
+	nextID   atomic.Int64 // share owner IDs
+	active   atomic.Int32 // open terminals
+}
+
+func newTerminalHub(store *stats.Store) *terminalHub {
+	return &terminalHub{store: store, races: newRaceHub(), sessions: newSessionRegistry()}
+}
+
+// handler returns the handler of the WebSocket endpoint.
+func (h *terminalHub) handler() http.Handler {
+	ws := websocket.Server{
+		Handshake: sameOriginHandshake,
+		Handler: func(conn *websocket.Conn) {
+			defer conn.Close()
+			if h.active.Add(1) > maxTerminals {
+				h.active.Add(-1)
+				_ = websocket.Message.Send(conn, []byte("Too many browser terminals are open — try again later, or use SSH.\r\n"))
+				return
+			}
+			defer h.active.Add(-1)
+			h.run(conn)
+		},
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// The server's read and write timeouts are meant for plain requests
+		// and would cut the connection after a few seconds; idle sessions
+		// are timed out by runTerminal instead.
+		rc := http.NewResponseController(w)
+		_ = rc.SetReadDeadline(time.Time{})
+		_ = rc.SetWriteDeadline(time.Time{})
+		ws.ServeHTTP(w, r)
+	})
+}
+
+// sameOriginHandshake rejects WebSocket connections opened by other sites.
+func sameOriginHandshake(cfg *websocket.Config, r *http.Request) error {
+	origin, err := url.Parse(r.Header.Get("Origin"))
+	if err != nil || origin.Host != r.Host {
+		return errors.New("cross-origin WebSocket request")
+	}
+	cfg.Origin = origin
+	return nil
+}
+
+// run runs one TUI session over conn until either side quits.
+func (h *terminalHub) run(conn *websocket.Conn) {
+	conn.MaxPayloadBytes = maxTerminalFrame
+	_ = conn.SetReadDeadline(time.Now().Add(time.Minute))
+	var raw []byte
+	var start terminalStart
+	if err := websocket.Message.Receive(conn, &raw); err != nil || json.Unmarshal(raw, &start) != nil {
+		return
+	}
+
+	out := &terminalWriter{conn: conn}
+	renderer := lipgloss.NewRenderer(out, termenv.WithProfile(termenv.TrueColor))
+	renderer.SetHasDarkBackground(true)
+	var m tea.Model
+	if start.Watch != "" {
+		sm := newSpectatorModel(renderer, h.sessions, start.Watch)
+		defer sm.unwatch()
+		m = sm
+	} else {
+		gm := initialModel(renderer)
+		name := displayName(start.Name)
+		if name == "" {
+			name = guestName()
+		}
+		gm.playerName = name
+		if h.store != nil {
+			// A web token links the terminal to the same stats as the page.
+			if player, err := h.store.TokenPlayer(start.Token); err == nil && player != "" {
+				gm.stats, gm.player = h.store, player
+			}
+		}
+		gm.race = h.races.connect(name)
+		gm.shares, gm.shareOwner = h.sessions, fmt.Sprintf("terminal-%d", h.nextID.Add(1))
+		defer func() {
+			gm.race.close()
+			h.sessions.closeOwner(gm.shareOwner)
+		}()
+		m = gm
+	}
+
+	in, inW := io.Pipe()
+	p := tea.NewProgram(m,
+		tea.WithInput(in),
+		tea.WithOutput(out),
+		tea.WithAltScreen(),
+		tea.WithoutSignalHandler(),
+	)
+
+	go func() {
+		defer inW.Close()
+		defer p.Quit()
+		for {
+			_ = conn.SetReadDeadline(time.Now().Add(terminalIdle))
+			var msg string
+			if err := websocket.Message.Receive(conn, &msg); err != nil || msg == "" {
+				return
+			}
+			switch msg[0] {
+			case 'i':
+				if _, err := inW.Write([]byte(msg[1:])); err != nil {
+					return
+				}
+			case 'r':
+				var size terminalSize
+				if json.Unmarshal([]byte(msg[1:]), &size) == nil {
+					p.Send(tea.WindowSizeMsg{Width: size.Cols, Height: size.Rows})
+				}
+			}
+		}
+	}()
+
+	if start.Cols > 0 && start.Rows > 0 {
+		go p.Send(tea.WindowSizeMsg{Width: start.Cols, Height: start.Rows})
+	}
+	if _, err := p.Run(); err != nil {
+		log.Printf("browser terminal: %v", err)
+	}
+	p.Kill()
+	in.Close()
+}
+
+// terminalWriter sends each write as one binary WebSocket frame, so the page
+// can decode UTF-8 sequences split across writes.
+type terminalWriter struct {
+	conn *websocket.Conn
+}
+
+func (w *terminalWriter) Write(b []byte) (int, error) {
+	_ = w.conn.SetWriteDeadline(time.Now().Add(10 * time.Second))
+	if err := websocket.Message.Send(w.conn, b); err != nil {
+		return 0, err
+	}
+	return len(b), nil
+}
+
+// guestName names a browser terminal player who did not pick a name.
+func guestName() string {
+	b := make([]byte, 2)
+	_, _ = rand.Read(b)
+	return "guest-" + hex.EncodeToString(b)
+}
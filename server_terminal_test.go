@@ -0,0 +1,165 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"golang.org/x/net/websocket"
+
+	"github.com/funkymcb/fremorizer/stats"
+)
+
+// browserTerminal is a test client of /ws/terminal that collects the output.
+type browserTerminal struct {
+	t    *testing.T
+	conn *websocket.Conn
+
+	mu  sync.Mutex
+	out strings.Builder
+}
+
+// openTerminal connects to srv and sends the start message.
+func openTerminal(t *testing.T, srv *httptest.Server, start terminalStart) *browserTerminal {
+	t.Helper()
+	conn, err := websocket.Dial("ws"+strings.TrimPrefix(srv.URL, "http"), "", srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bt := &browserTerminal{t: t, conn: conn}
+	t.Cleanup(func() { conn.Close() })
+	data, _ := json.Marshal(start)
+	if err := websocket.Message.Send(conn, string(data)); err != nil {
+		t.Fatal(err)
+	}
+	go func() {
+		for {
+			var frame []byte
+			if websocket.Message.Receive(conn, &frame) != nil {
+				return
+			}
+			bt.mu.Lock()
+			bt.out.Write(frame)
+			bt.mu.Unlock()
+		}
+	}()
+	return bt
+}
+
+func (bt *browserTerminal) send(frame string) {
+	bt.t.Helper()
+	if err := websocket.Message.Send(bt.conn, frame); err != nil {
+		bt.t.Fatal(err)
+	}
+}
+
+// keys types s one key per frame, as the page sends key presses.
+func (bt *browserTerminal) keys(s string) {
+	bt.t.Helper()
+	for _, r := range s {
+		bt.send("i" + string(r))
+	}
+}
+
+// waitFor waits until the output so far matches re and returns the match.
+// The renderer only redraws changed lines, so the output is searched as a
+// whole rather than screen by screen.
+func (bt *browserTerminal) waitFor(re string) []string {
+	bt.t.Helper()
+	pattern := regexp.MustCompile(re)
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		bt.mu.Lock()
+		m := pattern.FindStringSubmatch(bt.out.String())
+		bt.mu.Unlock()
+		if m != nil {
+			return m
+		}
+		if time.Now().After(deadline) {
+			bt.mu.Lock()
+			defer bt.mu.Unlock()
+			bt.t.Fatalf("no %q in the terminal output:\n%q", re, bt.out.String())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// eventually fails the test if cond does not hold within a few seconds.
+func eventually(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	for deadline := time.Now().Add(5 * time.Second); !cond(); time.Sleep(10 * time.Millisecond) {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+	}
+}
+
+func TestBrowserTerminal(t *testing.T) {
+	store, err := stats.Open(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	token, err := store.NewToken("web-alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	hub := newTerminalHub(store)
+	srv := httptest.NewServer(hub.handler())
+	defer srv.Close()
+
+	// A named player with a web token gets the menu, with the leaderboard
+	// of their stats.
+	alice := openTerminal(t, srv, terminalStart{terminalSize: terminalSize{Cols: 100, Rows: 40}, Token: token, Name: "alice\x1b[2J"})
+	alice.waitFor(`Choose a game mode`)
+	alice.waitFor(`l: leaderboard`)
+
+	// Sharing: a spectator terminal watches alice with the code shown.
+	alice.keys("s")
+	code := alice.waitFor(`Sharing, code ([A-Z2-9]{6})`)[1]
+	teacher := openTerminal(t, srv, terminalStart{terminalSize: terminalSize{Cols: 100, Rows: 40}, Watch: code})
+	teacher.waitFor(`Watching alice\[2J \(` + code + `\)`)
+
+	// Racing: a guest joins alice in the lobby.
+	guest := openTerminal(t, srv, terminalStart{terminalSize: terminalSize{Cols: 100, Rows: 40}})
+	guest.waitFor(`Choose a game mode`)
+	for _, bt := range []*browserTerminal{alice, guest} {
+		bt.keys(strings.Repeat("j", 6) + "\r")
+	}
+	guest.waitFor(`alice\[2J`)
+	guest.waitFor(`guest-[0-9a-f]{4}`)
+	eventually(t, "two racers in the hub", func() bool {
+		hub.races.mu.Lock()
+		defer hub.races.mu.Unlock()
+		return len(hub.races.clients) == 2
+	})
+
+	// A resize reaches the TUI: a tall, narrow terminal draws the
+	// fretboard vertically, nut first.
+	guest.keys("b")
+	guest.send(`r{"cols":30,"rows":60}`)
+	guest.keys(strings.Repeat("k", 3) + "\r")
+	guest.waitFor(`E  A  D  G  B  E \x1b\[K\r\n +={18}`)
+
+	// Closing the sockets tears the sessions down.
+	alice.conn.Close()
+	guest.conn.Close()
+	teacher.waitFor(`The student has disconnected`)
+	teacher.conn.Close()
+	eventually(t, "the hub to drop the closed sessions", func() bool {
+		hub.races.mu.Lock()
+		defer hub.races.mu.Unlock()
+		return len(hub.races.clients) == 0 && len(hub.sessions.sessions) == 0 && hub.active.Load() == 0
+	})
+}
+
+func TestBrowserTerminalRejectsOtherOrigins(t *testing.T) {
+	srv := httptest.NewServer(newTerminalHub(nil).handler())
+	defer srv.Close()
+	if _, err := websocket.Dial("ws"+strings.TrimPrefix(srv.URL, "http"), "", "https://evil.example"); err == nil {
+		t.Error("cross-origin WebSocket accepted")
+	}
+}
@@ -0,0 +1,347 @@
+package main
+
+import (
+	"crypto/rand"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// ── session registry ──────────────────────────────────────────────────────────
+//
+// An SSH player who chooses to share their session (s in the main menu) is
+// registered under a short share code. A teacher who knows the code can
+// watch the session read-only (`ssh -t host watch CODE`) and push hints into
+// the student's feedback line. While someone watches, the student model
+// publishes a frame after every update that changes it; frames and hints
+// travel as tea messages over channels, like the race hub's.
+
+// feedSize is the number of answer-feed events kept per session.
+const feedSize = 8
+
+type (
+	spectateFrameMsg struct {
+		frame string
+		feed  []string
+	}
+	spectateGoneMsg struct{}
+	teacherHintMsg  struct {
+		text string
+		from *sharedSession
+	}
+)
+
+type sessionRegistry struct {
+	mu       sync.Mutex
+	sessions map[string]*sharedSession
+}
+
+func newSessionRegistry() *sessionRegistry {
+	return &sessionRegistry{sessions: map[string]*sharedSession{}}
+}
+
+// sharedSession is a student session that can be watched. Its fields are
+// guarded by the registry's mutex.
+type sharedSession struct {
+	reg      *sessionRegistry
+	code     string
+	owner    string // the student's connection, to close on disconnect
+	name     string
+	frame    string
+	feed     []string
+	nextID   int
+	watchers map[int]chan tea.Msg
+	hints    chan tea.Msg
+	done     chan struct{} // closed when the student disconnects
+}
+
+// register adds a student session under a fresh share code.
+func (r *sessionRegistry) register(owner, name string) *sharedSession {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	code := newShareCode()
+	for r.sessions[code] != nil {
+		code = newShareCode()
+	}
+	s := &sharedSession{
+		reg:      r,
+		code:     code,
+		owner:    owner,
+		name:     name,
+		watchers: map[int]chan tea.Msg{},
+		hints:    make(chan tea.Msg, 8),
+		done:     make(chan struct{}),
+	}
+	r.sessions[code] = s
+	return s
+}
+
+// shareCodeAlphabet leaves out characters that are easy to confuse (0/O, 1/I).
+const shareCodeAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+
+func newShareCode() string {
+	b := make([]byte, 6)
+	_, _ = rand.Read(b)
+	for i := range b {
+		b[i] = shareCodeAlphabet[int(b[i])%len(shareCodeAlphabet)]
+	}
+	return string(b)
+}
+
+// close unregisters the session. Watchers notice through the done channel.
+func (s *sharedSession) close() {
+	r := s.reg
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	s.closeLocked()
+}
+
+func (s *sharedSession) closeLocked() {
+	if s.reg.sessions[s.code] != s {
+		return // already closed
+	}
+	delete(s.reg.sessions, s.code)
+	close(s.done)
+	clear(s.watchers)
+}
+
+// closeOwner closes the sessions a student connection shared.
+func (r *sessionRegistry) closeOwner(owner string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, s := range r.sessions {
+		if s.owner == owner {
+			s.closeLocked()
+		}
+	}
+}
+
+// record appends event to the answer feed without sending a frame, for
+// when nobody is watching.
+func (s *sharedSession) record(event string) {
+	if event == "" {
+		return
+	}
+	s.reg.mu.Lock()
+	defer s.reg.mu.Unlock()
+	s.addEvent(event)
+}
+
+func (s *sharedSession) addEvent(event string) {
+	s.feed = append(s.feed, time.Now().Format("15:04:05")+"  "+event)
+	if len(s.feed) > feedSize {
+		s.feed = s.feed[len(s.feed)-feedSize:]
+	}
+}
+
+// publish stores the student's current frame, appends event to the answer
+// feed if non-empty, and pushes both to every watcher.
+func (s *sharedSession) publish(frame, event string) {
+	r := s.reg
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if frame == s.frame && event == "" {
+		return
+	}
+	s.frame = frame
+	if event != "" {
+		s.addEvent(event)
+	}
+	msg := spectateFrameMsg{frame: s.frame, feed: append([]string{}, s.feed...)}
+	for _, ch := range s.watchers {
+		// Drop frames for slow watchers; the next frame supersedes them.
+		select {
+		case ch <- msg:
+		default:
+		}
+	}
+}
+
+// watcherCount returns how many teachers are watching.
+func (s *sharedSession) watcherCount() int {
+	s.reg.mu.Lock()
+	defer s.reg.mu.Unlock()
+	return len(s.watchers)
+}
+
+// watch subscribes to the session with the given code. The current frame is
+// delivered right away.
+func (r *sessionRegistry) watch(code string) (s *sharedSession, id int, ch chan tea.Msg, ok bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	s = r.sessions[strings.ToUpper(code)]
+	if s == nil {
+		return nil, 0, nil, false
+	}
+	s.nextID++
+	id = s.nextID
+	ch = make(chan tea.Msg, 16)
+	ch <- spectateFrameMsg{frame: s.frame, feed: append([]string{}, s.feed...)}
+	s.watchers[id] = ch
+	return s, id, ch, true
+}
+
+func (s *sharedSession) unwatch(id int) {
+	s.reg.mu.Lock()
+	defer s.reg.mu.Unlock()
+	delete(s.watchers, id)
+}
+
+// hint queues a teacher hint for the student. It reports false if the
+// student has disconnected or their queue is full.
+func (s *sharedSession) hint(text string) bool {
+	select {
+	case <-s.done:
+		return false // checked first: the queue may still have room
+	default:
+	}
+	select {
+	case s.hints <- teacherHintMsg{text: text, from: s}:
+		return true
+	default:
+		return false
+	}
+}
+
+// waitForHint delivers the next teacher hint to the student model.
+func waitForHint(s *sharedSession) tea.Cmd {
+	return func() tea.Msg {
+		select {
+		case msg := <-s.hints:
+			return msg
+		case <-s.done:
+			return nil
+		}
+	}
+}
+
+// ── spectator TUI ─────────────────────────────────────────────────────────────
+
+// spectatorModel is the read-only view a teacher gets of a student session.
+type spectatorModel struct {
+	styles    uiStyles
+	code      string
+	session   *sharedSession
+	watcherID int
+	ch        chan tea.Msg
+	frame     string
+	feed      []string
+	gone      bool
+	status    string
+	hintInput textinput.Model
+}
+
+func newSpectatorModel(renderer *lipgloss.Renderer, reg *sessionRegistry, code string) spectatorModel {
+	hi := textinput.New()
+	hi.Placeholder = "type a hint for the student and press Enter"
+	hi.CharLimit = 120
+	hi.Width = 60
+	hi.Focus()
+
+	m := spectatorModel{styles: newUIStyles(renderer), code: strings.ToUpper(code), hintInput: hi}
+	if s, id, ch, ok := reg.watch(code); ok {
+		m.session, m.watcherID, m.ch = s, id, ch
+	}
+	return m
+}
+
+// unwatch detaches the spectator, e.g. when its SSH session ends.
+func (m spectatorModel) unwatch() {
+	if m.session != nil {
+		m.session.unwatch(m.watcherID)
+	}
+}
+
+func (m spectatorModel) waitForFrame() tea.Cmd {
+	ch, done := m.ch, m.session.done
+	return func() tea.Msg {
+		select {
+		case msg := <-ch:
+			return msg
+		case <-done:
+			return spectateGoneMsg{}
+		}
+	}
+}
+
+func (m spectatorModel) Init() tea.Cmd {
+	if m.session == nil {
+		return nil
+	}
+	return tea.Batch(m.waitForFrame(), textinput.Blink)
+}
+
+func (m spectatorModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case spectateFrameMsg:
+		m.frame, m.feed = msg.frame, msg.feed
+		return m, m.waitForFrame()
+
+	case spectateGoneMsg:
+		m.gone = true
+		return m, nil
+
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "ctrl+c", "esc":
+			m.unwatch()
+			return m, tea.Quit
+		case "q":
+			if m.session == nil || m.gone {
+				return m, tea.Quit
+			}
+		case "enter":
+			text := strings.TrimSpace(m.hintInput.Value())
+			if text == "" || m.session == nil || m.gone {
+				return m, nil
+			}
+			if m.session.hint(text) {
+				m.status = "Hint sent."
+			} else {
+				m.status = "Hint not delivered — try again in a moment."
+			}
+			m.hintInput.Reset()
+			return m, nil
+		}
+	}
+
+	var cmd tea.Cmd
+	m.hintInput, cmd = m.hintInput.Update(msg)
+	return m, cmd
+}
+
+func (m spectatorModel) View() string {
+	var sb strings.Builder
+	if m.session == nil {
+		sb.WriteString(m.styles.errStyle.Render(fmt.Sprintf("No session with code %q.", m.code)) + "\n\n")
+		sb.WriteString(m.styles.hint.Render("The student gets a code by pressing s in the main menu.  q: quit"))
+		return sb.String()
+	}
+
+	sb.WriteString(m.styles.title.Render(fmt.Sprintf("Watching %s (%s)", displayName(m.session.name), m.code)) + "\n\n")
+	if m.gone {
+		sb.WriteString(m.styles.errStyle.Render("The student has disconnected.") + "\n\n")
+		sb.WriteString(m.styles.hint.Render("q/Esc: quit"))
+		return sb.String()
+	}
+
+	sb.WriteString(m.frame + "\n\n")
+	sb.WriteString(m.styles.title.Render("Answer feed") + "\n")
+	if len(m.feed) == 0 {
+		sb.WriteString(m.styles.hint.Render("  nothing yet") + "\n")
+	}
+	for _, e := range m.feed {
+		sb.WriteString("  " + e + "\n")
+	}
+	sb.WriteString("\n" + m.hintInput.View() + "\n")
+	if m.status != "" {
+		sb.WriteString(m.styles.hint.Render(m.status) + "\n")
+	}
+	sb.WriteString(m.styles.hint.Render("Enter: send hint  Esc: stop watching"))
+	return sb.String()
+}
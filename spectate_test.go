@@ -0,0 +1,213 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// student is a menu model that can share its session in reg.
+func student(reg *sessionRegistry, owner string) model {
+	m := initialModel(lipgloss.DefaultRenderer())
+	m.playerName = "stu"
+	m.shares, m.shareOwner = reg, owner
+	return m
+}
+
+func key(m tea.Model, k string) (tea.Model, tea.Cmd) {
+	msg := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(k)}
+	switch k {
+	case "enter":
+		msg = tea.KeyMsg{Type: tea.KeyEnter}
+	case "esc":
+		msg = tea.KeyMsg{Type: tea.KeyEsc}
+	}
+	return m.Update(msg)
+}
+
+func TestSharingIsOptIn(t *testing.T) {
+	reg := newSessionRegistry()
+	m := student(reg, "conn-1")
+	if m.share != nil || len(reg.sessions) != 0 {
+		t.Fatal("session shared before the student asked")
+	}
+	next, cmd := key(m, "s")
+	m = next.(model)
+	if m.share == nil || reg.sessions[m.share.code] != m.share || cmd == nil {
+		t.Fatalf("s: session not shared (share %v, wait for hints %v)", m.share, cmd != nil)
+	}
+	if !strings.Contains(m.View(), m.share.code) {
+		t.Error("menu does not show the share code")
+	}
+
+	shared := m.share
+	next, _ = key(m, "s")
+	m = next.(model)
+	if m.share != nil || len(reg.sessions) != 0 {
+		t.Error("second s: session still shared")
+	}
+	select {
+	case <-shared.done:
+	default:
+		t.Error("stopped session not closed")
+	}
+
+	// Without a registry, as in the local TUI, s does nothing.
+	local := initialModel(lipgloss.DefaultRenderer())
+	if next, _ := key(local, "s"); next.(model).share != nil {
+		t.Error("local TUI shared its session")
+	}
+}
+
+func TestFramesOnlyToWatchers(t *testing.T) {
+	reg := newSessionRegistry()
+	next, _ := key(student(reg, "conn-1"), "s")
+	m := next.(model)
+
+	// Unwatched, answers go to the feed without rendering frames.
+	m.feedback = "Correct!"
+	next, _ = m.Update(tea.WindowSizeMsg{Width: 80, Height: 24})
+	m = next.(model)
+	if m.share.frame != "" || len(m.share.feed) != 0 {
+		t.Errorf("unchanged feedback recorded: frame %q, feed %q", m.share.frame, m.share.feed)
+	}
+	m.share.record("Correct!")
+	if len(m.share.feed) != 1 || m.share.frame != "" {
+		t.Errorf("record: frame %q, feed %q", m.share.frame, m.share.feed)
+	}
+
+	s, id, ch, ok := reg.watch(strings.ToLower(m.share.code))
+	if !ok || s != m.share {
+		t.Fatal("watch with the lower-case code failed")
+	}
+	if first := (<-ch).(spectateFrameMsg); len(first.feed) != 1 {
+		t.Errorf("first frame feed = %q, want the recorded answer", first.feed)
+	}
+	next, _ = key(m, "j")
+	m = next.(model)
+	select {
+	case msg := <-ch:
+		if f := msg.(spectateFrameMsg); !strings.Contains(f.frame, "menu") {
+			t.Errorf("frame = %q", f.frame)
+		}
+	default:
+		t.Fatal("watcher got no frame")
+	}
+	m.share.publish(m.share.frame, "")
+	if len(ch) != 0 {
+		t.Error("unchanged frame sent again")
+	}
+
+	s.unwatch(id)
+	if m.share.watcherCount() != 0 {
+		t.Error("unwatch: watcher still counted")
+	}
+	m.share.publish("new frame", "event")
+	if len(ch) != 0 {
+		t.Error("frame sent after unwatch")
+	}
+	if _, _, _, ok := reg.watch("NOPE42"); ok {
+		t.Error("watch with an unknown code succeeded")
+	}
+}
+
+func TestCloseOwnerOnDisconnect(t *testing.T) {
+	reg := newSessionRegistry()
+	a := reg.register("conn-1", "a")
+	b := reg.register("conn-2", "b")
+	_, _, ch, _ := reg.watch(a.code)
+	<-ch
+
+	reg.closeOwner("conn-1")
+	if _, ok := reg.sessions[a.code]; ok || a.watcherCount() != 0 {
+		t.Error("closed session still registered or watched")
+	}
+	if reg.sessions[b.code] != b {
+		t.Error("another connection's session was closed")
+	}
+	if msg := waitForHint(a)(); msg != nil {
+		t.Errorf("waitForHint on a closed session = %T, want nil", msg)
+	}
+	a.close() // closing twice is harmless
+	reg.closeOwner("conn-1")
+}
+
+func TestHints(t *testing.T) {
+	reg := newSessionRegistry()
+	next, _ := key(student(reg, "conn-1"), "s")
+	m := next.(model)
+
+	if !m.share.hint("look at the B string") {
+		t.Fatal("hint refused")
+	}
+	next, _ = m.Update(waitForHint(m.share)())
+	m = next.(model)
+	if m.feedback != "Teacher: look at the B string" {
+		t.Errorf("feedback = %q", m.feedback)
+	}
+
+	// A hint from a session the student has stopped sharing is dropped.
+	old := m.share
+	old.hint("stale")
+	next, _ = key(m, "s")
+	m = next.(model)
+	next, _ = m.Update(teacherHintMsg{text: "stale", from: old})
+	if got := next.(model).feedback; strings.Contains(got, "stale") {
+		t.Errorf("hint from a stopped session shown: %q", got)
+	}
+	// The session is closed now, with room in its queue: hints are refused.
+	for range 10 {
+		if old.hint("too late") {
+			t.Fatal("hint to a closed session accepted")
+		}
+	}
+
+	full := reg.register("conn-2", "b")
+	for full.hint("spam") {
+	}
+	if len(full.hints) != cap(full.hints) {
+		t.Errorf("hint refused with %d of %d queued", len(full.hints), cap(full.hints))
+	}
+}
+
+func TestSpectatorModel(t *testing.T) {
+	reg := newSessionRegistry()
+	s := reg.register("conn-1", "stu\x1b[2J")
+	s.publish("FRAME", "Correct!")
+
+	var sm tea.Model = newSpectatorModel(lipgloss.DefaultRenderer(), reg, strings.ToLower(s.code))
+	spec := sm.(spectatorModel)
+	sm, _ = sm.Update(<-spec.ch)
+	view := sm.View()
+	if !strings.Contains(view, "FRAME") || !strings.Contains(view, "Correct!") || strings.Contains(view, "\x1b[2J") {
+		t.Errorf("spectator view:\n%q", view)
+	}
+
+	for _, r := range "try fret 5" {
+		sm, _ = sm.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+	sm, _ = key(sm, "enter")
+	if msg := (<-s.hints).(teacherHintMsg); msg.text != "try fret 5" {
+		t.Errorf("hint = %q", msg.text)
+	}
+	if !strings.Contains(sm.View(), "Hint sent.") {
+		t.Error("no confirmation for the hint")
+	}
+
+	s.close()
+	msg := sm.(spectatorModel).waitForFrame()()
+	sm, _ = sm.Update(msg)
+	if !strings.Contains(sm.View(), "disconnected") {
+		t.Errorf("after the student left:\n%s", sm.View())
+	}
+	if _, cmd := key(sm, "q"); cmd == nil {
+		t.Error("q does not quit once the student is gone")
+	}
+
+	missing := newSpectatorModel(lipgloss.DefaultRenderer(), reg, "NOPE42")
+	if missing.Init() != nil || !strings.Contains(missing.View(), `No session with code "NOPE42"`) {
+		t.Errorf("unknown code:\n%s", missing.View())
+	}
+}
@@ -0,0 +1,241 @@
+package stats
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// LeaderboardModes are the game modes that are ranked. Other modes either
+// have no defined end (free learning) or no comparable score.
+var LeaderboardModes = []string{"single", "chords"}
+
+// ConfigKey identifies a leaderboard: results are only comparable when mode,
+// instrument, tuning and fret count are identical, and for chord runs also
+// the number of chords and the difficulty. Other modes ignore those two.
+func ConfigKey(mode, instrType string, tuning []string, frets, chordCount int, difficulty string) string {
+	parts := []string{mode, instrType, strings.Join(tuning, "-"), strconv.Itoa(frets)}
+	if mode == "chords" {
+		parts = append(parts, strconv.Itoa(chordCount), difficulty)
+	}
+	return strings.Join(parts, "/")
+}
+
+// Entry is a player's best result on one leaderboard.
+type Entry struct {
+	PlayerID string `json:"-"`
+	Player   string `json:"player"`
+	Team     string `json:"team,omitempty"`
+	Rank     int    `json:"rank,omitempty"`
+	Result
+}
+
+// Key returns the leaderboard the entry belongs to.
+func (e Entry) Key() string {
+	return ConfigKey(e.Mode, e.Instrument, e.Tuning, e.Frets, e.TotalItems, e.Difficulty)
+}
+
+// better reports whether a ranks above b: faster first, then more accurate,
+// then earlier.
+func better(a, b Entry) bool {
+	if a.TimeSeconds != b.TimeSeconds {
+		return a.TimeSeconds < b.TimeSeconds
+	}
+	if a.Accuracy != b.Accuracy {
+		return a.Accuracy > b.Accuracy
+	}
+	return a.Timestamp < b.Timestamp
+}
+
+// leaderboardFile holds every board's entries plus the players' teams.
+type leaderboardFile struct {
+	Boards map[string][]Entry `json:"boards"`
+	Teams  map[string]string  `json:"teams"` // player ID → team
+}
+
+// persistedEntry keeps the player ID, which is hidden from API output.
+type persistedEntry struct {
+	PlayerID string `json:"playerId"`
+	Entry
+}
+
+func (s *Store) leaderboardPath() string {
+	return filepath.Join(s.dir, "leaderboard.json")
+}
+
+func (s *Store) loadLeaderboard() (leaderboardFile, error) {
+	lb := leaderboardFile{Boards: map[string][]Entry{}, Teams: map[string]string{}}
+	data, err := os.ReadFile(s.leaderboardPath())
+	if errors.Is(err, os.ErrNotExist) {
+		return lb, nil
+	}
+	if err != nil {
+		return lb, fmt.Errorf("read leaderboard: %w", err)
+	}
+	var raw struct {
+		Boards map[string][]persistedEntry `json:"boards"`
+		Teams  map[string]string           `json:"teams"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return lb, fmt.Errorf("parse leaderboard: %w", err)
+	}
+	for key, entries := range raw.Boards {
+		for _, pe := range entries {
+			pe.Entry.PlayerID = pe.PlayerID
+			lb.Boards[key] = append(lb.Boards[key], pe.Entry)
+		}
+	}
+	if raw.Teams != nil {
+		lb.Teams = raw.Teams
+	}
+	return lb, nil
+}
+
+func (s *Store) saveLeaderboard(lb leaderboardFile) error {
+	raw := struct {
+		Boards map[string][]persistedEntry `json:"boards"`
+		Teams  map[string]string           `json:"teams"`
+	}{Boards: map[string][]persistedEntry{}, Teams: lb.Teams}
+	for key, entries := range lb.Boards {
+		for _, e := range entries {
+			raw.Boards[key] = append(raw.Boards[key], persistedEntry{PlayerID: e.PlayerID, Entry: e})
+		}
+	}
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return err
+	}
+	return s.writeAtomic("leaderboard", data)
+}
+
+// Submit records e on its leaderboard. Only the player's best result per
+// board is kept, so a slower run never replaces a faster one.
+func (s *Store) Submit(e Entry) error {
+	if !playerIDPattern.MatchString(e.PlayerID) {
+		return fmt.Errorf("invalid player id %q", e.PlayerID)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	lb, err := s.loadLeaderboard()
+	if err != nil {
+		return err
+	}
+	e.Rank = 0
+	e.Team = lb.Teams[e.PlayerID]
+	key := e.Key()
+	entries := lb.Boards[key]
+	replaced := false
+	for i, old := range entries {
+		if old.PlayerID != e.PlayerID {
+			continue
+		}
+		replaced = true
+		if e.Player == "" {
+			e.Player = old.Player
+		}
+		if better(e, old) {
+			entries[i] = e
+		} else {
+			entries[i].Player = e.Player // keep the display name current
+		}
+	}
+	if !replaced {
+		entries = append(entries, e)
+	}
+	lb.Boards[key] = entries
+	return s.saveLeaderboard(lb)
+}
+
+// Leaderboard returns the ranked entries of the board identified by key.
+// A non-empty team restricts the board to that team's members; limit <= 0
+// returns every entry.
+func (s *Store) Leaderboard(key, team string, limit int) ([]Entry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	lb, err := s.loadLeaderboard()
+	if err != nil {
+		return nil, err
+	}
+	var out []Entry
+	for _, e := range lb.Boards[key] {
+		// Teams can change after a result was submitted; use the current one.
+		e.Team = lb.Teams[e.PlayerID]
+		if team != "" && !strings.EqualFold(e.Team, team) {
+			continue
+		}
+		out = append(out, e)
+	}
+	sort.Slice(out, func(i, j int) bool { return better(out[i], out[j]) })
+	if limit > 0 && len(out) > limit {
+		out = out[:limit]
+	}
+	for i := range out {
+		out[i].Rank = i + 1
+	}
+	return out, nil
+}
+
+// Boards returns the keys of all non-empty leaderboards, sorted.
+func (s *Store) Boards() ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	lb, err := s.loadLeaderboard()
+	if err != nil {
+		return nil, err
+	}
+	keys := make([]string, 0, len(lb.Boards))
+	for k, entries := range lb.Boards {
+		if len(entries) > 0 {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
+
+// maxTeamLen bounds team names, which are shown in fixed-width tables.
+const maxTeamLen = 24
+
+// SetTeam assigns player to team; an empty team removes the membership.
+func (s *Store) SetTeam(player, team string) error {
+	if !playerIDPattern.MatchString(player) {
+		return fmt.Errorf("invalid player id %q", player)
+	}
+	team = strings.TrimSpace(team)
+	if len(team) > maxTeamLen {
+		return fmt.Errorf("team name too long (max %d characters)", maxTeamLen)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	lb, err := s.loadLeaderboard()
+	if err != nil {
+		return err
+	}
+	if team == "" {
+		delete(lb.Teams, player)
+	} else {
+		lb.Teams[player] = team
+	}
+	return s.saveLeaderboard(lb)
+}
+
+// Team returns the player's team, or "" if they have none.
+func (s *Store) Team(player string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	lb, err := s.loadLeaderboard()
+	if err != nil {
+		return "", err
+	}
+	return lb.Teams[player], nil
+}
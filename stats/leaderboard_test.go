@@ -0,0 +1,157 @@
+package stats
+
+import "testing"
+
+var stdTuning = []string{"E", "A", "D", "G", "B", "E"}
+
+func entry(id, name string, secs float64) Entry {
+	return Entry{
+		PlayerID: id,
+		Player:   name,
+		Result: Result{
+			Mode: "single", Instrument: "guitar", Tuning: stdTuning, Frets: 12,
+			TimeSeconds: secs, TotalItems: 72, Accuracy: 0.9,
+		},
+	}
+}
+
+func TestConfigKey(t *testing.T) {
+	got := ConfigKey("single", "guitar", stdTuning, 12, 0, "")
+	if got != "single/guitar/E-A-D-G-B-E/12" {
+		t.Errorf("ConfigKey = %q", got)
+	}
+	if ConfigKey("single", "guitar", stdTuning, 24, 0, "") == got {
+		t.Error("different fret counts must give different boards")
+	}
+	if ConfigKey("single", "guitar", stdTuning, 12, 20, "hard") != got {
+		t.Error("chord settings must not split other modes' boards")
+	}
+}
+
+func TestLeaderboardChordConfigs(t *testing.T) {
+	s, err := Open(t.TempDir())
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	chords := func(id string, count int, difficulty string) Entry {
+		e := entry(id, id, 60)
+		e.Mode, e.TotalItems, e.Difficulty = "chords", count, difficulty
+		return e
+	}
+	for _, e := range []Entry{chords("a", 5, "easy"), chords("b", 20, "medium"), chords("c", 20, "easy")} {
+		if err := s.Submit(e); err != nil {
+			t.Fatalf("Submit: %v", err)
+		}
+	}
+	for _, tt := range []struct {
+		count      int
+		difficulty string
+		want       string
+	}{
+		{5, "easy", "a"},
+		{20, "medium", "b"},
+		{20, "easy", "c"},
+	} {
+		board, err := s.Leaderboard(ConfigKey("chords", "guitar", stdTuning, 12, tt.count, tt.difficulty), "", 0)
+		if err != nil {
+			t.Fatalf("Leaderboard: %v", err)
+		}
+		if len(board) != 1 || board[0].Player != tt.want {
+			t.Errorf("%d %s chords: board = %+v, want only %s", tt.count, tt.difficulty, board, tt.want)
+		}
+	}
+}
+
+func TestLeaderboardRanking(t *testing.T) {
+	s, err := Open(t.TempDir())
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	for _, e := range []Entry{entry("a", "alice", 120), entry("b", "bob", 90), entry("c", "carol", 150)} {
+		if err := s.Submit(e); err != nil {
+			t.Fatalf("Submit: %v", err)
+		}
+	}
+	key := ConfigKey("single", "guitar", stdTuning, 12, 0, "")
+	board, err := s.Leaderboard(key, "", 0)
+	if err != nil {
+		t.Fatalf("Leaderboard: %v", err)
+	}
+	want := []string{"bob", "alice", "carol"}
+	if len(board) != len(want) {
+		t.Fatalf("Leaderboard = %d entries, want %d", len(board), len(want))
+	}
+	for i, name := range want {
+		if board[i].Player != name || board[i].Rank != i+1 {
+			t.Errorf("rank %d = %s (rank field %d), want %s", i+1, board[i].Player, board[i].Rank, name)
+		}
+	}
+
+	top, _ := s.Leaderboard(key, "", 1)
+	if len(top) != 1 || top[0].Player != "bob" {
+		t.Errorf("Leaderboard limit 1 = %+v, want only bob", top)
+	}
+}
+
+func TestLeaderboardKeepsBestPerPlayer(t *testing.T) {
+	s, _ := Open(t.TempDir())
+	key := ConfigKey("single", "guitar", stdTuning, 12, 0, "")
+
+	_ = s.Submit(entry("a", "alice", 100))
+	_ = s.Submit(entry("a", "alice", 140)) // slower — ignored
+	board, _ := s.Leaderboard(key, "", 0)
+	if len(board) != 1 || board[0].TimeSeconds != 100 {
+		t.Fatalf("after slower run: %+v, want single entry with 100s", board)
+	}
+
+	_ = s.Submit(entry("a", "alice", 80)) // faster — replaces
+	board, _ = s.Leaderboard(key, "", 0)
+	if len(board) != 1 || board[0].TimeSeconds != 80 {
+		t.Errorf("after faster run: %+v, want single entry with 80s", board)
+	}
+}
+
+func TestLeaderboardSeparatesConfigs(t *testing.T) {
+	s, _ := Open(t.TempDir())
+	e := entry("a", "alice", 100)
+	_ = s.Submit(e)
+	e.Frets = 24
+	_ = s.Submit(e)
+
+	keys, err := s.Boards()
+	if err != nil {
+		t.Fatalf("Boards: %v", err)
+	}
+	if len(keys) != 2 {
+		t.Errorf("Boards = %v, want 2 separate boards", keys)
+	}
+}
+
+func TestLeaderboardTeams(t *testing.T) {
+	s, _ := Open(t.TempDir())
+	key := ConfigKey("single", "guitar", stdTuning, 12, 0, "")
+	_ = s.Submit(entry("a", "alice", 100))
+	_ = s.Submit(entry("b", "bob", 90))
+
+	if err := s.SetTeam("a", "Strings"); err != nil {
+		t.Fatalf("SetTeam: %v", err)
+	}
+	if team, _ := s.Team("a"); team != "Strings" {
+		t.Errorf("Team(a) = %q, want Strings", team)
+	}
+
+	board, _ := s.Leaderboard(key, "strings", 0)
+	if len(board) != 1 || board[0].Player != "alice" || board[0].Rank != 1 {
+		t.Errorf("team board = %+v, want alice ranked 1st", board)
+	}
+
+	if err := s.SetTeam("a", ""); err != nil {
+		t.Fatalf("SetTeam clear: %v", err)
+	}
+	if board, _ := s.Leaderboard(key, "strings", 0); len(board) != 0 {
+		t.Errorf("team board after leaving = %+v, want empty", board)
+	}
+	if err := s.SetTeam("a", "a team name that is far too long"); err == nil {
+		t.Error("SetTeam with long name: expected error")
+	}
+}
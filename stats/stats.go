@@ -0,0 +1,239 @@
+package stats
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Result is one finished game session. The JSON field names match the records
+// the web page keeps in localStorage (see saveResult in Fremorizer.html) so
+// both front ends can share the same data. The mode names do not: the
+// terminal records "single", "fretset", "chords" and "daily", the page
+// "random", "find", "chord" and "triads". The store keeps the terminal names
+// for games both front ends have (see CanonicalMode).
+type Result struct {
+	Mode        string   `json:"mode"`
+	Instrument  string   `json:"instrument,omitempty"`
+	Tuning      []string `json:"tuning,omitempty"`
+	Frets       int      `json:"frets,omitempty"`
+	TimeSeconds float64  `json:"timeSeconds"`
+	TotalItems  int      `json:"totalItems"`
+	AvgPerItem  float64  `json:"avgPerItem"`
+	Accuracy    float64  `json:"accuracy,omitempty"` // share of answers that were right, 0–1
+	Timestamp   int64    `json:"timestamp"`          // unix milliseconds
+
+	// Web page settings, kept so synced records round-trip unchanged.
+	Strings    []bool `json:"strings,omitempty"`
+	FretSets   []bool `json:"fretSets,omitempty"`
+	Difficulty string `json:"difficulty,omitempty"`
+}
+
+// NewResult builds a Result for a session that took elapsed to finish total items.
+func NewResult(mode, instrType string, tuning []string, frets int, elapsed time.Duration, total int) Result {
+	secs := elapsed.Seconds()
+	avg := 0.0
+	if total > 0 {
+		avg = secs / float64(total)
+	}
+	return Result{
+		Mode:        mode,
+		Instrument:  instrType,
+		Tuning:      append([]string{}, tuning...),
+		Frets:       frets,
+		TimeSeconds: round2(secs),
+		TotalItems:  total,
+		AvgPerItem:  round2(avg),
+		Timestamp:   time.Now().UnixMilli(),
+	}
+}
+
+// webModes maps the web page's mode names to the terminal's names for the
+// same game.
+var webModes = map[string]string{
+	"random": "single",
+	"find":   "fretset",
+	"chord":  "chords",
+}
+
+// CanonicalMode returns the terminal's name for a web page mode, so one game
+// is summarized as one mode whichever front end played it. Other modes are
+// returned unchanged.
+func CanonicalMode(mode string) string {
+	if m, ok := webModes[mode]; ok {
+		return m
+	}
+	return mode
+}
+
+func round2(f float64) float64 {
+	return float64(int64(f*100+0.5)) / 100
+}
+
+// Store persists results per player as one JSON file each in a directory.
+// A Store is safe for concurrent use.
+type Store struct {
+	dir string
+	mu  sync.Mutex
+}
+
+// Open returns a Store backed by dir, creating the directory if needed.
+func Open(dir string) (*Store, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("create stats dir: %w", err)
+	}
+	return &Store{dir: dir}, nil
+}
+
+// playerIDPattern restricts player IDs to characters that are safe in file
+// names; SSH key fingerprints are converted with PlayerID first.
+var playerIDPattern = regexp.MustCompile(`^[A-Za-z0-9_-]{1,64}$`)
+
+// PlayerID turns an arbitrary identifier (e.g. "SHA256:abc+/=") into a
+// file-name-safe player ID.
+func PlayerID(raw string) string {
+	out := make([]byte, 0, len(raw))
+	for i := 0; i < len(raw) && len(out) < 64; i++ {
+		c := raw[i]
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9', c == '-':
+			out = append(out, c)
+		case c == '+', c == '/', c == '_', c == ':':
+			out = append(out, '_')
+		}
+	}
+	return string(out)
+}
+
+func (s *Store) path(player string) (string, error) {
+	// The shared leaderboard and token files live in the same directory.
+	if !playerIDPattern.MatchString(player) || player == "leaderboard" || player == "tokens" {
+		return "", fmt.Errorf("invalid player id %q", player)
+	}
+	return filepath.Join(s.dir, player+".json"), nil
+}
+
+// Add appends r to the player's results.
+func (s *Store) Add(player string, r Result) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	results, err := s.load(player)
+	if err != nil {
+		return err
+	}
+	results = append(results, r)
+	return s.save(player, results)
+}
+
+// Results returns all results recorded for player, oldest first.
+func (s *Store) Results(player string) ([]Result, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.load(player)
+}
+
+func (s *Store) load(player string) ([]Result, error) {
+	p, err := s.path(player)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(p)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read stats: %w", err)
+	}
+	var results []Result
+	if err := json.Unmarshal(data, &results); err != nil {
+		return nil, fmt.Errorf("parse stats %s: %w", p, err)
+	}
+	return results, nil
+}
+
+// save writes the player's results atomically (see writeAtomic) so a crash
+// mid-write never leaves a truncated stats file behind.
+func (s *Store) save(player string, results []Result) error {
+	if _, err := s.path(player); err != nil {
+		return err
+	}
+	data, err := json.Marshal(results)
+	if err != nil {
+		return err
+	}
+	return s.writeAtomic(player, data)
+}
+
+// writeAtomic replaces <dir>/<name>.json via a temp file and rename, so a
+// crash mid-write never leaves a truncated file behind.
+func (s *Store) writeAtomic(name string, data []byte) error {
+	tmp, err := os.CreateTemp(s.dir, name+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("write %s: %w", name, err)
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return fmt.Errorf("write %s: %w", name, err)
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("write %s: %w", name, err)
+	}
+	return os.Rename(tmp.Name(), filepath.Join(s.dir, name+".json"))
+}
+
+// Check reports whether the store can still write results, e.g. for a
+// readiness probe.
+func (s *Store) Check() error {
+	tmp, err := os.CreateTemp(s.dir, "check.*.tmp")
+	if err != nil {
+		return fmt.Errorf("stats dir not writable: %w", err)
+	}
+	tmp.Close()
+	return os.Remove(tmp.Name())
+}
+
+// ModeSummary aggregates all results of one game mode.
+type ModeSummary struct {
+	Mode        string  `json:"mode"`
+	Games       int     `json:"games"`
+	TotalItems  int     `json:"totalItems"`
+	BestSeconds float64 `json:"bestSeconds"`
+	AvgSeconds  float64 `json:"avgSeconds"`
+	BestAvgItem float64 `json:"bestAvgPerItem"`
+	LastPlayed  int64   `json:"lastPlayed"`
+}
+
+// Summarize groups results by mode, sorted by mode name.
+func Summarize(results []Result) []ModeSummary {
+	byMode := map[string]*ModeSummary{}
+	for _, r := range results {
+		ms, ok := byMode[r.Mode]
+		if !ok {
+			ms = &ModeSummary{Mode: r.Mode, BestSeconds: r.TimeSeconds, BestAvgItem: r.AvgPerItem}
+			byMode[r.Mode] = ms
+		}
+		ms.Games++
+		ms.TotalItems += r.TotalItems
+		ms.AvgSeconds += r.TimeSeconds
+		ms.BestSeconds = min(ms.BestSeconds, r.TimeSeconds)
+		ms.BestAvgItem = min(ms.BestAvgItem, r.AvgPerItem)
+		ms.LastPlayed = max(ms.LastPlayed, r.Timestamp)
+	}
+
+	out := make([]ModeSummary, 0, len(byMode))
+	for _, ms := range byMode {
+		ms.AvgSeconds = round2(ms.AvgSeconds / float64(ms.Games))
+		out = append(out, *ms)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Mode < out[j].Mode })
+	return out
+}
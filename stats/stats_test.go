@@ -0,0 +1,114 @@
+package stats
+
+import (
+	"os"
+	"testing"
+	"time"
+)
+
+func TestStoreAddAndResults(t *testing.T) {
+	s, err := Open(t.TempDir())
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+
+	got, err := s.Results("alice")
+	if err != nil {
+		t.Fatalf("Results on empty store: %v", err)
+	}
+	if len(got) != 0 {
+		t.Fatalf("Results on empty store = %d entries, want 0", len(got))
+	}
+
+	r := NewResult("single", "guitar", []string{"E", "A", "D", "G", "B", "E"}, 12, 90*time.Second, 72)
+	if err := s.Add("alice", r); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+	if err := s.Add("alice", r); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+
+	got, err = s.Results("alice")
+	if err != nil {
+		t.Fatalf("Results: %v", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("Results = %d entries, want 2", len(got))
+	}
+	if got[0].Mode != "single" || got[0].TotalItems != 72 || got[0].Frets != 12 {
+		t.Errorf("Results()[0] = %+v, want mode single, 72 items, 12 frets", got[0])
+	}
+
+	other, _ := s.Results("bob")
+	if len(other) != 0 {
+		t.Errorf("Results(bob) = %d entries, want 0 (players are isolated)", len(other))
+	}
+}
+
+func TestStoreRejectsUnsafePlayerID(t *testing.T) {
+	s, err := Open(t.TempDir())
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	for _, id := range []string{"", "../etc", "a/b", "SHA256:abc"} {
+		if err := s.Add(id, Result{Mode: "single"}); err == nil {
+			t.Errorf("Add(%q): expected error", id)
+		}
+	}
+}
+
+func TestPlayerID(t *testing.T) {
+	got := PlayerID("SHA256:ab+c/d=")
+	if got != "SHA256_ab_c_d" {
+		t.Errorf("PlayerID = %q, want SHA256_ab_c_d", got)
+	}
+	if !playerIDPattern.MatchString(got) {
+		t.Errorf("PlayerID(%q) = %q is not a valid player id", "SHA256:ab+c/d=", got)
+	}
+}
+
+func TestNewResultAverage(t *testing.T) {
+	r := NewResult("chords", "guitar", nil, 12, 30*time.Second, 4)
+	if r.TimeSeconds != 30 || r.AvgPerItem != 7.5 {
+		t.Errorf("NewResult: time=%v avg=%v, want 30 and 7.5", r.TimeSeconds, r.AvgPerItem)
+	}
+	if zero := NewResult("chords", "guitar", nil, 12, time.Second, 0); zero.AvgPerItem != 0 {
+		t.Errorf("NewResult with 0 items: avg=%v, want 0", zero.AvgPerItem)
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	results := []Result{
+		{Mode: "single", TimeSeconds: 100, TotalItems: 72, AvgPerItem: 1.4, Timestamp: 1},
+		{Mode: "single", TimeSeconds: 80, TotalItems: 72, AvgPerItem: 1.1, Timestamp: 3},
+		{Mode: "chords", TimeSeconds: 50, TotalItems: 20, AvgPerItem: 2.5, Timestamp: 2},
+	}
+	sum := Summarize(results)
+	if len(sum) != 2 {
+		t.Fatalf("Summarize = %d modes, want 2", len(sum))
+	}
+	if sum[0].Mode != "chords" || sum[1].Mode != "single" {
+		t.Errorf("Summarize order = %s, %s; want chords, single", sum[0].Mode, sum[1].Mode)
+	}
+	s := sum[1]
+	if s.Games != 2 || s.TotalItems != 144 || s.BestSeconds != 80 || s.AvgSeconds != 90 || s.BestAvgItem != 1.1 || s.LastPlayed != 3 {
+		t.Errorf("Summarize(single) = %+v", s)
+	}
+}
+
+func TestStoreCheck(t *testing.T) {
+	dir := t.TempDir()
+	s, err := Open(dir)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	if err := s.Check(); err != nil {
+		t.Fatalf("Check: %v", err)
+	}
+	if err := os.RemoveAll(dir); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Check(); err == nil {
+		t.Error("Check on a removed directory: expected error")
+	}
+}
@@ -0,0 +1,197 @@
+package stats
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Tokens let clients without an SSH key, i.e. the web page, act as a player.
+// Only a hash of each token is stored, so a leaked stats directory does not
+// leak usable tokens.
+
+// unusedTokenTTL is how long a token is kept before its first use. Tokens
+// that never sync are dropped after it, so clients that create players and
+// walk away do not grow tokens.json forever.
+var unusedTokenTTL = 7 * 24 * time.Hour
+
+// tokenEntry is what tokens.json stores for a token hash.
+type tokenEntry struct {
+	Player  string    `json:"player"`
+	Created time.Time `json:"created"`
+	Used    bool      `json:"used,omitempty"` // presented to TokenPlayer at least once
+}
+
+// UnmarshalJSON also reads the plain player IDs older stores wrote. Those
+// tokens may be in use, so they count as used and never expire.
+func (e *tokenEntry) UnmarshalJSON(data []byte) error {
+	var player string
+	if json.Unmarshal(data, &player) == nil {
+		*e = tokenEntry{Player: player, Used: true}
+		return nil
+	}
+	type plain tokenEntry
+	return json.Unmarshal(data, (*plain)(e))
+}
+
+// expired reports whether the token was never used within unusedTokenTTL.
+func (e tokenEntry) expired() bool {
+	return !e.Used && time.Since(e.Created) > unusedTokenTTL
+}
+
+func (s *Store) tokensPath() string {
+	return filepath.Join(s.dir, "tokens.json")
+}
+
+// hashToken returns the storage key of token.
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func (s *Store) loadTokens() (map[string]tokenEntry, error) {
+	tokens := map[string]tokenEntry{}
+	data, err := os.ReadFile(s.tokensPath())
+	if errors.Is(err, os.ErrNotExist) {
+		return tokens, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read tokens: %w", err)
+	}
+	if err := json.Unmarshal(data, &tokens); err != nil {
+		return nil, fmt.Errorf("parse tokens: %w", err)
+	}
+	return tokens, nil
+}
+
+func (s *Store) saveTokens(tokens map[string]tokenEntry) error {
+	data, err := json.Marshal(tokens)
+	if err != nil {
+		return err
+	}
+	return s.writeAtomic("tokens", data)
+}
+
+// NewToken creates a token that identifies player. A player can hold several
+// tokens, e.g. one per browser. Expired tokens are dropped on the way.
+func (s *Store) NewToken(player string) (string, error) {
+	if _, err := s.path(player); err != nil {
+		return "", err
+	}
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	token := base64.RawURLEncoding.EncodeToString(b)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	tokens, err := s.loadTokens()
+	if err != nil {
+		return "", err
+	}
+	for hash, e := range tokens {
+		if e.expired() {
+			delete(tokens, hash)
+		}
+	}
+	tokens[hashToken(token)] = tokenEntry{Player: player, Created: time.Now()}
+	if err := s.saveTokens(tokens); err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+// NewAnonymousPlayer creates a player without an SSH key from its first
+// results and returns its ID together with a token for it. Requiring results
+// keeps clients that never finish a game from creating players.
+func (s *Store) NewAnonymousPlayer(results []Result) (player, token string, err error) {
+	if len(results) == 0 {
+		return "", "", errors.New("a new player needs at least one result")
+	}
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", err
+	}
+	player = "web-" + hex.EncodeToString(b)
+	if token, err = s.NewToken(player); err != nil {
+		return "", "", err
+	}
+	if _, err := s.Merge(player, results); err != nil {
+		return "", "", err
+	}
+	return player, token, nil
+}
+
+// TokenPlayer returns the player a token belongs to, or "" for an unknown or
+// expired token. The first use of a token is recorded, so it no longer
+// expires.
+func (s *Store) TokenPlayer(token string) (string, error) {
+	if token == "" {
+		return "", nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	tokens, err := s.loadTokens()
+	if err != nil {
+		return "", err
+	}
+	hash := hashToken(token)
+	e, ok := tokens[hash]
+	if !ok || e.expired() {
+		return "", nil
+	}
+	if !e.Used {
+		e.Used = true
+		tokens[hash] = e
+		if err := s.saveTokens(tokens); err != nil {
+			return "", err
+		}
+	}
+	return e.Player, nil
+}
+
+// Merge adds the results that the player does not have yet; a result is
+// identified by mode and timestamp. This makes uploading the same results
+// twice, e.g. when a browser syncs its whole history, harmless. Web mode
+// names are stored as their terminal counterparts (see CanonicalMode). It
+// returns the number of results added.
+func (s *Store) Merge(player string, results []Result) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	existing, err := s.load(player)
+	if err != nil {
+		return 0, err
+	}
+	type key struct {
+		mode string
+		ts   int64
+	}
+	seen := make(map[key]bool, len(existing))
+	for _, r := range existing {
+		seen[key{CanonicalMode(r.Mode), r.Timestamp}] = true
+	}
+	added := 0
+	for _, r := range results {
+		r.Mode = CanonicalMode(r.Mode)
+		k := key{r.Mode, r.Timestamp}
+		if seen[k] {
+			continue
+		}
+		seen[k] = true
+		existing = append(existing, r)
+		added++
+	}
+	if added == 0 {
+		return 0, nil
+	}
+	return added, s.save(player, existing)
+}
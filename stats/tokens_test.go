@@ -0,0 +1,153 @@
+package stats
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTokens(t *testing.T) {
+	dir := t.TempDir()
+	s, err := Open(dir)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+
+	token, err := s.NewToken("SHA256_abc")
+	if err != nil {
+		t.Fatalf("NewToken: %v", err)
+	}
+	if got, _ := s.TokenPlayer(token); got != "SHA256_abc" {
+		t.Errorf("TokenPlayer = %q, want SHA256_abc", got)
+	}
+	if got, _ := s.TokenPlayer("not-a-token"); got != "" {
+		t.Errorf("TokenPlayer(unknown) = %q, want empty", got)
+	}
+	if got, _ := s.TokenPlayer(""); got != "" {
+		t.Errorf("TokenPlayer(\"\") = %q, want empty", got)
+	}
+
+	data, _ := os.ReadFile(dir + "/tokens.json")
+	if strings.Contains(string(data), token) {
+		t.Error("tokens.json contains the plain token")
+	}
+
+	if _, err := s.NewToken("../evil"); err == nil {
+		t.Error("NewToken with unsafe player id: expected error")
+	}
+}
+
+func TestNewAnonymousPlayer(t *testing.T) {
+	s, _ := Open(t.TempDir())
+	if _, _, err := s.NewAnonymousPlayer(nil); err == nil {
+		t.Error("NewAnonymousPlayer without results: expected error")
+	}
+	first := Result{Mode: "random", TimeSeconds: 60, TotalItems: 10, Timestamp: 1000}
+	player, token, err := s.NewAnonymousPlayer([]Result{first})
+	if err != nil {
+		t.Fatalf("NewAnonymousPlayer: %v", err)
+	}
+	if !strings.HasPrefix(player, "web-") {
+		t.Errorf("player = %q, want web- prefix", player)
+	}
+	if got, _ := s.TokenPlayer(token); got != player {
+		t.Errorf("TokenPlayer = %q, want %q", got, player)
+	}
+	if got, _ := s.Results(player); len(got) != 1 {
+		t.Errorf("Results = %d entries, want the first result", len(got))
+	}
+}
+
+func TestUnusedTokensExpire(t *testing.T) {
+	s, _ := Open(t.TempDir())
+	used, err := s.NewToken("alice")
+	if err != nil {
+		t.Fatalf("NewToken: %v", err)
+	}
+	if got, _ := s.TokenPlayer(used); got != "alice" {
+		t.Fatalf("TokenPlayer = %q, want alice", got)
+	}
+	unused, _ := s.NewToken("bob")
+
+	defer func(ttl time.Duration) { unusedTokenTTL = ttl }(unusedTokenTTL)
+	unusedTokenTTL = 0
+	if got, _ := s.TokenPlayer(unused); got != "" {
+		t.Errorf("TokenPlayer(expired) = %q, want empty", got)
+	}
+	if got, _ := s.TokenPlayer(used); got != "alice" {
+		t.Errorf("TokenPlayer(used) = %q, want alice", got)
+	}
+	// The next token drops the expired one from the file.
+	if _, err := s.NewToken("carol"); err != nil {
+		t.Fatalf("NewToken: %v", err)
+	}
+	tokens, _ := s.loadTokens()
+	if _, ok := tokens[hashToken(unused)]; ok || len(tokens) != 2 {
+		t.Errorf("tokens after expiry: %+v", tokens)
+	}
+}
+
+func TestLegacyTokens(t *testing.T) {
+	dir := t.TempDir()
+	s, _ := Open(dir)
+	legacy := `{"` + hashToken("old") + `":"alice"}`
+	if err := os.WriteFile(filepath.Join(dir, "tokens.json"), []byte(legacy), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	defer func(ttl time.Duration) { unusedTokenTTL = ttl }(unusedTokenTTL)
+	unusedTokenTTL = 0
+	if _, err := s.NewToken("bob"); err != nil {
+		t.Fatalf("NewToken: %v", err)
+	}
+	if got, _ := s.TokenPlayer("old"); got != "alice" {
+		t.Errorf("TokenPlayer(legacy) = %q, want alice", got)
+	}
+}
+
+func TestMergeSkipsKnownResults(t *testing.T) {
+	s, _ := Open(t.TempDir())
+	a := Result{Mode: "random", TimeSeconds: 60, TotalItems: 10, Timestamp: 1000}
+	b := Result{Mode: "chord", TimeSeconds: 90, TotalItems: 20, Timestamp: 2000}
+
+	if n, err := s.Merge("alice", []Result{a, b}); err != nil || n != 2 {
+		t.Fatalf("Merge = %d, %v; want 2, nil", n, err)
+	}
+	// Uploading the same history again, plus one new result.
+	c := Result{Mode: "random", TimeSeconds: 55, TotalItems: 10, Timestamp: 3000}
+	if n, err := s.Merge("alice", []Result{a, b, c, c}); err != nil || n != 1 {
+		t.Fatalf("second Merge = %d, %v; want 1, nil", n, err)
+	}
+	got, _ := s.Results("alice")
+	if len(got) != 3 {
+		t.Errorf("Results = %d entries, want 3", len(got))
+	}
+
+	// The page's modes are stored under the terminal's names, so the same
+	// game uploaded under either name is only kept once.
+	if n, _ := s.Merge("alice", []Result{{Mode: "single", TimeSeconds: 55, TotalItems: 10, Timestamp: 3000}}); n != 0 {
+		t.Errorf("Merge of a known result under its terminal name added %d", n)
+	}
+	for _, r := range got {
+		if r.Mode != "single" && r.Mode != "chords" {
+			t.Errorf("stored mode %q, want a terminal mode name", r.Mode)
+		}
+	}
+}
+
+func TestCanonicalMode(t *testing.T) {
+	tests := []struct{ mode, want string }{
+		{"random", "single"},
+		{"find", "fretset"},
+		{"chord", "chords"},
+		{"triads", "triads"},
+		{"single", "single"},
+		{"daily", "daily"},
+	}
+	for _, tt := range tests {
+		if got := CanonicalMode(tt.mode); got != tt.want {
+			t.Errorf("CanonicalMode(%q) = %q, want %q", tt.mode, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/funkymcb/fremorizer/stats"
+)
+
+// remoteStats syncs the local TUI's results with a fremorizer server's
+// /api/me/results, so progress follows the player between the local TUI, SSH
+// and the web page. The token comes from `ssh host token`.
+type remoteStats struct {
+	server string // base URL, e.g. https://fremorizer.com
+	token  string
+	client *http.Client
+}
+
+func newRemoteStats(server, token string) (*remoteStats, error) {
+	if token == "" {
+		return nil, errors.New("--server needs a token from `ssh -p 2222 <host> token`, via --token or FREMORIZER_TOKEN")
+	}
+	return &remoteStats{
+		server: strings.TrimSuffix(server, "/"),
+		token:  token,
+		client: &http.Client{Timeout: 10 * time.Second},
+	}, nil
+}
+
+// sync uploads all of the player's local results and adds the server's to
+// the local store. Both sides skip results they already have, so syncing
+// whole histories is safe to repeat.
+func (rs *remoteStats) sync(store *stats.Store, player string) error {
+	local, err := store.Results(player)
+	if err != nil {
+		return err
+	}
+	if local == nil {
+		local = []stats.Result{}
+	}
+	if err := rs.do("POST", local, nil); err != nil {
+		return fmt.Errorf("upload results: %w", err)
+	}
+	var remote []stats.Result
+	if err := rs.do("GET", nil, &remote); err != nil {
+		return fmt.Errorf("download results: %w", err)
+	}
+	_, err = store.Merge(player, remote)
+	return err
+}
+
+// do sends body, if not nil, to /api/me/results and decodes the response
+// into out, if not nil.
+func (rs *remoteStats) do(method string, body, out any) error {
+	var buf bytes.Buffer
+	if body != nil {
+		if err := json.NewEncoder(&buf).Encode(body); err != nil {
+			return err
+		}
+	}
+	req, err := http.NewRequest(method, rs.server+"/api/me/results", &buf)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+rs.token)
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := rs.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		var e struct{ Error string }
+		if json.NewDecoder(resp.Body).Decode(&e) == nil && e.Error != "" {
+			return fmt.Errorf("%s: %s", resp.Status, e.Error)
+		}
+		return errors.New(resp.Status)
+	}
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
@@ -0,0 +1,127 @@
+package theory
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// The generated block in html/lib.js sits between these two lines.
+const (
+	BeginJS = "// BEGIN GENERATED music-theory data — edit theory/*.go, then run `go generate ./theory`."
+	EndJS   = "// END GENERATED music-theory data."
+)
+
+// stringNames names the guitar strings by display index.
+var stringNames = [6]string{"high E", "B", "G", "D", "A", "low E"}
+
+// JS returns the block of JavaScript constants html/lib.js gets from this
+// package, including the marker lines: CHROMATIC, DISPLAY_BOTH,
+// DISPLAY_FLAT, TUNINGS, the scale constants, CHORD_SHAPES (every voicing
+// placed at every root), DEGREE and CHORD_FORMULAS.
+func JS() []byte {
+	var b bytes.Buffer
+	p := func(format string, args ...any) { fmt.Fprintf(&b, format+"\n", args...) }
+
+	p("%s", BeginJS)
+	p("const CHROMATIC = %s;", jsStrings(Chromatic[:]))
+	var both, flat []string
+	for _, n := range Chromatic {
+		if f, ok := Flats[n]; ok {
+			both = append(both, jsString(n)+":"+jsString(n+"/"+f))
+			flat = append(flat, jsString(n)+":"+jsString(f))
+		}
+	}
+	p("const DISPLAY_BOTH = { %s };", strings.Join(both, ","))
+	p("const DISPLAY_FLAT = { %s };", strings.Join(flat, ","))
+	p("")
+	p("// Top-to-bottom tunings (high → low)")
+	p("const TUNINGS = {")
+	p("  guitar: %s,", jsStrings(reversed(GuitarTuning)))
+	p("  bass:   %s,", jsStrings(reversed(BassTuning)))
+	p("};")
+	p("")
+	for _, s := range Scales {
+		name := strings.ToUpper(strings.ReplaceAll(s.Name, " ", "_"))
+		if !strings.HasPrefix(name, "PENTATONIC") {
+			name += "_SCALE"
+		}
+		ivs := make([]string, len(s.Intervals))
+		for i, iv := range s.Intervals {
+			ivs[i] = fmt.Sprint(iv)
+		}
+		p("const %s = [%s];", name, strings.Join(ivs, ","))
+	}
+	p("")
+	p("// Every voicing at every root that fits frets 0-%d (s = display string 0-5).", MaxFret)
+	p("const CHORD_SHAPES = [")
+	for _, v := range Voicings {
+		shape := ""
+		if v.Shape != "" {
+			shape = ", " + v.Shape + "-shape"
+		}
+		p("  // %s%s, root on the %s string", strings.TrimSpace(v.Suffix), shape, stringNames[v.RootString])
+		for _, c := range v.Chords() {
+			pos := make([]string, len(c.Positions))
+			for i, q := range c.Positions {
+				pos[i] = fmt.Sprintf("{s:%d,f:%d,iv:%s}", q.String, q.Fret, jsString(q.Degree))
+			}
+			p("  { name:%s, positions:[%s] },", jsString(c.Name), strings.Join(pos, ","))
+		}
+	}
+	p("];")
+	p("")
+	p("// Degree token → [semitones from root, human-readable label]")
+	p("const DEGREE = {")
+	for _, d := range Degrees {
+		p("  %s: [%d, %s],", jsString(d.Token), d.Semitones, jsString(d.Label))
+	}
+	p("};")
+	p("")
+	p("// Ordered roughly most → least common; the order breaks ties when several")
+	p("// names describe the same set of notes. `suf` is appended to the root name.")
+	p("const CHORD_FORMULAS = [")
+	for _, f := range Formulas {
+		p("  { suf: %s, deg: %s },", jsString(f.Suffix), jsStrings(f.Degrees))
+	}
+	p("];")
+	p("%s", EndJS)
+	return b.Bytes()
+}
+
+// SpliceJS replaces the generated block of a lib.js source with JS().
+func SpliceJS(src []byte) ([]byte, error) {
+	begin := bytes.Index(src, []byte(BeginJS))
+	end := bytes.Index(src, []byte(EndJS))
+	if begin < 0 || end < begin {
+		return nil, errors.New("generated block markers not found")
+	}
+	end += len(EndJS)
+	if end < len(src) && src[end] == '\n' {
+		end++
+	}
+	out := append([]byte{}, src[:begin]...)
+	out = append(out, JS()...)
+	return append(out, src[end:]...), nil
+}
+
+func jsString(s string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(s) + "'"
+}
+
+func jsStrings(list []string) string {
+	q := make([]string, len(list))
+	for i, s := range list {
+		q[i] = jsString(s)
+	}
+	return "[" + strings.Join(q, ",") + "]"
+}
+
+func reversed(list []string) []string {
+	out := make([]string, len(list))
+	for i, s := range list {
+		out[len(list)-1-i] = s
+	}
+	return out
+}
@@ -0,0 +1,166 @@
+// Package theory holds the music-theory data the terminal game and the web
+// page share: note names, standard tunings, scales, chord voicings and the
+// chord formulas used to name a set of notes.
+//
+// It is the single source of that data. The instrument and game packages
+// read it directly; html/lib.js gets a generated copy (see JS), which a test
+// keeps in sync. After changing anything here, run
+//
+//	go generate ./theory
+package theory
+
+//go:generate go run ../tools/gen-theory -lib ../html/lib.js
+
+// Chromatic lists the twelve pitch classes from C, spelled with sharps.
+var Chromatic = [12]string{"C", "C#", "D", "D#", "E", "F", "F#", "G", "G#", "A", "A#", "B"}
+
+// Flats maps each sharp of Chromatic to its flat spelling.
+var Flats = map[string]string{"C#": "Db", "D#": "Eb", "F#": "Gb", "G#": "Ab", "A#": "Bb"}
+
+// RootNames are the names chords are spelled with, by pitch class: the
+// conventional key names, e.g. Eb and Bb rather than D# and A#.
+var RootNames = [12]string{"C", "C#", "D", "Eb", "E", "F", "F#", "G", "Ab", "A", "Bb", "B"}
+
+// PitchClass returns the pitch class (0–11) of a note name spelled with a
+// sharp, a flat or neither.
+func PitchClass(name string) (int, bool) {
+	for pc, n := range Chromatic {
+		if name == n || name == Flats[n] {
+			return pc, true
+		}
+	}
+	return 0, false
+}
+
+// Standard tunings, low string to high string.
+var (
+	GuitarTuning  = []string{"E", "A", "D", "G", "B", "E"}
+	BassTuning    = []string{"E", "A", "D", "G"}
+	UkuleleTuning = []string{"G", "C", "E", "A"}
+)
+
+// A Scale is a set of intervals in semitones above the root.
+type Scale struct {
+	Name      string
+	Intervals []int
+}
+
+// Scales lists the scales the game and the page can show.
+var Scales = []Scale{
+	{"major", []int{0, 2, 4, 5, 7, 9, 11}},
+	{"minor", []int{0, 2, 3, 5, 7, 8, 10}},
+	{"dorian", []int{0, 2, 3, 5, 7, 9, 10}},
+	{"mixolydian", []int{0, 2, 4, 5, 7, 9, 10}},
+	{"harmonic minor", []int{0, 2, 3, 5, 7, 8, 11}},
+	{"pentatonic major", []int{0, 2, 4, 7, 9}},
+	{"pentatonic minor", []int{0, 3, 5, 7, 10}},
+}
+
+// ScaleIntervals returns the intervals of the named scale, or nil.
+func ScaleIntervals(name string) []int {
+	for _, s := range Scales {
+		if s.Name == name {
+			return s.Intervals
+		}
+	}
+	return nil
+}
+
+// A Degree is a chord tone token as used in voicings and formulas.
+type Degree struct {
+	Token     string // "1", "b3", "#9", …
+	Semitones int    // above the root, within the octave
+	Label     string // human-readable, e.g. "minor 3rd"
+}
+
+// Degrees lists every degree token. Tokens an octave apart (2 and 9) share
+// their semitones.
+var Degrees = []Degree{
+	{"1", 0, "root"},
+	{"b2", 1, "flat 2nd"}, {"b9", 1, "flat 9th"},
+	{"2", 2, "2nd"}, {"9", 2, "9th"},
+	{"b3", 3, "minor 3rd"}, {"#9", 3, "sharp 9th"},
+	{"3", 4, "major 3rd"},
+	{"4", 5, "4th"}, {"11", 5, "11th"},
+	{"b5", 6, "flat 5th"}, {"#11", 6, "sharp 11th"},
+	{"5", 7, "perfect 5th"},
+	{"#5", 8, "sharp 5th"}, {"b13", 8, "flat 13th"},
+	{"6", 9, "6th"}, {"13", 9, "13th"}, {"bb7", 9, "dim 7th"},
+	{"b7", 10, "minor 7th"},
+	{"7", 11, "major 7th"},
+}
+
+// DegreeSemitones returns the semitones of a degree token.
+func DegreeSemitones(token string) (int, bool) {
+	for _, d := range Degrees {
+		if d.Token == token {
+			return d.Semitones, true
+		}
+	}
+	return 0, false
+}
+
+// A Formula names the chord made of its degrees; Suffix is appended to the
+// root name.
+type Formula struct {
+	Suffix  string
+	Degrees []string
+}
+
+// Formulas is ordered roughly most to least common; the order breaks ties
+// when several names describe the same set of notes.
+var Formulas = []Formula{
+	// Triads
+	{" major", []string{"1", "3", "5"}},
+	{" minor", []string{"1", "b3", "5"}},
+	{" dim", []string{"1", "b3", "b5"}},
+	{" aug", []string{"1", "3", "#5"}},
+	{" sus2", []string{"1", "2", "5"}},
+	{" sus4", []string{"1", "4", "5"}},
+	{"(b5)", []string{"1", "3", "b5"}},
+	// Power chord
+	{"5", []string{"1", "5"}},
+	// Sixths & sevenths
+	{"7", []string{"1", "3", "5", "b7"}},
+	{"maj7", []string{"1", "3", "5", "7"}},
+	{"m7", []string{"1", "b3", "5", "b7"}},
+	{"6", []string{"1", "3", "5", "6"}},
+	{"m6", []string{"1", "b3", "5", "6"}},
+	{"m(maj7)", []string{"1", "b3", "5", "7"}},
+	{"m7b5", []string{"1", "b3", "b5", "b7"}},
+	{"dim7", []string{"1", "b3", "b5", "bb7"}},
+	{"dim(maj7)", []string{"1", "b3", "b5", "7"}},
+	{"7sus4", []string{"1", "4", "5", "b7"}},
+	{"7sus2", []string{"1", "2", "5", "b7"}},
+	{"7b5", []string{"1", "3", "b5", "b7"}},
+	{"7#5", []string{"1", "3", "#5", "b7"}},
+	{"maj7b5", []string{"1", "3", "b5", "7"}},
+	{"maj7#5", []string{"1", "3", "#5", "7"}},
+	// Added-tone chords
+	{"add9", []string{"1", "9", "3", "5"}},
+	{"madd9", []string{"1", "9", "b3", "5"}},
+	{"add11", []string{"1", "3", "11", "5"}},
+	{"madd11", []string{"1", "b3", "11", "5"}},
+	// Ninths
+	{"9", []string{"1", "9", "3", "5", "b7"}},
+	{"maj9", []string{"1", "9", "3", "5", "7"}},
+	{"m9", []string{"1", "9", "b3", "5", "b7"}},
+	{"m(maj9)", []string{"1", "9", "b3", "5", "7"}},
+	{"6/9", []string{"1", "9", "3", "5", "6"}},
+	{"m6/9", []string{"1", "9", "b3", "5", "6"}},
+	{"7b9", []string{"1", "b9", "3", "5", "b7"}},
+	{"7#9", []string{"1", "#9", "3", "5", "b7"}},
+	{"9sus4", []string{"1", "9", "4", "5", "b7"}},
+	{"9b5", []string{"1", "9", "3", "b5", "b7"}},
+	{"9#5", []string{"1", "9", "3", "#5", "b7"}},
+	{"7#11", []string{"1", "3", "#11", "5", "b7"}},
+	{"maj7#11", []string{"1", "3", "#11", "5", "7"}},
+	{"7b13", []string{"1", "3", "5", "b13", "b7"}},
+	// Elevenths & thirteenths (13ths omit the 11th, as commonly voiced)
+	{"11", []string{"1", "9", "3", "11", "5", "b7"}},
+	{"m11", []string{"1", "9", "b3", "11", "5", "b7"}},
+	{"maj11", []string{"1", "9", "3", "11", "5", "7"}},
+	{"13", []string{"1", "9", "3", "5", "13", "b7"}},
+	{"m13", []string{"1", "9", "b3", "5", "13", "b7"}},
+	{"maj13", []string{"1", "9", "3", "5", "13", "7"}},
+}
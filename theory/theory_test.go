@@ -0,0 +1,124 @@
+package theory
+
+import (
+	"bytes"
+	"os"
+	"testing"
+)
+
+// TestLibJSInSync fails when html/lib.js's generated block differs from what
+// this package generates, i.e. when one side was edited without the other.
+func TestLibJSInSync(t *testing.T) {
+	src, err := os.ReadFile("../html/lib.js")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := SpliceJS(src)
+	if err != nil {
+		t.Fatalf("html/lib.js: %v", err)
+	}
+	if !bytes.Equal(src, want) {
+		t.Error("html/lib.js is out of date with the theory package; run `go generate ./theory`")
+	}
+}
+
+func TestSpliceJSWithoutMarkers(t *testing.T) {
+	if _, err := SpliceJS([]byte("const CHROMATIC = [];\n")); err == nil {
+		t.Error("SpliceJS without markers: expected error")
+	}
+}
+
+// TestChordsSpellTheirDegrees checks every placed chord against the notes of
+// the guitar: each position must sound the root plus its degree.
+func TestChordsSpellTheirDegrees(t *testing.T) {
+	for _, c := range AllChords() {
+		name := c.Name[:1]
+		if len(c.Name) > 1 && (c.Name[1] == '#' || c.Name[1] == 'b') {
+			name = c.Name[:2]
+		}
+		root, ok := PitchClass(name)
+		if !ok {
+			t.Fatalf("%s: unknown root", c.Name)
+		}
+		hasRoot := false
+		for _, p := range c.Positions {
+			semis, ok := DegreeSemitones(p.Degree)
+			if !ok {
+				t.Errorf("%s: unknown degree %q", c.Name, p.Degree)
+				continue
+			}
+			open, _ := PitchClass(GuitarTuning[len(GuitarTuning)-1-p.String])
+			if got, want := (open+p.Fret)%12, (root+semis)%12; got != want {
+				t.Errorf("%s: string %d fret %d sounds %s, want %s (%s)",
+					c.Name, p.String, p.Fret, Chromatic[got], Chromatic[want], p.Degree)
+			}
+			if p.Fret < 0 || p.Fret > MaxFret {
+				t.Errorf("%s: fret %d out of range", c.Name, p.Fret)
+			}
+			hasRoot = hasRoot || p.Degree == "1"
+		}
+		if !hasRoot {
+			t.Errorf("%s: no root", c.Name)
+		}
+	}
+}
+
+func TestVoicingChords(t *testing.T) {
+	find := func(suffix, shape string) Voicing {
+		for _, v := range Voicings {
+			if v.Suffix == suffix && v.Shape == shape {
+				return v
+			}
+		}
+		t.Fatalf("no %q %s-shape voicing", suffix, shape)
+		return Voicing{}
+	}
+	tests := []struct {
+		suffix, shape string
+		count         int
+		first         string
+		firstFret     int // of the root string
+	}{
+		{" major", "E", 11, "C major", 8}, // Eb would reach fret 13
+		{" major", "C", 9, "C major", 3},  // A, Bb, B would need frets below the nut
+		{"sus2", "E", 1, "Esus2", 0},      // OpenOnly
+		{"9", "A", 12, "C9", 3},           // Wrap places A9 at fret 12
+	}
+	for _, tt := range tests {
+		v := find(tt.suffix, tt.shape)
+		chords := v.Chords()
+		if len(chords) != tt.count {
+			t.Errorf("%q %s-shape: %d chords, want %d", tt.suffix, tt.shape, len(chords), tt.count)
+			continue
+		}
+		c := chords[0]
+		if c.Name != tt.first || c.Positions[0].Fret != tt.firstFret {
+			t.Errorf("%q %s-shape: first chord %s at fret %d, want %s at fret %d",
+				tt.suffix, tt.shape, c.Name, c.Positions[0].Fret, tt.first, tt.firstFret)
+		}
+	}
+}
+
+func TestPitchClass(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		want int
+		ok   bool
+	}{
+		{"C", 0, true}, {"C#", 1, true}, {"Db", 1, true}, {"Bb", 10, true}, {"B", 11, true}, {"H", 0, false},
+	} {
+		if got, ok := PitchClass(tt.name); got != tt.want || ok != tt.ok {
+			t.Errorf("PitchClass(%q) = %d, %v; want %d, %v", tt.name, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestFormulaDegreesExist(t *testing.T) {
+	for _, f := range Formulas {
+		for _, d := range f.Degrees {
+			if _, ok := DegreeSemitones(d); !ok {
+				t.Errorf("formula %q: unknown degree %q", f.Suffix, d)
+			}
+		}
+	}
+}
@@ -0,0 +1,163 @@
+package theory
+
+// MaxFret is the highest fret a voicing is placed up to: the web page's
+// fretboard shows frets 0–12.
+const MaxFret = 12
+
+// A Tone is one fretted or open string of a voicing.
+type Tone struct {
+	String int    // display index, 0 = high E … 5 = low E
+	Offset int    // frets from the root fret on the voicing's root string
+	Degree string // chord degree token, see Degrees
+}
+
+// A Voicing is a movable chord grip for 6-string guitar in standard tuning.
+// Strings without a tone are muted.
+type Voicing struct {
+	Suffix     string // appended to the root name: " major", "7", "m7b5", …
+	Shape      string // the CAGED shape it derives from, if any
+	RootString int    // display index of the string the root fret is on
+	Tones      []Tone
+
+	// OpenOnly keeps only the voicing with the root on the open string.
+	OpenOnly bool
+	// Wrap places roots whose grip does not fit near the nut an octave
+	// higher, so every root gets the voicing if it fits below MaxFret.
+	Wrap bool
+}
+
+// Voicings lists every chord grip the chord quiz asks for. The CAGED
+// voicings come first, then the pro-tier grips: standard drop-2 or
+// CAGED-derived grips with a fretted span of at most 3, whose extended
+// chords omit the 5th where that is the common guitar voicing.
+var Voicings = []Voicing{
+	// Major
+	{Suffix: " major", Shape: "E", RootString: 5, Tones: []Tone{{5, 0, "1"}, {4, 2, "5"}, {3, 2, "1"}, {2, 1, "3"}, {1, 0, "5"}, {0, 0, "1"}}},
+	{Suffix: " major", Shape: "A", RootString: 4, Tones: []Tone{{4, 0, "1"}, {3, 2, "5"}, {2, 2, "1"}, {1, 2, "3"}, {0, 0, "5"}}},
+	{Suffix: " major", Shape: "D", RootString: 3, Tones: []Tone{{3, 0, "1"}, {2, 2, "5"}, {1, 3, "1"}, {0, 2, "3"}}},
+	{Suffix: " major", Shape: "C", RootString: 4, Tones: []Tone{{4, 0, "1"}, {3, -1, "3"}, {2, -3, "5"}, {1, -2, "1"}, {0, -3, "3"}}},
+	{Suffix: " major", Shape: "G", RootString: 5, Tones: []Tone{{5, 0, "1"}, {4, -1, "3"}, {3, -3, "5"}, {2, -3, "1"}, {1, -3, "3"}, {0, 0, "1"}}},
+	// Minor
+	{Suffix: " minor", Shape: "E", RootString: 5, Tones: []Tone{{5, 0, "1"}, {4, 2, "5"}, {3, 2, "1"}, {2, 0, "b3"}, {1, 0, "5"}, {0, 0, "1"}}},
+	{Suffix: " minor", Shape: "A", RootString: 4, Tones: []Tone{{4, 0, "1"}, {3, 2, "5"}, {2, 2, "1"}, {1, 1, "b3"}, {0, 0, "5"}}},
+	{Suffix: " minor", Shape: "D", RootString: 3, Tones: []Tone{{3, 0, "1"}, {2, 2, "5"}, {1, 3, "1"}, {0, 1, "b3"}}},
+	// Dominant 7th
+	{Suffix: "7", Shape: "E", RootString: 5, Tones: []Tone{{5, 0, "1"}, {4, 2, "5"}, {3, 0, "b7"}, {2, 1, "3"}, {1, 0, "5"}, {0, 0, "1"}}},
+	{Suffix: "7", Shape: "A", RootString: 4, Tones: []Tone{{4, 0, "1"}, {3, 2, "5"}, {2, 0, "b7"}, {1, 2, "3"}, {0, 0, "5"}}},
+	{Suffix: "7", Shape: "D", RootString: 3, Tones: []Tone{{3, 0, "1"}, {2, 2, "5"}, {1, 1, "b7"}, {0, 2, "3"}}},
+	// Sus2. Barred up the neck the E-shape spans 5 frets under a barre,
+	// which is not realistically playable.
+	{Suffix: "sus2", Shape: "E", RootString: 5, Tones: []Tone{{5, 0, "1"}, {4, 2, "5"}, {3, 4, "2"}, {2, 4, "5"}, {1, 0, "5"}, {0, 0, "1"}}, OpenOnly: true},
+	{Suffix: "sus2", Shape: "A", RootString: 4, Tones: []Tone{{4, 0, "1"}, {3, 2, "5"}, {2, 2, "1"}, {1, 0, "2"}, {0, 0, "5"}}},
+	{Suffix: "sus2", Shape: "D", RootString: 3, Tones: []Tone{{3, 0, "1"}, {2, 2, "5"}, {1, 3, "1"}, {0, 0, "2"}}},
+	// Sus4
+	{Suffix: "sus4", Shape: "E", RootString: 5, Tones: []Tone{{5, 0, "1"}, {4, 2, "5"}, {3, 2, "1"}, {2, 2, "4"}, {1, 0, "5"}, {0, 0, "1"}}},
+	{Suffix: "sus4", Shape: "A", RootString: 4, Tones: []Tone{{4, 0, "1"}, {3, 2, "5"}, {2, 2, "1"}, {1, 3, "4"}, {0, 0, "5"}}},
+	{Suffix: "sus4", Shape: "D", RootString: 3, Tones: []Tone{{3, 0, "1"}, {2, 2, "5"}, {1, 3, "1"}, {0, 3, "4"}}},
+	// Add9. No D-shape: without a 3rd those voicings are identical to the
+	// D-shape sus2 ones — same frets, two names, one accepted answer.
+	{Suffix: "add9", Shape: "E", RootString: 5, Tones: []Tone{{5, 0, "1"}, {4, 2, "5"}, {3, 2, "1"}, {2, 1, "3"}, {1, 0, "5"}, {0, 2, "9"}}},
+	{Suffix: "add9", Shape: "C", RootString: 4, Tones: []Tone{{4, 0, "1"}, {3, -1, "3"}, {2, -3, "5"}, {1, 0, "9"}, {0, -3, "3"}}},
+	// Major 7th
+	{Suffix: "maj7", Shape: "E", RootString: 5, Tones: []Tone{{5, 0, "1"}, {4, 2, "5"}, {3, 1, "7"}, {2, 1, "3"}, {1, 0, "5"}, {0, 0, "1"}}},
+	{Suffix: "maj7", Shape: "A", RootString: 4, Tones: []Tone{{4, 0, "1"}, {3, 2, "5"}, {2, 1, "7"}, {1, 2, "3"}, {0, 0, "5"}}},
+	{Suffix: "maj7", Shape: "D", RootString: 3, Tones: []Tone{{3, 0, "1"}, {2, 2, "5"}, {1, 2, "7"}, {0, 2, "3"}}},
+	// Minor 7th
+	{Suffix: "m7", Shape: "E", RootString: 5, Tones: []Tone{{5, 0, "1"}, {4, 2, "5"}, {3, 0, "b7"}, {2, 0, "b3"}, {1, 0, "5"}, {0, 0, "1"}}},
+	{Suffix: "m7", Shape: "A", RootString: 4, Tones: []Tone{{4, 0, "1"}, {3, 2, "5"}, {2, 0, "b7"}, {1, 1, "b3"}, {0, 0, "5"}}},
+	{Suffix: "m7", Shape: "D", RootString: 3, Tones: []Tone{{3, 0, "1"}, {2, 2, "5"}, {1, 1, "b7"}, {0, 1, "b3"}}},
+	// 9th
+	{Suffix: "9", Shape: "E", RootString: 5, Tones: []Tone{{5, 0, "1"}, {4, 2, "5"}, {3, 0, "b7"}, {2, 1, "3"}, {1, 0, "5"}, {0, 2, "9"}}},
+	{Suffix: "9", Shape: "A", RootString: 4, Tones: []Tone{{4, 0, "1"}, {3, -1, "3"}, {2, 0, "b7"}, {1, 0, "9"}, {0, 0, "5"}}, Wrap: true},
+	// 7#9 — the E-shape and the compact "Hendrix" grip
+	{Suffix: "7#9", Shape: "E", RootString: 5, Tones: []Tone{{5, 0, "1"}, {4, 2, "5"}, {3, 0, "b7"}, {2, 1, "3"}, {1, 0, "5"}, {0, 3, "#9"}}},
+	{Suffix: "7#9", RootString: 4, Tones: []Tone{{4, 0, "1"}, {3, -1, "3"}, {2, 0, "b7"}, {1, 1, "#9"}}},
+
+	// ── Pro tier ──
+	// Sixths
+	{Suffix: "6", RootString: 4, Tones: []Tone{{4, 0, "1"}, {3, 2, "5"}, {2, 2, "1"}, {1, 2, "3"}, {0, 2, "6"}}, Wrap: true},     // C6 = x35555
+	{Suffix: "6", RootString: 3, Tones: []Tone{{3, 0, "1"}, {2, 2, "5"}, {1, 0, "6"}, {0, 2, "3"}}, Wrap: true},                  // D6 = xx0202
+	{Suffix: "m6", RootString: 4, Tones: []Tone{{4, 0, "1"}, {3, 2, "5"}, {2, 2, "1"}, {1, 1, "b3"}, {0, 2, "6"}}, Wrap: true},   // Am6 = x02212
+	{Suffix: "m6", RootString: 3, Tones: []Tone{{3, 0, "1"}, {2, 2, "5"}, {1, 0, "6"}, {0, 1, "b3"}}, Wrap: true},                // Dm6 = xx0201
+	{Suffix: "6/9", RootString: 4, Tones: []Tone{{4, 0, "1"}, {3, -1, "3"}, {2, -1, "6"}, {1, 0, "9"}, {0, 0, "5"}}, Wrap: true}, // C6/9 = x32233
+	// Extended
+	{Suffix: "maj9", RootString: 4, Tones: []Tone{{4, 0, "1"}, {3, -1, "3"}, {2, 1, "7"}, {1, 0, "9"}}, Wrap: true},              // Cmaj9 = x3243x
+	{Suffix: "m9", RootString: 4, Tones: []Tone{{4, 0, "1"}, {3, -2, "b3"}, {2, 0, "b7"}, {1, 0, "9"}, {0, 0, "5"}}, Wrap: true}, // Cm9 = x31333
+	{Suffix: "13", RootString: 5, Tones: []Tone{{5, 0, "1"}, {3, 0, "b7"}, {2, 1, "3"}, {1, 2, "13"}}, Wrap: true},               // F13 = 1x123x
+	// Minor-major
+	{Suffix: "m(maj7)", RootString: 4, Tones: []Tone{{4, 0, "1"}, {3, 2, "5"}, {2, 1, "7"}, {1, 1, "b3"}}, Wrap: true}, // Am(maj7) = x0211x
+	// Diminished / half-diminished / augmented
+	{Suffix: "m7b5", RootString: 4, Tones: []Tone{{4, 0, "1"}, {3, 1, "b5"}, {2, 0, "b7"}, {1, 1, "b3"}}, Wrap: true},   // Bm7b5 = x2323x
+	{Suffix: "m7b5", RootString: 5, Tones: []Tone{{5, 0, "1"}, {3, 0, "b7"}, {2, 0, "b3"}, {1, -1, "b5"}}, Wrap: true},  // F#m7b5 = 2x221x
+	{Suffix: "dim7", RootString: 4, Tones: []Tone{{4, 0, "1"}, {3, 1, "b5"}, {2, -1, "bb7"}, {1, 1, "b3"}}, Wrap: true}, // Bdim7 = x2313x
+	{Suffix: "dim7", RootString: 3, Tones: []Tone{{3, 0, "1"}, {2, 1, "b5"}, {1, 0, "bb7"}, {0, 1, "b3"}}, Wrap: true},  // Edim7 = xx2323
+	{Suffix: "aug", RootString: 4, Tones: []Tone{{4, 0, "1"}, {3, -1, "3"}, {2, -2, "#5"}, {1, -2, "1"}}, Wrap: true},   // Caug = x3211x
+	{Suffix: "aug", RootString: 5, Tones: []Tone{{5, 0, "1"}, {3, 2, "1"}, {2, 1, "3"}, {1, 1, "#5"}}, Wrap: true},      // Faug = 1x322x
+	// Dominant colors
+	{Suffix: "7sus4", RootString: 5, Tones: []Tone{{5, 0, "1"}, {4, 2, "5"}, {3, 0, "b7"}, {2, 2, "4"}, {1, 0, "5"}, {0, 0, "1"}}, Wrap: true}, // F7sus4 = 131311
+	{Suffix: "7sus4", RootString: 4, Tones: []Tone{{4, 0, "1"}, {3, 2, "5"}, {2, 0, "b7"}, {1, 3, "4"}}, Wrap: true},                           // A7sus4 = x0203x
+	{Suffix: "7b5", RootString: 5, Tones: []Tone{{5, 0, "1"}, {3, 0, "b7"}, {2, 1, "3"}, {1, -1, "b5"}}, Wrap: true},                           // C7b5 = 8x897x
+	{Suffix: "7#5", RootString: 5, Tones: []Tone{{5, 0, "1"}, {3, 0, "b7"}, {2, 1, "3"}, {1, 1, "#5"}}, Wrap: true},                            // C7#5 = 8x899x
+	{Suffix: "7b9", RootString: 4, Tones: []Tone{{4, 0, "1"}, {3, -1, "3"}, {2, 0, "b7"}, {1, -1, "b9"}}, Wrap: true},                          // C7b9 = x3232x
+}
+
+// A Position is one string of a placed chord.
+type Position struct {
+	String int // display index, 0 = high E
+	Fret   int
+	Degree string
+}
+
+// A Chord is a voicing placed at a root.
+type Chord struct {
+	Name      string // root name from RootNames plus the voicing's suffix
+	Positions []Position
+}
+
+// Chords places the voicing at every root, C to B, whose grip fits within
+// frets 0–MaxFret. The root fret is the lowest one that sounds the root on
+// the root string; see OpenOnly and Wrap for the exceptions.
+func (v Voicing) Chords() []Chord {
+	open, _ := PitchClass(GuitarTuning[len(GuitarTuning)-1-v.RootString])
+	var chords []Chord
+	for pc := range 12 {
+		base := (pc - open + 12) % 12
+		if v.OpenOnly && base != 0 {
+			continue
+		}
+		frets := []int{base}
+		if v.Wrap {
+			frets = append(frets, base+12)
+		}
+		for _, root := range frets {
+			if !v.fits(root) {
+				continue
+			}
+			c := Chord{Name: RootNames[pc] + v.Suffix}
+			for _, t := range v.Tones {
+				c.Positions = append(c.Positions, Position{t.String, root + t.Offset, t.Degree})
+			}
+			chords = append(chords, c)
+			break
+		}
+	}
+	return chords
+}
+
+func (v Voicing) fits(root int) bool {
+	for _, t := range v.Tones {
+		if f := root + t.Offset; f < 0 || f > MaxFret {
+			return false
+		}
+	}
+	return true
+}
+
+// AllChords places every voicing, in the order of Voicings.
+func AllChords() []Chord {
+	var chords []Chord
+	for _, v := range Voicings {
+		chords = append(chords, v.Chords()...)
+	}
+	return chords
+}
@@ -0,0 +1,44 @@
+// gen-theory rewrites the generated block of html/lib.js — note names,
+// tunings, scales, chord shapes and chord formulas — from the theory package,
+// so the web page and the terminal game share one copy of that data.
+//
+// Run from the repo root, or through go generate:
+//
+//	go generate ./theory
+//	go run ./tools/gen-theory [-lib html/lib.js]
+//
+// TestLibJSInSync in the theory package fails when lib.js is out of date.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/funkymcb/fremorizer/theory"
+)
+
+func main() {
+	lib := flag.String("lib", filepath.Join("html", "lib.js"), "the lib.js to update")
+	flag.Parse()
+
+	src, err := os.ReadFile(*lib)
+	if err != nil {
+		log.Fatal(err)
+	}
+	out, err := theory.SpliceJS(src)
+	if err != nil {
+		log.Fatalf("%s: %v", *lib, err)
+	}
+	if bytes.Equal(out, src) {
+		fmt.Printf("%s is up to date\n", *lib)
+		return
+	}
+	if err := os.WriteFile(*lib, out, 0o644); err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("updated %s\n", *lib)
+}
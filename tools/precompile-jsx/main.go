@@ -1,21 +1,41 @@
-// precompile-jsx rewrites html/Fremorizer.html in place for production:
-// the inline <script type="text/babel"> block is compiled to plain JS with
-// esbuild and the @babel/standalone loader tag is removed. The page then
-// starts without downloading Babel (~3 MB) or compiling JSX in the browser,
-// and the server can drop 'unsafe-eval' from its CSP (see pageHandler).
+// precompile-jsx builds the web page for production into an output directory
+// (html/dist by default) and leaves the checked-in sources alone:
+//
+//   - the page's inline <script type="text/babel"> block is compiled to plain
+//     JS with esbuild into app.js and the @babel/standalone loader tag is
+//     removed, so the page starts without downloading Babel (~3 MB) or
+//     compiling JSX in the browser, and has no inline script left: the
+//     server's CSP needs neither 'unsafe-eval' nor 'unsafe-inline';
+//   - lib.js is minified and styles.css bundled (@import and url() resolved)
+//     and minified;
+//   - React (from unpkg) and the Google Fonts are downloaded into
+//     static/vendor/, so the page loads nothing from other origins and the
+//     CSP allows 'self' only (see pageHandler); the scripts must match the
+//     integrity attributes of their tags;
+//   - every script and stylesheet gets a content-hashed name below static/
+//     (static/lib.3fa2c1d0.js), and manifest.json maps the names the page
+//     uses to the hashed ones. versionedPage rewrites the page's references
+//     from it, and the server serves static/ as immutable.
 //
 // Run from the repo root before `go build` (the Dockerfile does this):
 //
-//	go run ./tools/precompile-jsx [path/to/Fremorizer.html]
+//	go run ./tools/precompile-jsx [-vendor=false] [-src html] [-out html/dist]
 //
-// The checked-in HTML keeps the Babel setup so local development needs no
-// build step.
+// The server embeds html/dist. Until the tool has run it only holds a README,
+// and the server serves the sources from html/ as they are, so local
+// development needs no build step.
 package main
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"flag"
 	"fmt"
 	"log"
 	"os"
+	"path"
+	"path/filepath"
 	"regexp"
 	"strings"
 
@@ -23,26 +43,111 @@ import (
 )
 
 func main() {
-	path := "html/Fremorizer.html"
-	if len(os.Args) > 1 {
-		path = os.Args[1]
+	src := flag.String("src", "html", "directory with Fremorizer.html, lib.js and styles.css")
+	out := flag.String("out", filepath.Join("html", "dist"), "build output directory")
+	vendorDeps := flag.Bool("vendor", true, "download React and the fonts instead of loading them from CDNs")
+	flag.Parse()
+
+	if err := cleanOutput(*out); err != nil {
+		log.Fatal(err)
+	}
+	static := filepath.Join(*out, "static")
+
+	raw, err := os.ReadFile(filepath.Join(*src, "Fremorizer.html"))
+	if err != nil {
+		log.Fatal(err)
+	}
+	page, app, err := compileJSX(string(raw))
+	if err != nil {
+		log.Fatalf("Fremorizer.html: %v", err)
+	}
+	if *vendorDeps {
+		page, err = vendor(page, filepath.Join(static, "vendor"))
+		if err != nil {
+			log.Fatalf("Fremorizer.html: vendor dependencies: %v", err)
+		}
+	}
+
+	// name used by the page → hashed path below the output directory
+	manifest := map[string]string{}
+	if manifest["app.js"], err = writeHashed(*out, "static/app.js", app); err != nil {
+		log.Fatal(err)
+	}
+	for _, name := range []string{"lib.js", "styles.css"} {
+		code, err := minify(filepath.Join(*src, name))
+		if err != nil {
+			log.Fatalf("%s: %v", name, err)
+		}
+		if manifest[name], err = writeHashed(*out, path.Join("static", name), code); err != nil {
+			log.Fatal(err)
+		}
+	}
+	// Vendored scripts and stylesheets are hashed too. Font files keep
+	// their names, which carry the font version already.
+	vendored, _ := filepath.Glob(filepath.Join(static, "vendor", "*"))
+	for _, file := range vendored {
+		name := path.Join("vendor", filepath.Base(file))
+		if ext := path.Ext(name); ext != ".js" && ext != ".css" {
+			continue
+		}
+		data, err := os.ReadFile(file)
+		if err == nil {
+			manifest[name], err = writeHashed(*out, path.Join("static", name), data)
+		}
+		if err == nil {
+			err = os.Remove(file)
+		}
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(*out, "manifest.json"), append(data, '\n'), 0o644); err != nil {
+		log.Fatal(err)
 	}
+	if err := os.WriteFile(filepath.Join(*out, "Fremorizer.html"), []byte(page), 0o644); err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("built %s: Fremorizer.html and %d hashed assets\n", *out, len(manifest))
+}
 
-	raw, err := os.ReadFile(path)
+// cleanOutput empties the output directory, keeping its README.
+func cleanOutput(dir string) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	entries, err := os.ReadDir(dir)
 	if err != nil {
-		log.Fatalf("read %s: %v", path, err)
+		return err
+	}
+	for _, e := range entries {
+		if e.Name() == "README.md" {
+			continue
+		}
+		if err := os.RemoveAll(filepath.Join(dir, e.Name())); err != nil {
+			return err
+		}
 	}
-	html := string(raw)
+	return nil
+}
 
+// compileJSX compiles the page's inline babel script block to plain JS,
+// replaces the block with a reference to app.js and removes the Babel loader
+// tag. It returns the page and the script.
+func compileJSX(html string) (string, []byte, error) {
 	const openTag = `<script type="text/babel">`
 	const closeTag = `</script>`
 	start := strings.Index(html, openTag)
 	if start < 0 {
-		log.Fatalf("%s: no %q block found (already precompiled?)", path, openTag)
+		return "", nil, fmt.Errorf("no %q block found", openTag)
 	}
 	rel := strings.Index(html[start:], closeTag)
 	if rel < 0 {
-		log.Fatalf("%s: unterminated babel script block", path)
+		return "", nil, fmt.Errorf("unterminated babel script block")
 	}
 	end := start + rel
 
@@ -53,27 +158,70 @@ func main() {
 		MinifySyntax:     true,
 		// Keep identifiers: the script runs in the page's global scope.
 	})
-	for _, w := range result.Warnings {
-		log.Printf("esbuild warning: %s", w.Text)
-	}
-	if len(result.Errors) > 0 {
-		for _, e := range result.Errors {
-			log.Printf("esbuild error: %s", e.Text)
-		}
-		log.Fatalf("%s: JSX compilation failed", path)
+	if err := buildErrors(result.Warnings, result.Errors); err != nil {
+		return "", nil, fmt.Errorf("JSX compilation failed: %w", err)
 	}
-
-	html = html[:start] + "<script>" + string(result.Code) + closeTag + html[end+len(closeTag):]
+	// A classic script at the same place runs at the same time as the
+	// inline block did, and its top-level names are still globals.
+	html = html[:start] + `<script src="app.js"></script>` + html[end+len(closeTag):]
 
 	// The Babel loader is no longer needed.
 	babelTag := regexp.MustCompile(`(?m)^\s*<script src="https://unpkg\.com/@babel/standalone[^\n]*\n`)
 	if !babelTag.MatchString(html) {
-		log.Fatalf("%s: @babel/standalone script tag not found", path)
+		return "", nil, fmt.Errorf("@babel/standalone script tag not found")
 	}
-	html = babelTag.ReplaceAllString(html, "")
+	fmt.Printf("compiled JSX: %d bytes → %d bytes JS\n", len(src), len(result.Code))
+	return babelTag.ReplaceAllString(html, ""), result.Code, nil
+}
+
+// minify minifies a script or bundles and minifies a stylesheet. lib.js is
+// not bundled: it has no imports, and esbuild would wrap it as a CommonJS
+// module because it checks for module.exports, hiding window.Fremorizer.
+func minify(file string) ([]byte, error) {
+	result := api.Build(api.BuildOptions{
+		EntryPoints:       []string{file},
+		Bundle:            path.Ext(file) == ".css",
+		Outdir:            "out",
+		Write:             false,
+		MinifyWhitespace:  true,
+		MinifyIdentifiers: true,
+		MinifySyntax:      true,
+		LogLevel:          api.LogLevelSilent,
+	})
+	if err := buildErrors(result.Warnings, result.Errors); err != nil {
+		return nil, err
+	}
+	if len(result.OutputFiles) != 1 {
+		return nil, fmt.Errorf("esbuild wrote %d files, want 1", len(result.OutputFiles))
+	}
+	code := result.OutputFiles[0].Contents
+	fmt.Printf("minified %s: %d bytes\n", file, len(code))
+	return code, nil
+}
+
+func buildErrors(warnings, errors []api.Message) error {
+	for _, w := range warnings {
+		log.Printf("esbuild warning: %s", w.Text)
+	}
+	if len(errors) == 0 {
+		return nil
+	}
+	for _, e := range errors {
+		log.Printf("esbuild error: %s", e.Text)
+	}
+	return fmt.Errorf("%d esbuild errors", len(errors))
+}
 
-	if err := os.WriteFile(path, []byte(html), 0o644); err != nil {
-		log.Fatalf("write %s: %v", path, err)
+// writeHashed writes data below dir as name with a content hash before the
+// extension (static/lib.js → static/lib.3fa2c1d0.js) and returns that name.
+// The hash is the server's assetHash.
+func writeHashed(dir, name string, data []byte) (string, error) {
+	sum := sha256.Sum256(data)
+	ext := path.Ext(name)
+	hashed := strings.TrimSuffix(name, ext) + "." + hex.EncodeToString(sum[:4]) + ext
+	file := filepath.Join(dir, filepath.FromSlash(hashed))
+	if err := os.MkdirAll(filepath.Dir(file), 0o755); err != nil {
+		return "", err
 	}
-	fmt.Printf("precompiled %s: %d bytes JSX → %d bytes JS\n", path, len(src), len(result.Code))
+	return hashed, os.WriteFile(file, data, 0o644)
 }
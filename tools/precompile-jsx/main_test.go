@@ -0,0 +1,49 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestWriteHashed(t *testing.T) {
+	dir := t.TempDir()
+	// The server's assetHash pins the same value (TestAssetHash), which
+	// keeps the build's file names and the server's hashes in step.
+	name, err := writeHashed(dir, "static/lib.js", []byte("lib.js"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if name != "static/lib.4d4d0cea.js" {
+		t.Errorf("writeHashed = %q, want static/lib.4d4d0cea.js", name)
+	}
+	if data, err := os.ReadFile(filepath.Join(dir, "static", "lib.4d4d0cea.js")); err != nil || string(data) != "lib.js" {
+		t.Errorf("written file: %q, %v", data, err)
+	}
+}
+
+func TestCompiledPageHasNoInlineScript(t *testing.T) {
+	src, err := os.ReadFile(filepath.Join("..", "..", "html", "Fremorizer.html"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	page, app, err := compileJSX(string(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(app) == 0 {
+		t.Error("compileJSX returned no script")
+	}
+	// The server's CSP allows no inline script for a built page; its
+	// hashes only cover the inline blocks it finds (TestCSPCoversInlineBlocks).
+	for _, tag := range regexp.MustCompile(`(?i)<script\b[^>]*>`).FindAllString(page, -1) {
+		if !strings.Contains(tag, " src=") {
+			t.Errorf("built page keeps inline script %s", tag)
+		}
+	}
+	if strings.Contains(page, "@babel/standalone") {
+		t.Error("built page still loads Babel")
+	}
+}
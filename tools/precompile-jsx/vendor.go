@@ -0,0 +1,171 @@
+package main
+
+import (
+	"cmp"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"fmt"
+	"hash"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Vendoring: the page's CDN dependencies are downloaded into the build's
+// static/vendor/ directory, and the page is rewritten to load them from
+// vendor/<file>, which the build manifest then maps to the hashed copies.
+
+var (
+	cdnScriptTag   = regexp.MustCompile(`<script src="(https://unpkg\.com/[^"]+)"([^>]*)></script>`)
+	integrityAttr  = regexp.MustCompile(`integrity="([^"]+)"`)
+	fontsLinkTag   = regexp.MustCompile(`<link href="(https://fonts\.googleapis\.com/css2\?[^"]+)" rel="stylesheet">`)
+	fontsPreloader = regexp.MustCompile(`(?m)^\s*<link rel="preconnect" href="https://fonts\.googleapis\.com">\n`)
+	fontFileURL    = regexp.MustCompile(`url\((https://fonts\.gstatic\.com/[^)]+)\)`)
+)
+
+// Google Fonts picks the font format by user agent; a current browser gets
+// WOFF2 with unicode-range subsets.
+const fontsUserAgent = "Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0 Safari/537.36"
+
+var client = &http.Client{Timeout: time.Minute}
+
+// vendor downloads the scripts and fonts html loads from unpkg and Google
+// Fonts into dir and returns html referring to the local copies as
+// <base of dir>/<file>. Every script must carry an integrity attribute and
+// match it, so a changed CDN file fails the build instead of being served
+// from this origin.
+func vendor(html, dir string) (string, error) {
+	if err := os.MkdirAll(filepath.Join(dir, "fonts"), 0o755); err != nil {
+		return "", err
+	}
+	base := path.Base(filepath.ToSlash(dir))
+
+	var firstErr error
+	html = cdnScriptTag.ReplaceAllStringFunc(html, func(tag string) string {
+		m := cdnScriptTag.FindStringSubmatch(tag)
+		data, err := download(m[1], "")
+		if err == nil {
+			if sri := integrityAttr.FindStringSubmatch(m[2]); sri != nil {
+				err = checkIntegrity(data, sri[1])
+			} else {
+				err = fmt.Errorf("no integrity attribute; once the file is verified, add integrity=%q", integrity(data))
+			}
+		}
+		if err == nil {
+			err = os.WriteFile(filepath.Join(dir, path.Base(m[1])), data, 0o644)
+		}
+		if err != nil {
+			firstErr = cmp.Or(firstErr, fmt.Errorf("%s: %w", m[1], err))
+			return tag
+		}
+		fmt.Printf("vendored %s (%d bytes)\n", m[1], len(data))
+		return `<script src="` + base + "/" + path.Base(m[1]) + `"></script>`
+	})
+
+	html = fontsLinkTag.ReplaceAllStringFunc(html, func(tag string) string {
+		m := fontsLinkTag.FindStringSubmatch(tag)
+		css, err := vendorFonts(strings.ReplaceAll(m[1], "&amp;", "&"), dir)
+		if err == nil {
+			err = os.WriteFile(filepath.Join(dir, "fonts.css"), []byte(css), 0o644)
+		}
+		if err != nil {
+			firstErr = cmp.Or(firstErr, fmt.Errorf("fonts: %w", err))
+			return tag
+		}
+		return `<link href="` + base + `/fonts.css" rel="stylesheet">`
+	})
+	html = fontsPreloader.ReplaceAllString(html, "")
+	return html, firstErr
+}
+
+// vendorFonts downloads a Google Fonts stylesheet and its font files into
+// dir/fonts and returns the stylesheet pointing at the local files. File
+// names keep the family and version from the gstatic path, e.g.
+// oswald-v53-TK3_….woff2, so a font update never reuses a cached name.
+func vendorFonts(cssURL, dir string) (string, error) {
+	css, err := download(cssURL, fontsUserAgent)
+	if err != nil {
+		return "", err
+	}
+	files, total := 0, 0
+	done := map[string]bool{}
+	out := fontFileURL.ReplaceAllStringFunc(string(css), func(ref string) string {
+		if err != nil {
+			return ref
+		}
+		u := fontFileURL.FindStringSubmatch(ref)[1]
+		name := strings.ReplaceAll(strings.TrimPrefix(u, "https://fonts.gstatic.com/s/"), "/", "-")
+		if done[name] {
+			return "url(fonts/" + name + ")"
+		}
+		done[name] = true
+		var data []byte
+		if data, err = download(u, fontsUserAgent); err != nil {
+			return ref
+		}
+		if err = os.WriteFile(filepath.Join(dir, "fonts", name), data, 0o644); err != nil {
+			return ref
+		}
+		files++
+		total += len(data)
+		return "url(fonts/" + name + ")"
+	})
+	if err != nil {
+		return "", err
+	}
+	fmt.Printf("vendored %d font files (%d bytes)\n", files, total)
+	return out, nil
+}
+
+func download(url, userAgent string) ([]byte, error) {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	if userAgent != "" {
+		req.Header.Set("User-Agent", userAgent)
+	}
+	res, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("GET %s: %s", url, res.Status)
+	}
+	return io.ReadAll(res.Body)
+}
+
+// integrity returns the sha384 subresource integrity value of data.
+func integrity(data []byte) string {
+	sum := sha512.Sum384(data)
+	return "sha384-" + base64.StdEncoding.EncodeToString(sum[:])
+}
+
+// checkIntegrity verifies data against a subresource integrity value such as
+// "sha384-<base64>".
+func checkIntegrity(data []byte, sri string) error {
+	algo, want, _ := strings.Cut(sri, "-")
+	var h hash.Hash
+	switch algo {
+	case "sha256":
+		h = sha256.New()
+	case "sha384":
+		h = sha512.New384()
+	case "sha512":
+		h = sha512.New()
+	default:
+		return fmt.Errorf("unsupported integrity algorithm %q", algo)
+	}
+	h.Write(data)
+	if got := base64.StdEncoding.EncodeToString(h.Sum(nil)); got != want {
+		return fmt.Errorf("integrity mismatch: got %s-%s", algo, got)
+	}
+	return nil
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCheckIntegrity(t *testing.T) {
+	data := []byte("console.log('react')")
+	sum := sha256.Sum256(data)
+	tests := []struct {
+		sri string
+		ok  bool
+	}{
+		{integrity(data), true},
+		{"sha256-" + base64.StdEncoding.EncodeToString(sum[:]), true},
+		{integrity([]byte("something else")), false},
+		{"sha384-", false},
+		{"md5-" + base64.StdEncoding.EncodeToString(sum[:16]), false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		if err := checkIntegrity(data, tt.sri); (err == nil) != tt.ok {
+			t.Errorf("checkIntegrity(%q) = %v, want ok %v", tt.sri, err, tt.ok)
+		}
+	}
+}
+
+// cdn answers every request with the same script.
+type cdn string
+
+func (c cdn) RoundTrip(*http.Request) (*http.Response, error) {
+	return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(string(c)))}, nil
+}
+
+func TestVendorChecksIntegrity(t *testing.T) {
+	const script = "window.React = {};"
+	defer func(rt http.RoundTripper) { client.Transport = rt }(client.Transport)
+	client.Transport = cdn(script)
+
+	const url = "https://unpkg.com/react@18.3.1/umd/react.production.min.js"
+	tests := []struct {
+		name, attrs string
+		ok          bool
+	}{
+		{"pinned", ` integrity="` + integrity([]byte(script)) + `" crossorigin="anonymous"`, true},
+		{"changed on the CDN", ` integrity="` + integrity([]byte("old")) + `" crossorigin="anonymous"`, false},
+		{"not pinned", ` crossorigin="anonymous"`, false},
+	}
+	for _, tt := range tests {
+		dir := filepath.Join(t.TempDir(), "vendor")
+		tag := `<script src="` + url + `"` + tt.attrs + `></script>`
+		html, err := vendor(tag, dir)
+		if (err == nil) != tt.ok {
+			t.Errorf("%s: err = %v, want ok %v", tt.name, err, tt.ok)
+			continue
+		}
+		_, statErr := os.Stat(filepath.Join(dir, "react.production.min.js"))
+		if tt.ok {
+			if html != `<script src="vendor/react.production.min.js"></script>` || statErr != nil {
+				t.Errorf("%s: html %q, file: %v", tt.name, html, statErr)
+			}
+			continue
+		}
+		if html != tag || statErr == nil {
+			t.Errorf("%s: rejected script was vendored: html %q", tt.name, html)
+		}
+		if tt.attrs == ` crossorigin="anonymous"` && !strings.Contains(err.Error(), integrity([]byte(script))) {
+			t.Errorf("%s: error %q does not name the value to pin", tt.name, err)
+		}
+	}
+}
+
+// TestPagePinsCDNScripts runs the integrity check on every unpkg script of
+// the real page, with the downloads stubbed. The stub never matches the real
+// files, so each script must fail on a mismatch: a missing integrity
+// attribute would fail the Docker build's go run ./tools/precompile-jsx.
+func TestPagePinsCDNScripts(t *testing.T) {
+	src, err := os.ReadFile(filepath.Join("..", "..", "html", "Fremorizer.html"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func(rt http.RoundTripper) { client.Transport = rt }(client.Transport)
+	client.Transport = cdn("stub")
+
+	tags := cdnScriptTag.FindAllString(string(src), -1)
+	if len(tags) == 0 {
+		t.Fatal("no unpkg scripts in the page")
+	}
+	for _, tag := range tags {
+		_, err := vendor(tag, filepath.Join(t.TempDir(), "vendor"))
+		if err == nil || !strings.Contains(err.Error(), "integrity mismatch") {
+			t.Errorf("%s: err = %v, want an integrity mismatch", tag, err)
+		}
+	}
+}
@@ -0,0 +1,211 @@
+// synth-samples writes a synthetic sample bank of plucked strings for a MIDI
+// range, for instruments the web page has no recordings of yet. Notes are
+// synthesised with the Karplus-Strong algorithm: a burst of noise circulates
+// through a tuned delay line whose low-pass feedback makes it decay like a
+// string. Each note is written as one 16-bit mono WAV per channel, following
+// the bank naming scheme <layer>-<midi><L|R>.wav (see samples.go) with layers
+// numbered softest first, like the gt1/gt2/gt3 Strat layers.
+//
+// The noise comes from a generator seeded per note, layer and channel, so a
+// bank can be regenerated bit for bit.
+//
+// Run from the repo root:
+//
+//	go run ./tools/synth-samples -instrument bass
+//	go run ./tools/synth-samples -instrument ukulele -layers 3 -rate 44100 -out /tmp/uke
+//	go run ./tools/synth-samples -instrument guitar -layers 3 -low 39 -high 85 -out /tmp/strat
+//
+// The server picks the bank up from html/assets/sounds/<instrument> on the
+// next build, or from any directory via --samples <instrument>=<dir>.
+package main
+
+import (
+	"bytes"
+	"encoding/binary"
+	"flag"
+	"fmt"
+	"log"
+	"math"
+	"math/rand"
+	"os"
+	"path/filepath"
+)
+
+// voice describes an instrument's default pitch range and string sound.
+type voice struct {
+	low, high int     // MIDI range: lowest open string to highest string at fret 24
+	sustain   float64 // seconds for a note at A4 to fade by 60 dB
+	pick      float64 // plucking position as a fraction of the string length
+}
+
+var voices = map[string]voice{
+	"guitar":  {low: 30, high: 88, sustain: 3.0, pick: 0.18}, // F#1 (8-string) to E6
+	"bass":    {low: 23, high: 72, sustain: 4.0, pick: 0.22}, // B0 (5-string) to C5
+	"ukulele": {low: 60, high: 93, sustain: 1.6, pick: 0.25}, // C4 to A6
+}
+
+func main() {
+	instrument := flag.String("instrument", "bass", "instrument whose range and string sound to use: guitar, bass or ukulele")
+	low := flag.Int("low", 0, "lowest MIDI note (default: the instrument's lowest)")
+	high := flag.Int("high", 0, "highest MIDI note (default: the instrument's highest)")
+	step := flag.Int("step", 3, "semitones between sampled notes; the page pitch-shifts in between")
+	layers := flag.Int("layers", 1, "number of velocity layers, named <prefix>1 (softest) to <prefix>N")
+	prefix := flag.String("prefix", "gt", "layer name prefix used in the file names")
+	duration := flag.Float64("duration", 2, "length of each sample in seconds")
+	rate := flag.Int("rate", 22050, "sample rate in Hz")
+	seed := flag.Int64("seed", 1, "seed of the excitation noise")
+	out := flag.String("out", "", "output directory (default: html/assets/sounds/<instrument>)")
+	flag.Parse()
+
+	v, ok := voices[*instrument]
+	if !ok {
+		log.Fatalf("unknown instrument %q", *instrument)
+	}
+	if *low == 0 {
+		*low = v.low
+	}
+	if *high == 0 {
+		*high = v.high
+	}
+	if *out == "" {
+		*out = filepath.Join("html", "assets", "sounds", *instrument)
+	}
+	if *low < 0 || *high > 127 || *low > *high || *step < 1 || *layers < 1 ||
+		*duration <= 0 || *rate < 8000 {
+		log.Fatal("invalid range, step, layers, duration or rate")
+	}
+	if err := os.MkdirAll(*out, 0o755); err != nil {
+		log.Fatal(err)
+	}
+
+	files := 0
+	for layer := 1; layer <= *layers; layer++ {
+		// Layers spread evenly over velocities 0.4 to 1.
+		velocity := 1.0
+		if *layers > 1 {
+			velocity = 0.4 + 0.6*float64(layer-1)/float64(*layers-1)
+		}
+		for midi := *low; midi <= *high; midi += *step {
+			for ci, ch := range []string{"L", "R"} {
+				// Channels get their own noise and a slight detune, like
+				// the two microphones of the recorded banks.
+				rng := rand.New(rand.NewSource(*seed + int64(midi)<<16 + int64(layer)<<8 + int64(ci)))
+				cents := []float64{-1.5, 1.5}[ci]
+				pcm := pluck(v, midi, cents, velocity, *duration, *rate, rng)
+				name := filepath.Join(*out, fmt.Sprintf("%s%d-%d%s.wav", *prefix, layer, midi, ch))
+				if err := os.WriteFile(name, wav(pcm, *rate), 0o644); err != nil {
+					log.Fatal(err)
+				}
+				files++
+			}
+		}
+	}
+	fmt.Printf("wrote %d samples to %s\n", files, *out)
+}
+
+// pluck renders one note with the Karplus-Strong algorithm. Softer
+// velocities get a duller excitation and a lower level.
+func pluck(v voice, midi int, cents, velocity, duration float64, rate int, rng *rand.Rand) []float64 {
+	freq := 440 * math.Pow(2, (float64(midi)-69)/12+cents/1200)
+	n := int(duration * float64(rate))
+	out := make([]float64, n)
+
+	// Loop gain per period for the wanted decay time. Higher notes decay
+	// faster, as on a real string.
+	sustain := v.sustain * math.Pow(440/freq, 0.5)
+	target := math.Pow(0.001, 1/(sustain*freq))
+
+	// The loop filter y = (1−S)·x[n] + S·x[n−1] damps high notes far more
+	// than the target on its own, so S shrinks until its loss at the
+	// fundamental matches (Jaffe and Smith's decay stretching); the loop
+	// gain makes up any remaining difference.
+	w := 2 * math.Pi * freq / float64(rate)
+	stretch := 0.5
+	if k := (1 - target*target) / (2 * (1 - math.Cos(w))); k < 0.25 {
+		stretch = (1 - math.Sqrt(1-4*k)) / 2
+	}
+	filterLoss := math.Sqrt(1 - 2*stretch*(1-stretch)*(1-math.Cos(w)))
+	loopGain := math.Min(1, target/filterLoss)
+
+	// The loop delays by delay samples, the filter by about S more and a
+	// first-order allpass by the fraction that is left, so the pitch is
+	// exact. Fractions near 0 make the allpass ring, so it gets 0.1 to 1.1
+	// samples.
+	period := float64(rate)/freq - stretch
+	delay := int(period - 0.1)
+	if delay < 2 {
+		delay = 2
+	}
+	frac := period - float64(delay)
+	apCoef := (1 - frac) / (1 + frac)
+
+	// Excitation: noise, low-passed by velocity, with a comb notch at the
+	// plucking position. line holds the last delay+1 outputs.
+	line := make([]float64, delay+1)
+	lp := 0.15 + 0.8*velocity // one-pole low-pass coefficient
+	prev := 0.0
+	for i := range line {
+		prev += lp * (rng.Float64()*2 - 1 - prev)
+		line[i] = prev
+	}
+	pickAt := int(v.pick * float64(delay))
+	for i := len(line) - 1; i >= pickAt && pickAt > 0; i-- {
+		line[i] -= line[i-pickAt]
+	}
+
+	// y[n] = allpass(g · ((1−S)·y[n−N] + S·y[n−N−1]))
+	apIn, apOut := 0.0, 0.0
+	for i := range out {
+		oldest := i % len(line)      // y[n−N−1], overwritten below
+		older := (i + 1) % len(line) // y[n−N]
+		avg := loopGain * ((1-stretch)*line[older] + stretch*line[oldest])
+		y := apCoef*avg + apIn - apCoef*apOut
+		apIn, apOut = avg, y
+		line[oldest] = y
+		out[i] = y
+	}
+
+	// Remove DC, normalise and fade the tail out.
+	dcIn, dcOut := 0.0, 0.0
+	peak := 0.0
+	for i, s := range out {
+		dcOut = s - dcIn + 0.995*dcOut
+		dcIn = s
+		out[i] = dcOut
+		peak = math.Max(peak, math.Abs(dcOut))
+	}
+	level := 0.8 * math.Sqrt(velocity)
+	fade := int(0.05 * float64(rate))
+	for i := range out {
+		if peak > 0 {
+			out[i] *= level / peak
+		}
+		if rest := n - 1 - i; rest < fade {
+			out[i] *= float64(rest) / float64(fade)
+		}
+	}
+	return out
+}
+
+// wav encodes samples in [-1, 1] as a 16-bit mono PCM WAV file.
+func wav(samples []float64, rate int) []byte {
+	var buf bytes.Buffer
+	dataLen := uint32(2 * len(samples))
+	le := binary.LittleEndian
+	buf.WriteString("RIFF")
+	binary.Write(&buf, le, 36+dataLen)
+	buf.WriteString("WAVEfmt ")
+	binary.Write(&buf, le, uint32(16))     // fmt chunk size
+	binary.Write(&buf, le, uint16(1))      // PCM
+	binary.Write(&buf, le, uint16(1))      // mono
+	binary.Write(&buf, le, uint32(rate))   // sample rate
+	binary.Write(&buf, le, uint32(rate*2)) // byte rate
+	binary.Write(&buf, le, uint16(2))      // block align
+	binary.Write(&buf, le, uint16(16))     // bits per sample
+	buf.WriteString("data")
+	binary.Write(&buf, le, dataLen)
+	for _, s := range samples {
+		binary.Write(&buf, le, int16(math.Round(math.Max(-1, math.Min(1, s))*32767)))
+	}
+	return buf.Bytes()
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func render(instrument string, midi int, seed int64) []float64 {
+	return pluck(voices[instrument], midi, 0, 1, 0.5, 22050, rand.New(rand.NewSource(seed)))
+}
+
+func TestPluckIsDeterministic(t *testing.T) {
+	a, b := wav(render("bass", 40, 1), 22050), wav(render("bass", 40, 1), 22050)
+	if !bytes.Equal(a, b) {
+		t.Error("the same seed gave different samples")
+	}
+	if bytes.Equal(a, wav(render("bass", 40, 2), 22050)) {
+		t.Error("another seed gave the same samples")
+	}
+}
+
+func TestPluckLevelAndPitch(t *testing.T) {
+	const rate = 22050
+	for _, midi := range []int{28, 45, 69, 88} {
+		pcm := render("guitar", midi, 1)
+		if len(pcm) != rate/2 {
+			t.Fatalf("midi %d: %d samples, want %d", midi, len(pcm), rate/2)
+		}
+		peak := 0.0
+		for _, s := range pcm {
+			peak = math.Max(peak, math.Abs(s))
+		}
+		if math.Abs(peak-0.8) > 1e-9 {
+			t.Errorf("midi %d: peak %.3f, want 0.8 at full velocity", midi, peak)
+		}
+		if pcm[len(pcm)-1] != 0 {
+			t.Errorf("midi %d: tail not faded out", midi)
+		}
+
+		// The strongest autocorrelation lag over the attack is the period.
+		freq := 440 * math.Pow(2, float64(midi-69)/12)
+		want := rate / freq
+		best, bestLag := math.Inf(-1), 0
+		for lag := int(want * 0.7); lag <= int(want*1.3)+1; lag++ {
+			sum := 0.0
+			for i := range 2000 {
+				sum += pcm[i] * pcm[i+lag]
+			}
+			if sum > best {
+				best, bestLag = sum, lag
+			}
+		}
+		if math.Abs(float64(bestLag)-want) > 1 {
+			t.Errorf("midi %d: period %d samples, want %.1f", midi, bestLag, want)
+		}
+	}
+}
+
+func TestWAVHeader(t *testing.T) {
+	data := wav([]float64{0, 1, -1, 2, 0.5}, 22050)
+	if len(data) != 44+2*5 {
+		t.Fatalf("%d bytes, want a 44-byte header and 5 samples", len(data))
+	}
+	le := binary.LittleEndian
+	for _, f := range []struct {
+		name      string
+		got, want uint32
+	}{
+		{"RIFF size", le.Uint32(data[4:]), uint32(len(data) - 8)},
+		{"fmt size", le.Uint32(data[16:]), 16},
+		{"format", uint32(le.Uint16(data[20:])), 1},
+		{"channels", uint32(le.Uint16(data[22:])), 1},
+		{"rate", le.Uint32(data[24:]), 22050},
+		{"byte rate", le.Uint32(data[28:]), 44100},
+		{"block align", uint32(le.Uint16(data[32:])), 2},
+		{"bits", uint32(le.Uint16(data[34:])), 16},
+		{"data size", le.Uint32(data[40:]), 10},
+	} {
+		if f.got != f.want {
+			t.Errorf("%s = %d, want %d", f.name, f.got, f.want)
+		}
+	}
+	if string(data[0:4]) != "RIFF" || string(data[8:16]) != "WAVEfmt " || string(data[36:40]) != "data" {
+		t.Errorf("chunk IDs: %q", data[:40])
+	}
+	var pcm [5]int16
+	binary.Read(bytes.NewReader(data[44:]), le, &pcm)
+	if pcm != [5]int16{0, 32767, -32767, 32767, 16384} {
+		t.Errorf("samples = %v, want clipped 16-bit values", pcm)
+	}
+}
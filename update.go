@@ -3,15 +3,33 @@ package main
 import (
 	"fmt"
 	"math"
+	"math/rand"
+	"slices"
 	"strings"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/funkymcb/fremorizer/game"
 	"github.com/funkymcb/fremorizer/instrument"
+	"github.com/funkymcb/fremorizer/stats"
 )
 
 func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	next, cmd := m.update(msg)
+	nm, ok := next.(model)
+	if !ok {
+		return next, cmd
+	}
+	if nm.state != statePlaying {
+		nm.restoreInstrument()
+	}
+	if nm.share != nil {
+		nm.publishFrame(m.feedback)
+	}
+	return nm, cmd
+}
+
+func (m model) update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tickMsg:
 		if m.blink == 0 {
@@ -21,12 +39,32 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		return m, tick()
 
+	case tea.WindowSizeMsg:
+		m.width, m.height = msg.Width, msg.Height
+		m.fitLayout()
+		return m, nil
+
 	case gameFeedbackMsg:
 		m.feedback = msg.text
 		m.feedbackOK = msg.correct
 		m.textInput.Reset()
 		return m, nil
 
+	case raceBoardMsg:
+		m.raceBoard = msg.board
+		return m, waitForRace(m.race)
+
+	case raceStartMsg:
+		return m.startRace(msg)
+
+	case teacherHintMsg:
+		if msg.from != m.share {
+			return m, nil // the student has stopped sharing since
+		}
+		m.feedback = "Teacher: " + msg.text
+		m.feedbackOK = true
+		return m, waitForHint(m.share)
+
 	case tea.KeyMsg:
 		switch m.state {
 		case stateModeSelect:
@@ -36,7 +74,14 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case stateOptionsTuning:
 			return m.updateTuning(msg)
 		case statePlaying:
+			if m.racing {
+				return m.updateRacing(msg)
+			}
 			return m.updatePlaying(msg)
+		case stateRaceLobby:
+			return m.updateRaceLobby(msg)
+		case stateLeaderboard:
+			return m.updateLeaderboard(msg)
 		}
 	}
 
@@ -44,7 +89,7 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 }
 
 func (m model) updateModeSelect(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
-	modes := []string{"single", "fretset", "chords", "freelearning", "notelist"}
+	modes := []string{"single", "fretset", "chords", "freelearning", "notelist", "daily", "race"}
 
 	switch msg.String() {
 	case "ctrl+c", "q":
@@ -57,8 +102,32 @@ func (m model) updateModeSelect(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		m.state = stateOptions
 		m.optCursor = 0
 		return m, nil
+	case "s":
+		if m.shares == nil {
+			break
+		}
+		if m.share != nil {
+			m.share.close()
+			m.share = nil
+			return m, nil
+		}
+		m.share = m.shares.register(m.shareOwner, m.playerName)
+		return m, waitForHint(m.share)
+	case "l":
+		if m.stats != nil {
+			m.state = stateLeaderboard
+			m.feedback = ""
+			m.loadLeaderboard()
+			return m, tea.ClearScreen
+		}
 	case "enter", " ":
 		m.selectedMode = m.modeCursor
+		switch modes[m.modeCursor] {
+		case "race":
+			return m.enterRaceLobby()
+		case "daily":
+			return m.startDaily()
+		}
 		return m.startGame(modes[m.modeCursor])
 	}
 
@@ -66,18 +135,18 @@ func (m model) updateModeSelect(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 }
 
 func (m model) startGame(mode string) (tea.Model, tea.Cmd) {
-	var inst *instrument.Instrument
-	var err error
-
-	switch m.instrType {
-	case "guitar":
-		inst, err = instrument.NewGuitar(m.tuning, m.frets)
-	case "bass":
-		inst, err = instrument.NewBass(m.tuning, m.frets)
-	case "ukulele":
-		inst, err = instrument.NewUkulele(m.tuning, m.frets)
+	next, cmd := m.startGameWithRand(mode, nil)
+	if next.(model).state == statePlaying {
+		metrics.gameStarted(mode)
 	}
+	return next, cmd
+}
 
+// startGameWithRand starts a game whose randomness comes from rng, so that
+// games started with equally seeded generators play the same sequence.
+// A nil rng gives a fresh random game.
+func (m model) startGameWithRand(mode string, rng *rand.Rand) (tea.Model, tea.Cmd) {
+	inst, err := buildInstrument(m.instrType, m.tuning, m.frets)
 	if err != nil {
 		m.feedback = fmt.Sprintf("Error: %v", err)
 		return m, nil
@@ -88,6 +157,7 @@ func (m model) startGame(mode string) (tea.Model, tea.Cmd) {
 		"difficulty":  m.chordDifficulty,
 		"chordCount":  m.chordCount,
 		"accidentals": m.noteListAccidentals,
+		"rand":        rng,
 	})
 	if err != nil {
 		m.feedback = fmt.Sprintf("Error: %v", err)
@@ -98,10 +168,14 @@ func (m model) startGame(mode string) (tea.Model, tea.Cmd) {
 	m.state = statePlaying
 	m.feedback = ""
 	m.gameStartTime = time.Now()
+	m.gameOver = false
+	m.daily = false
 	m.wrongGuesses = 0
+	m.mistakes = 0
 	m.revealed = false
 	m.textInput.Reset()
 	m.textInput.Focus()
+	m.fitLayout()
 	return m, tea.ClearScreen
 }
 
@@ -144,6 +218,12 @@ func (m model) updateOptions(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			}
 		case optItemNoteListAccidentals:
 			m.noteListAccidentals = nextAccidentals(m.noteListAccidentals)
+		case optItemFretboardLayout:
+			m.fretboardLayout = nextFretboardLayout(m.fretboardLayout)
+		case optItemHandedness:
+			m.leftHanded = !m.leftHanded
+		case optItemStringOrder:
+			m.lowStringOnTop = !m.lowStringOnTop
 		case optItemBack:
 			m.state = stateModeSelect
 		}
@@ -171,6 +251,12 @@ func (m model) updateOptions(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			}
 		case optItemNoteListAccidentals:
 			m.noteListAccidentals = prevAccidentals(m.noteListAccidentals)
+		case optItemFretboardLayout:
+			m.fretboardLayout = prevFretboardLayout(m.fretboardLayout)
+		case optItemHandedness:
+			m.leftHanded = !m.leftHanded
+		case optItemStringOrder:
+			m.lowStringOnTop = !m.lowStringOnTop
 		}
 	}
 
@@ -308,8 +394,16 @@ func (m model) updateSingleNoteMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 				elapsed := time.Since(m.gameStartTime)
 				_, total := snGame.Progress()
 				avg := elapsed.Seconds() / math.Max(1, float64(total))
+				if m.daily {
+					err := m.finishGame("daily", total)
+					accuracy := float64(total) / float64(total+m.mistakes)
+					m.feedback = dailySummary(m.dailyDate, snGame.GetInstrument(), m.dailySlips, elapsed, accuracy) + unsaved(err)
+					m.feedbackOK = true
+					return m, nil
+				}
+				err := m.finishGame("single", total)
 				m.feedback = fmt.Sprintf("All notes green — well done! Time: %s | Avg: %.1fs per note",
-					formatDuration(elapsed), avg)
+					formatDuration(elapsed), avg) + unsaved(err)
 				m.feedbackOK = true
 				return m, nil
 			}
@@ -322,6 +416,11 @@ func (m model) updateSingleNoteMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		}
 
 		m.wrongGuesses++
+		m.mistakes++
+		if m.daily {
+			si, fret := snGame.CurrentPosition()
+			m.dailySlips[[2]int{si, fret}] = true
+		}
 		if m.wrongGuesses >= 3 {
 			noteName := snGame.CurrentNoteName()
 			snGame.RevealNote(false)
@@ -348,6 +447,38 @@ func (m model) updateSingleNoteMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	return m, cmd
 }
 
+// cursorMove maps an arrow or hjkl key to a cursor move by strings and
+// frets, following the fretboard on screen: on the vertical one, frets run
+// down and strings from the lowest on the left, and mirrored or flipped
+// fretboards reverse the direction along their axis.
+func (m model) cursorMove(key string) (ds, df int) {
+	switch key {
+	case "up", "k":
+		ds = -1
+	case "down", "j":
+		ds = 1
+	case "left", "h":
+		df = -1
+	case "right", "l":
+		df = 1
+	}
+	opts := m.renderOpts()
+	if opts.Vertical {
+		ds, df = -df, ds
+		if opts.LeftHanded != opts.FlipStrings {
+			ds = -ds
+		}
+		return ds, df
+	}
+	if opts.LeftHanded {
+		df = -df
+	}
+	if opts.FlipStrings {
+		ds = -ds
+	}
+	return ds, df
+}
+
 func (m model) updateFretSetMode(msg tea.KeyMsg, fsGame *game.FretSetGameImpl) (tea.Model, tea.Cmd) {
 	switch msg.String() {
 	case "ctrl+c":
@@ -356,14 +487,8 @@ func (m model) updateFretSetMode(msg tea.KeyMsg, fsGame *game.FretSetGameImpl) (
 		m.state = stateModeSelect
 		m.feedback = ""
 		return m, tea.ClearScreen
-	case "up", "k":
-		fsGame.MoveCursor(-1, 0)
-	case "down", "j":
-		fsGame.MoveCursor(1, 0)
-	case "left", "h":
-		fsGame.MoveCursor(0, -1)
-	case "right", "l":
-		fsGame.MoveCursor(0, 1)
+	case "up", "k", "down", "j", "left", "h", "right", "l":
+		fsGame.MoveCursor(m.cursorMove(msg.String()))
 	case " ", "enter":
 		cs, cf := fsGame.GetCursor()
 		fsGame.ToggleMark(cs, cf)
@@ -377,9 +502,10 @@ func (m model) updateFretSetMode(msg tea.KeyMsg, fsGame *game.FretSetGameImpl) (
 				elapsed := time.Since(m.gameStartTime)
 				sets := fsGame.FretSetsCompleted()
 				avg := elapsed.Seconds() / math.Max(1, float64(sets))
+				err := m.finishGame("fretset", sets)
 				m.state = stateModeSelect
 				m.feedback = fmt.Sprintf("Fretboard complete — well done! Time: %s | Avg: %.1fs per fret set",
-					formatDuration(elapsed), avg)
+					formatDuration(elapsed), avg) + unsaved(err)
 				m.feedbackOK = true
 			} else if fretSetDone {
 				m.feedback = fmt.Sprintf("Fret set complete! Now find '%s'.", fsGame.GetTargetNote())
@@ -405,14 +531,8 @@ func (m model) updateChordsMode(msg tea.KeyMsg, cg *game.ChordsGame) (tea.Model,
 	// Medium difficulty: cursor-marking sub-phase.
 	if cg.IsMarking() {
 		switch msg.String() {
-		case "up", "k":
-			cg.MoveCursor(-1, 0)
-		case "down", "j":
-			cg.MoveCursor(1, 0)
-		case "left", "h":
-			cg.MoveCursor(0, -1)
-		case "right", "l":
-			cg.MoveCursor(0, 1)
+		case "up", "k", "down", "j", "left", "h", "right", "l":
+			cg.MoveCursor(m.cursorMove(msg.String()))
 		case " ", "enter":
 			cs, cf := cg.GetCursor()
 			cg.ToggleMark(cs, cf)
@@ -432,9 +552,10 @@ func (m model) updateChordsMode(msg tea.KeyMsg, cg *game.ChordsGame) (tea.Model,
 				elapsed := time.Since(m.gameStartTime)
 				_, total := cg.Progress()
 				avg := elapsed.Seconds() / math.Max(1, float64(total))
+				err := m.finishGame("chords", total)
 				m.state = stateModeSelect
 				m.feedback = fmt.Sprintf("All %d chords found — well done! Time: %s | Avg: %.1fs per chord",
-					total, formatDuration(elapsed), avg)
+					total, formatDuration(elapsed), avg) + unsaved(err)
 				m.feedbackOK = true
 				return m, nil
 			}
@@ -467,6 +588,7 @@ func (m model) updateChordsMode(msg tea.KeyMsg, cg *game.ChordsGame) (tea.Model,
 				m.feedbackOK = true
 			}
 		} else {
+			m.mistakes++
 			m.textInput.Reset()
 			if cg.Phase() == game.ChordPhaseNaming {
 				m.feedback = "Incorrect chord name. Try again!"
@@ -491,14 +613,8 @@ func (m model) updateFreeLearningMode(msg tea.KeyMsg, flGame *game.FreeLearningG
 		m.state = stateModeSelect
 		m.feedback = ""
 		return m, tea.ClearScreen
-	case "up", "k":
-		flGame.MoveCursor(-1, 0)
-	case "down", "j":
-		flGame.MoveCursor(1, 0)
-	case "left", "h":
-		flGame.MoveCursor(0, -1)
-	case "right", "l":
-		flGame.MoveCursor(0, 1)
+	case "up", "k", "down", "j", "left", "h", "right", "l":
+		flGame.MoveCursor(m.cursorMove(msg.String()))
 	case " ":
 		flGame.RevealNote()
 	case "s":
@@ -515,6 +631,209 @@ func (m model) updateFreeLearningMode(msg tea.KeyMsg, flGame *game.FreeLearningG
 	return m, nil
 }
 
+// ── race mode ─────────────────────────────────────────────────────────────────
+
+func (m model) enterRaceLobby() (tea.Model, tea.Cmd) {
+	if m.race == nil {
+		m.feedback = "Race mode is only available on the SSH server and in the browser terminal."
+		m.feedbackOK = false
+		return m, nil
+	}
+	m.race.join()
+	m.state = stateRaceLobby
+	m.feedback = ""
+	return m, tea.ClearScreen
+}
+
+func (m model) updateRaceLobby(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c":
+		return m, tea.Quit
+	case "esc", "b", "q":
+		m.race.leave()
+		m.state = stateModeSelect
+		m.feedback = ""
+		return m, tea.ClearScreen
+	case "m":
+		m.race.cycleMode()
+	case "enter", " ":
+		cfg := raceConfig{
+			instrType:  m.instrType,
+			tuning:     append([]string{}, m.tuning...),
+			frets:      m.frets,
+			sequential: m.fretSetSequential,
+		}
+		if !m.race.start(cfg) {
+			m.feedback = "A race is already running — wait for it to finish."
+			m.feedbackOK = false
+		}
+	}
+	return m, nil
+}
+
+// startRace starts the shared race game. The race's instrument setup stands
+// in for the player's own options until the game ends, so every racer sees
+// the same board.
+func (m model) startRace(msg raceStartMsg) (tea.Model, tea.Cmd) {
+	if m.state != stateRaceLobby {
+		return m, waitForRace(m.race)
+	}
+	m.useInstrument(instrumentConfig{
+		instrType:         msg.cfg.instrType,
+		numStrings:        len(msg.cfg.tuning),
+		tuning:            msg.cfg.tuning,
+		frets:             msg.cfg.frets,
+		fretSetSequential: msg.cfg.sequential,
+	})
+
+	next, cmd := m.startGameWithRand(msg.mode, rand.New(rand.NewSource(msg.seed)))
+	nm := next.(model)
+	if nm.state != statePlaying {
+		nm.race.leave()
+		nm.state = stateModeSelect
+		return nm, waitForRace(nm.race)
+	}
+	nm.racing = true
+	nm.fitLayout() // make room for the race board
+	metrics.gameStarted(msg.mode)
+	correct, total := raceProgress(nm.activeGame)
+	nm.race.report(correct, total, false)
+	return nm, tea.Batch(cmd, waitForRace(nm.race))
+}
+
+// updateRacing wraps updatePlaying and reports every move's progress to the
+// race hub. Finishing returns to the lobby to watch the others; leaving the
+// game gives up the race.
+func (m model) updateRacing(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	next, cmd := m.updatePlaying(msg)
+	nm := next.(model)
+	correct, total := raceProgress(nm.activeGame)
+	switch {
+	case nm.gameOver:
+		nm.race.report(total, total, true)
+		nm.racing = false
+		nm.state = stateRaceLobby
+	case nm.state != statePlaying:
+		nm.race.leave()
+		nm.racing = false
+	default:
+		nm.race.report(correct, total, false)
+	}
+	return nm, cmd
+}
+
+// raceProgress returns the progress of a raceable game.
+func raceProgress(g game.Game) (correct, total int) {
+	switch g := g.(type) {
+	case *game.SingleNoteGame:
+		return g.Progress()
+	case *game.FretSetGameImpl:
+		_, _, correct, total = g.Progress()
+		return correct, total
+	}
+	return 0, 0
+}
+
+// ── spectators ────────────────────────────────────────────────────────────────
+
+// publishFrame pushes the current screen to teachers watching this session.
+// A changed feedback line is added to their answer feed. Nothing is rendered
+// while nobody watches.
+func (m model) publishFrame(prevFeedback string) {
+	event := ""
+	if m.feedback != prevFeedback {
+		event = strings.TrimSpace(m.feedback)
+	}
+	if m.share.watcherCount() == 0 {
+		m.share.record(event)
+		return
+	}
+	frame := "The student is in the menu."
+	if m.state == statePlaying {
+		frame = m.viewPlaying()
+	}
+	m.share.publish(frame, event)
+}
+
+// finishGame marks the active game as finished and saves the session to the
+// player's stats and, for ranked modes, to the leaderboard. It returns what
+// could not be saved, for the end-of-game message: a full disk should not
+// interrupt the game.
+func (m *model) finishGame(mode string, total int) error {
+	m.gameOver = true
+	metrics.gameCompleted(mode)
+	if m.stats == nil || m.player == "" {
+		return nil
+	}
+	r := stats.NewResult(mode, m.instrType, m.tuning, m.frets, time.Since(m.gameStartTime), total)
+	if mode == "chords" {
+		r.Difficulty = m.chordDifficulty
+	}
+	if total > 0 {
+		r.Accuracy = math.Round(float64(total)/float64(total+m.mistakes)*1000) / 1000
+	}
+	if err := m.stats.Add(m.player, r); err != nil {
+		return fmt.Errorf("record result: %w", err)
+	}
+	if !slices.Contains(stats.LeaderboardModes, mode) {
+		return nil
+	}
+	e := stats.Entry{PlayerID: m.player, Player: m.playerName, Result: r}
+	if err := m.stats.Submit(e); err != nil {
+		return fmt.Errorf("submit leaderboard entry: %w", err)
+	}
+	return nil
+}
+
+// unsaved returns the note added to the end-of-game message when finishGame
+// failed.
+func unsaved(err error) string {
+	if err == nil {
+		return ""
+	}
+	return fmt.Sprintf("\nYour result was not saved (%v).", err)
+}
+
+// ── leaderboard ───────────────────────────────────────────────────────────────
+
+func (m model) updateLeaderboard(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	n := len(stats.LeaderboardModes)
+	switch msg.String() {
+	case "ctrl+c":
+		return m, tea.Quit
+	case "esc", "q", "b":
+		m.state = stateModeSelect
+		return m, tea.ClearScreen
+	case "left", "h":
+		m.lbMode = (m.lbMode - 1 + n) % n
+	case "right", "l", "tab":
+		m.lbMode = (m.lbMode + 1) % n
+	case "t":
+		m.lbTeamOnly = !m.lbTeamOnly
+	default:
+		return m, nil
+	}
+	m.loadLeaderboard()
+	return m, nil
+}
+
+// loadLeaderboard reads the board the leaderboard view shows, for the mode
+// and filter picked there and the current instrument options.
+func (m *model) loadLeaderboard() {
+	m.lbEntries = nil
+	m.lbTeam, m.lbErr = m.stats.Team(m.player)
+	if m.lbErr != nil || m.lbTeamOnly && m.lbTeam == "" {
+		return
+	}
+	filter := ""
+	if m.lbTeamOnly {
+		filter = m.lbTeam
+	}
+	mode := stats.LeaderboardModes[m.lbMode]
+	key := stats.ConfigKey(mode, m.instrType, m.tuning, m.frets, m.chordCount, m.chordDifficulty)
+	m.lbEntries, m.lbErr = m.stats.Leaderboard(key, filter, 10)
+}
+
 func (m *model) cycleInstrument(dir int) {
 	instruments := []string{"guitar", "bass", "ukulele"}
 	for i, name := range instruments {
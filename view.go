@@ -4,9 +4,11 @@ import (
 	"fmt"
 	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/funkymcb/fremorizer/game"
 	"github.com/funkymcb/fremorizer/instrument"
+	"github.com/funkymcb/fremorizer/stats"
 )
 
 func (m model) View() string {
@@ -18,7 +20,15 @@ func (m model) View() string {
 	case stateOptionsTuning:
 		return m.viewTuning()
 	case statePlaying:
-		return m.viewPlaying()
+		v := m.viewPlaying()
+		if m.racing {
+			v += "\n\n" + m.viewRaceBoard()
+		}
+		return v + m.viewWatched()
+	case stateRaceLobby:
+		return m.viewRaceLobby()
+	case stateLeaderboard:
+		return m.viewLeaderboard()
 	}
 	return ""
 }
@@ -34,6 +44,8 @@ func (m model) viewModeSelect() string {
 		"3. Identify chord notes (CAGED system)",
 		"4. Free learning (explore the fretboard)",
 		"5. Simple random note list",
+		"6. Daily challenge (same notes for everyone today)",
+		"7. Race other players (SSH)",
 	}
 	for i, mode := range modes {
 		if i == m.modeCursor {
@@ -51,10 +63,35 @@ func (m model) viewModeSelect() string {
 			sb.WriteString(m.styles.hint.Render(m.feedback) + "\n\n")
 		}
 	}
-	sb.WriteString(m.styles.hint.Render("↑/↓: navigate  Enter: select  o: options  q: quit"))
+	footer := "↑/↓: navigate  Enter: select  o: options  q: quit"
+	if m.stats != nil {
+		footer = "↑/↓: navigate  Enter: select  o: options  l: leaderboard  q: quit"
+	}
+	sb.WriteString(m.styles.hint.Render(footer))
+	switch {
+	case m.share != nil:
+		sb.WriteString("\n" + m.styles.hint.Render(fmt.Sprintf(
+			"Sharing, code %s — a teacher can watch with: ssh -t -p 2222 <host> watch %s  s: stop sharing",
+			m.share.code, m.share.code)))
+		sb.WriteString(m.viewWatched())
+	case m.shares != nil:
+		sb.WriteString("\n" + m.styles.hint.Render("s: share this session with a teacher"))
+	}
 	return sb.String()
 }
 
+// viewWatched tells the student that a teacher is watching.
+func (m model) viewWatched() string {
+	if m.share == nil {
+		return ""
+	}
+	n := m.share.watcherCount()
+	if n == 0 {
+		return ""
+	}
+	return "\n" + m.styles.selected.Render(fmt.Sprintf("● Being watched by %d teacher(s)", n))
+}
+
 func (m model) viewOptions() string {
 	var sb strings.Builder
 	sb.WriteString(m.styles.title.Render("Options") + "\n\n")
@@ -72,6 +109,9 @@ func (m model) viewOptions() string {
 		fmt.Sprintf("Chord mode:      %s", chordDifficultyLabel(m.chordDifficulty)),
 		fmt.Sprintf("Chord count:     %d  (range: 1-99)", m.chordCount),
 		fmt.Sprintf("Note list:       %s", noteListAccidentalsLabel(m.noteListAccidentals)),
+		fmt.Sprintf("Fretboard:       %s", fretboardLayoutLabel(m.fretboardLayout)),
+		fmt.Sprintf("Handedness:      %s", handednessLabel(m.leftHanded)),
+		fmt.Sprintf("String order:    %s", stringOrderLabel(m.lowStringOnTop)),
 		"Back",
 	}
 
@@ -116,10 +156,113 @@ func (m model) viewTuning() string {
 	return sb.String()
 }
 
+// boardOpts returns how the active game's fretboard is drawn, before it is
+// fitted to the terminal.
+func (m model) boardOpts() instrument.RenderOpts {
+	opts := instrument.RenderOpts{
+		Blink:       m.blink,
+		Renderer:    m.styles.renderer,
+		LeftHanded:  m.leftHanded,
+		FlipStrings: m.lowStringOnTop,
+	}
+	switch g := m.activeGame.(type) {
+	case *game.FretSetGameImpl:
+		opts.FretSetMode = true
+		opts.FretSetStart, opts.FretSetEnd = g.GetFretSetBounds()
+		opts.CursorString, opts.CursorFret = g.GetCursor()
+	case *game.ChordsGame:
+		opts.ChordMode = true
+		opts.HideIntervals = g.Difficulty() == "medium"
+		if g.IsMarking() {
+			opts.ShowCursor = true
+			opts.CursorString, opts.CursorFret = g.GetCursor()
+		}
+	case *game.FreeLearningGame:
+		opts.ShowCursor = true
+		opts.CursorString, opts.CursorFret = g.GetCursor()
+	}
+	return opts
+}
+
+// renderOpts returns how the active game's fretboard is drawn: in the fitted
+// layout, with the window moved to the cursor or the question.
+func (m model) renderOpts() instrument.RenderOpts {
+	opts := m.boardOpts()
+	if m.activeGame != nil && m.layout.frets > 0 {
+		opts.Vertical, opts.Compact = m.layout.vertical, m.layout.compact
+		opts.FromFret, opts.ToFret = instrument.Window(m.activeGame.GetInstrument(), opts, m.layout.frets)
+	}
+	return opts
+}
+
+// fretboardFit is a fretboard layout that fits the terminal.
+type fretboardFit struct {
+	vertical, compact bool
+	frets             int // frets shown; 0 = not fitted
+}
+
+// chromeLines returns how many lines the game view prints at most besides
+// the fretboard, counted from viewPlaying and the views it calls, plus the
+// race board and the watched line of View.
+func (m model) chromeLines() int {
+	var n int
+	switch m.activeGame.(type) {
+	case *game.FretSetGameImpl:
+		n = 12 // note, feedback, hint, 2 progress bars, time, keys and the blank lines between
+	case *game.ChordsGame:
+		n = 10 // chord, prompt, hint, feedback, progress, time, keys and the blank lines between
+	case *game.FreeLearningGame:
+		n = 4 // message and 2 lines of keys
+	default:
+		n = 9 // input, feedback, daily title, progress, time, keys and the blank lines between
+	}
+	if m.racing {
+		n += 2 + strings.Count(m.viewRaceBoard(), "\n")
+	}
+	if m.share != nil {
+		n++
+	}
+	return n
+}
+
+// fitLayout lays the active game's fretboard out for the terminal. It tries
+// the layouts allowed by the Fretboard option in turn, horizontal before
+// vertical and normal before compact cells, and takes the first that fits
+// whole. If none does, it takes the one showing the most frets. It runs when
+// a game starts, which also picks up changed options, and when the terminal
+// is resized; renderOpts then only moves the window.
+func (m *model) fitLayout() {
+	m.layout = fretboardFit{}
+	if m.activeGame == nil {
+		return
+	}
+	inst, opts := m.activeGame.GetInstrument(), m.boardOpts()
+	height := 0
+	if m.height > 0 {
+		height = max(m.height-m.chromeLines(), 1)
+	}
+	for _, vertical := range []bool{false, true} {
+		if vertical && m.fretboardLayout == "horizontal" || !vertical && m.fretboardLayout == "vertical" {
+			continue
+		}
+		for _, compact := range []bool{false, true} {
+			opts.Vertical, opts.Compact = vertical, compact
+			fit, whole := instrument.Fit(inst, opts, m.width, height)
+			if n := fit.ToFret - fit.FromFret + 1; n > m.layout.frets {
+				m.layout = fretboardFit{vertical, compact, n}
+			}
+			if whole {
+				m.layout = fretboardFit{vertical, compact, inst.Frets}
+				return
+			}
+		}
+	}
+}
+
 func (m model) viewPlaying() string {
 	var sb strings.Builder
 
-	opts := instrument.RenderOpts{Blink: m.blink, Renderer: m.styles.renderer}
+	opts := m.renderOpts()
 
 	if cgGame, ok := m.activeGame.(*game.ChordsGame); ok {
 		return m.viewChordsMode(cgGame, opts)
@@ -134,14 +277,6 @@ func (m model) viewPlaying() string {
 	}
 
 	if fsGame, ok := m.activeGame.(*game.FretSetGameImpl); ok {
-		start, end := fsGame.GetFretSetBounds()
-		cs, cf := fsGame.GetCursor()
-		opts.FretSetMode = true
-		opts.FretSetStart = start
-		opts.FretSetEnd = end
-		opts.CursorString = cs
-		opts.CursorFret = cf
-
 		sb.WriteString(instrument.Render(fsGame.GetInstrument(), opts))
 		sb.WriteString(fmt.Sprintf("\nNote to find: %s\n\n",
 			m.styles.title.Render(fsGame.GetTargetNote())))
@@ -176,6 +311,9 @@ func (m model) viewPlaying() string {
 			sb.WriteString("Find the note!\n\n")
 		}
 		if snGame, ok := m.activeGame.(*game.SingleNoteGame); ok {
+			if m.daily {
+				sb.WriteString(m.styles.title.Render("Daily challenge "+m.dailyDate.Format("2006-01-02")) + "\n")
+			}
 			correct, total := snGame.Progress()
 			sb.WriteString(m.renderProgressBar(correct, total, 30) + "\n")
 			sb.WriteString(m.styles.hint.Render("Time: "+formatDuration(time.Since(m.gameStartTime))) + "\n\n")
@@ -189,17 +327,6 @@ func (m model) viewPlaying() string {
 func (m model) viewChordsMode(cg *game.ChordsGame, opts instrument.RenderOpts) string {
 	var sb strings.Builder
 
-	opts.ChordMode = true
-	if cg.Difficulty() == "medium" {
-		opts.HideIntervals = true
-	}
-	if cg.IsMarking() {
-		cs, cf := cg.GetCursor()
-		opts.ShowCursor = true
-		opts.CursorString = cs
-		opts.CursorFret = cf
-	}
-
 	sb.WriteString(instrument.Render(cg.GetInstrument(), opts))
 	sb.WriteString("\n")
 
@@ -253,11 +380,6 @@ func (m model) viewChordsMode(cg *game.ChordsGame, opts instrument.RenderOpts) s
 func (m model) viewFreeLearningMode(flGame *game.FreeLearningGame, opts instrument.RenderOpts) string {
 	var sb strings.Builder
 
-	cs, cf := flGame.GetCursor()
-	opts.ShowCursor = true
-	opts.CursorString = cs
-	opts.CursorFret = cf
-
 	sb.WriteString(instrument.Render(flGame.GetInstrument(), opts))
 	sb.WriteString("\n")
 
@@ -297,8 +419,138 @@ func (m model) renderProgressBar(correct, total, width int) string {
 	if total == 0 {
 		return ""
 	}
+	return "Progress: " + m.renderBar(correct, total, width)
+}
+
+func (m model) renderBar(correct, total, width int) string {
+	if total == 0 {
+		return "[" + strings.Repeat("░", width) + "]"
+	}
 	filled := correct * width / total
 	pct := correct * 100 / total
 	bar := m.styles.success.Render(strings.Repeat("█", filled)) + strings.Repeat("░", width-filled)
-	return fmt.Sprintf("Progress: [%s] %d%% (%d/%d)", bar, pct, correct, total)
+	return fmt.Sprintf("[%s] %d%% (%d/%d)", bar, pct, correct, total)
+}
+
+func (m model) viewRaceLobby() string {
+	var sb strings.Builder
+	sb.WriteString(m.styles.title.Render("Race Lobby") + "\n\n")
+
+	board := m.raceBoard
+	sb.WriteString(fmt.Sprintf("Mode: %s\n", raceModeLabel(board.mode)))
+	if board.running {
+		sb.WriteString(m.styles.hint.Render("A race is running — the next one starts when everyone has finished.") + "\n")
+	}
+	sb.WriteString("\n" + m.viewRaceBoard() + "\n")
+
+	if m.feedback != "" {
+		if m.feedbackOK {
+			sb.WriteString(m.styles.result.Render(m.feedback) + "\n\n")
+		} else {
+			sb.WriteString(m.styles.errStyle.Render(m.feedback) + "\n\n")
+		}
+	}
+	sb.WriteString(m.styles.hint.Render(fmt.Sprintf(
+		"Enter: start race (%s, %d frets)  m: change mode  Esc: leave",
+		m.instrType, m.frets)))
+	return sb.String()
+}
+
+// viewRaceBoard renders the live scoreboard of the lobby.
+func (m model) viewRaceBoard() string {
+	var sb strings.Builder
+	sb.WriteString(m.styles.title.Render("Scoreboard") + "\n")
+	nameWidth := 4
+	for _, e := range m.raceBoard.entries {
+		nameWidth = max(nameWidth, utf8.RuneCountInString(displayName(e.name)))
+	}
+	for i, e := range m.raceBoard.entries {
+		name := fmt.Sprintf("%-*s", nameWidth, displayName(e.name))
+		if e.id == m.race.id {
+			name = m.styles.selected.Render(name)
+		}
+		var status string
+		switch {
+		case e.finished > 0:
+			status = m.styles.success.Render(fmt.Sprintf("#%d  %s", i+1, formatDuration(e.finished)))
+		case e.racing:
+			status = m.renderBar(e.correct, e.total, 20)
+		default:
+			status = m.styles.hint.Render("waiting")
+		}
+		sb.WriteString(fmt.Sprintf("  %s  %s\n", name, status))
+	}
+	return sb.String()
+}
+
+func raceModeLabel(mode string) string {
+	switch mode {
+	case "fretset":
+		return "find notes in a set of 3 frets"
+	default:
+		return "guess a random note"
+	}
+}
+
+// viewLeaderboard shows the ranked board for the current instrument options.
+func (m model) viewLeaderboard() string {
+	var sb strings.Builder
+	mode := stats.LeaderboardModes[m.lbMode]
+	sb.WriteString(m.styles.title.Render("Leaderboard") + "\n\n")
+	sb.WriteString(fmt.Sprintf("Mode: %s\n", leaderboardModeLabel(mode)))
+	setup := fmt.Sprintf("Setup: %s, %s, %d frets", m.instrType, strings.Join(m.tuning, "-"), m.frets)
+	if mode == "chords" {
+		setup += fmt.Sprintf(", %d chords, %s", m.chordCount, m.chordDifficulty)
+	}
+	sb.WriteString(setup + "\n")
+
+	if m.lbTeamOnly {
+		if m.lbTeam == "" {
+			sb.WriteString(m.styles.hint.Render("You are not in a team — set one with: ssh -p 2222 <host> team NAME") + "\n")
+		} else {
+			sb.WriteString(fmt.Sprintf("Team: %s\n", displayName(m.lbTeam)))
+		}
+	}
+	sb.WriteString("\n")
+
+	entries := m.lbEntries
+	switch {
+	case m.lbErr != nil:
+		sb.WriteString(m.styles.errStyle.Render(fmt.Sprintf("Error: %v", m.lbErr)) + "\n")
+	case len(entries) == 0:
+		sb.WriteString(m.styles.hint.Render("  No results for this setup yet.") + "\n")
+	}
+
+	nameWidth := 6
+	for _, e := range entries {
+		nameWidth = max(nameWidth, utf8.RuneCountInString(displayName(e.Player)))
+	}
+	for _, e := range entries {
+		name := fmt.Sprintf("%-*s", nameWidth, displayName(e.Player))
+		if e.PlayerID == m.player {
+			name = m.styles.selected.Render(name)
+		}
+		line := fmt.Sprintf("  %2d. %s  %8s  %5.1f%%", e.Rank, name,
+			formatDuration(secondsToDuration(e.TimeSeconds)), e.Accuracy*100)
+		if e.Team != "" {
+			line += "  " + m.styles.hint.Render("["+displayName(e.Team)+"]")
+		}
+		sb.WriteString(line + "\n")
+	}
+
+	scope := "t: team only"
+	if m.lbTeamOnly {
+		scope = "t: everyone"
+	}
+	sb.WriteString("\n" + m.styles.hint.Render("←/→: switch mode  "+scope+"  Esc: back  (change the setup under options)"))
+	return sb.String()
+}
+
+func leaderboardModeLabel(mode string) string {
+	switch mode {
+	case "chords":
+		return "identify chord notes"
+	default:
+		return "guess a random note"
+	}
 }
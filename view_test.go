@@ -0,0 +1,68 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/funkymcb/fremorizer/game"
+	"github.com/funkymcb/fremorizer/instrument"
+)
+
+// playing starts mode on a 24-fret guitar in a width×height terminal.
+func playing(t *testing.T, mode, layout string, width, height int) model {
+	t.Helper()
+	m := initialModel(lipgloss.DefaultRenderer())
+	m.frets = 24
+	m.fretboardLayout = layout
+	next, _ := m.Update(tea.WindowSizeMsg{Width: width, Height: height})
+	next, _ = next.(model).startGame(mode)
+	m = next.(model)
+	if m.state != statePlaying {
+		t.Fatalf("%s: not playing: %s", mode, m.feedback)
+	}
+	return m
+}
+
+func TestGameViewsFitTerminal(t *testing.T) {
+	for _, mode := range []string{"single", "fretset", "chords", "freelearning"} {
+		for _, size := range [][2]int{{100, 30}, {60, 50}, {200, 20}} {
+			m := playing(t, mode, "auto", size[0], size[1])
+			m.feedback = "Wrong!"
+			view := m.View()
+			if lines := strings.Count(view, "\n") + 1; lines > size[1] {
+				t.Errorf("%s in %dx%d: %d lines (layout %+v):\n%s", mode, size[0], size[1], lines, m.layout, view)
+			}
+			// The header and the hints may wrap, the board may not.
+			_, board, _ := strings.Cut(instrument.Render(m.activeGame.GetInstrument(), m.renderOpts()), "\n")
+			if w := lipgloss.Width(board); w > size[0] {
+				t.Errorf("%s in %dx%d: board is %d columns (layout %+v)", mode, size[0], size[1], w, m.layout)
+			}
+		}
+	}
+}
+
+func TestLayoutFollowsCursor(t *testing.T) {
+	m := playing(t, "freelearning", "horizontal", 2+8*6+1, 40)
+	// Compact cells show 12 frets instead of 8.
+	if m.layout != (fretboardFit{compact: true, frets: 12}) {
+		t.Fatalf("layout = %+v, want 12 compact horizontal frets", m.layout)
+	}
+	fl := m.activeGame.(*game.FreeLearningGame)
+	for range 20 {
+		fl.MoveCursor(0, 1)
+	}
+	_, fret := fl.GetCursor()
+	opts := m.renderOpts()
+	if fret < opts.FromFret || fret > opts.ToFret || opts.ToFret-opts.FromFret+1 != 12 {
+		t.Errorf("cursor at fret %d, window %d-%d", fret, opts.FromFret, opts.ToFret)
+	}
+
+	// A resize fits the layout again.
+	next, _ := m.Update(tea.WindowSizeMsg{Width: 200, Height: 40})
+	if got := next.(model).layout; got != (fretboardFit{frets: 24}) {
+		t.Errorf("after resize: layout = %+v, want the whole board", got)
+	}
+}